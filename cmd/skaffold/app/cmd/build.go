@@ -35,6 +35,7 @@ import (
 var (
 	quietFlag       bool
 	buildFormatFlag = flags.NewTemplateFlag("{{json .}}", flags.BuildOutput{})
+	buildOutputFlag string
 )
 
 // For testing
@@ -51,6 +52,7 @@ func NewCmdBuild(out io.Writer) *cobra.Command {
 			f.StringSliceVarP(&opts.TargetImages, "build-image", "b", nil, "Choose which artifacts to build. Artifacts with image names that contain the expression will be built only. Default is to build sources for all artifacts")
 			f.BoolVarP(&quietFlag, "quiet", "q", false, "Suppress the build output and print image built on success. See --output to format output.")
 			f.VarP(buildFormatFlag, "output", "o", "Used in conjuction with --quiet flag. "+buildFormatFlag.Usage())
+			f.StringVar(&buildOutputFlag, "file-output", "", "Filename to write the built images to, in a versioned json format that can be read by skaffold deploy --build-artifacts")
 		}).
 		NoArgs(cancelWithCtrlC(context.Background(), doBuild))
 }
@@ -71,6 +73,12 @@ func doBuild(ctx context.Context, out io.Writer) error {
 		return err
 	}
 
+	if buildOutputFlag != "" {
+		if err := flags.WriteBuildOutput(buildOutputFlag, bRes); err != nil {
+			return errors.Wrap(err, "writing build output")
+		}
+	}
+
 	if quietFlag {
 		cmdOut := flags.BuildOutput{Builds: bRes}
 		if err := buildFormatFlag.Template().Execute(out, cmdOut); err != nil {
@@ -88,7 +96,11 @@ func createRunnerAndBuild(ctx context.Context, buildOut io.Writer) ([]build.Arti
 	}
 	defer runner.RPCServerShutdown()
 
-	return runner.BuildAndTest(ctx, buildOut, targetArtifacts(opts, config))
+	bRes, err := runner.BuildAndTest(ctx, buildOut, targetArtifacts(opts, config))
+	if err != nil {
+		return nil, err
+	}
+	return build.WithMetadata(bRes, config.Build), nil
 }
 
 func targetArtifacts(opts *config.SkaffoldOptions, cfg *latest.SkaffoldConfig) []*latest.Artifact {
@@ -119,3 +119,26 @@ func TestRunBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestFileOutputFlag(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		builds := []build.Artifact{{
+			ImageName: "gcr.io/skaffold/example",
+			Tag:       "gcr.io/skaffold/example:test@sha256:abac",
+			Digest:    "sha256:abac",
+		}}
+		file := t.NewTempDir().Path("build.out")
+		t.Override(&buildOutputFlag, file)
+		t.Override(&createRunnerAndBuildFunc, func(context.Context, io.Writer) ([]build.Artifact, error) {
+			return builds, nil
+		})
+
+		err := doBuild(context.Background(), ioutil.Discard)
+		t.CheckError(false, err)
+
+		var deployFlag flags.BuildOutputFileFlag
+		err = deployFlag.Set(file)
+
+		t.CheckErrorAndDeepEqual(false, err, builds, deployFlag.BuildArtifacts())
+	})
+}
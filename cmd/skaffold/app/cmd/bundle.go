@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/bundle"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var bundleDir string
+
+// NewCmdBundle describes the CLI commands to deploy to disconnected clusters.
+func NewCmdBundle(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "A set of commands to build once and deploy to disconnected clusters.",
+	}
+
+	cmd.AddCommand(NewCmdBundleCreate(out))
+	cmd.AddCommand(NewCmdBundleDeploy(out))
+	return cmd
+}
+
+// NewCmdBundleCreate describes the CLI command to create a bundle.
+func NewCmdBundleCreate(out io.Writer) *cobra.Command {
+	return NewCmd(out, "create").
+		WithDescription("Builds the artifacts and saves them, with the deployment files, to a bundle directory").
+		WithFlags(func(f *pflag.FlagSet) {
+			AddFlags(f, "build")
+			f.StringVar(&bundleDir, "bundle-dir", "bundle", "Directory of the bundle")
+		}).
+		NoArgs(cancelWithCtrlC(context.Background(), doBundleCreate))
+}
+
+// NewCmdBundleDeploy describes the CLI command to deploy a bundle.
+func NewCmdBundleDeploy(out io.Writer) *cobra.Command {
+	return NewCmd(out, "deploy").
+		WithDescription("Loads the images of a bundle and deploys them").
+		WithFlags(func(f *pflag.FlagSet) {
+			// The configuration is the one saved in the bundle, with the profiles already applied.
+			deployFlags := pflag.NewFlagSet("deploy", pflag.ContinueOnError)
+			AddFlags(deployFlags, "deploy")
+			deployFlags.VisitAll(func(flag *pflag.Flag) {
+				if flag.Name != "filename" && flag.Name != "profile" {
+					f.AddFlag(flag)
+				}
+			})
+			f.StringVar(&bundleDir, "bundle-dir", "bundle", "Directory of the bundle")
+		}).
+		NoArgs(cancelWithCtrlC(context.Background(), doBundleDeploy))
+}
+
+func doBundleCreate(ctx context.Context, out io.Writer) error {
+	dir, err := filepath.Abs(bundleDir)
+	if err != nil {
+		return errors.Wrap(err, "finding bundle directory")
+	}
+
+	return withRunner(func(r *runner.SkaffoldRunner, config *latest.SkaffoldConfig) error {
+		return r.CreateBundle(ctx, out, dir, config)
+	})
+}
+
+func doBundleDeploy(ctx context.Context, out io.Writer) error {
+	dir, err := filepath.Abs(bundleDir)
+	if err != nil {
+		return errors.Wrap(err, "finding bundle directory")
+	}
+
+	// The deployment files are relative to the project directory of the bundle.
+	if err := os.Chdir(bundle.ProjectDir(dir)); err != nil {
+		return errors.Wrap(err, "reading bundle")
+	}
+	opts.ConfigurationFile = bundle.ConfigFile
+
+	return withRunner(func(r *runner.SkaffoldRunner, _ *latest.SkaffoldConfig) error {
+		return r.DeployBundle(ctx, out, dir)
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"io"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -35,8 +36,13 @@ func NewCmdDev(out io.Writer) *cobra.Command {
 		WithCommonFlags().
 		WithFlags(func(f *pflag.FlagSet) {
 			f.StringVar(&opts.Trigger, "trigger", "notify", "How are changes detected? (polling, manual or notify)")
+			f.BoolVar(&opts.Resume, "resume", false, "Reuse the images of the previous dev session for the artifacts that didn't change. Implies --no-prune")
+			f.BoolVar(&opts.Interactive, "interactive", false, "Control the dev loop by typing commands: b to rebuild, a number to rebuild one artifact, d to redeploy, s to sync, l to mute logs, p to pause watching, q to quit")
 			f.StringSliceVarP(&opts.TargetImages, "watch-image", "w", nil, "Choose which artifacts to watch. Artifacts with image names that contain the expression will be watched only. Default is to watch sources for all artifacts")
 			f.IntVarP(&opts.WatchPollInterval, "watch-poll-interval", "i", 1000, "Interval (in ms) between two checks for file changes")
+			f.StringVar(&opts.WatchQuietPeriod, "watch-quiet-period", "", "How long to wait without file changes before starting an iteration, e.g. 500ms (defaults to the watch poll interval)")
+			f.StringVar(&opts.WatchMaxWait, "watch-max-wait", "", "Maximum time to wait for file changes to settle before forcing an iteration, e.g. 5s")
+			f.StringVar(&opts.WatchMinInterval, "watch-min-interval", "", "Minimum time between the start of two iterations, e.g. 10s")
 		}).
 		NoArgs(cancelWithCtrlC(context.Background(), doDev))
 }
@@ -67,6 +73,10 @@ func doDev(ctx context.Context, out io.Writer) error {
 			if err != nil {
 				return errors.Wrap(err, "creating runner")
 			}
+			r.SetConfigLoader(func() (*latest.SkaffoldConfig, error) {
+				config, _, err := effectiveConfig(opts)
+				return config, err
+			})
 
 			err = r.Dev(ctx, out, config.Build.Artifacts)
 			if r.HasDeployed() {
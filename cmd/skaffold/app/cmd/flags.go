@@ -180,6 +180,38 @@ var FlagRegistry = []Flag{
 		FlagAddMethod: "BoolVar",
 		DefinedOn:     []string{"dev", "run", "debug"},
 	},
+	{
+		Name:          "native-apply",
+		Usage:         "Apply and delete manifests through the Kubernetes API instead of the kubectl binary",
+		Value:         &opts.NativeApply,
+		DefValue:      false,
+		FlagAddMethod: "BoolVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy", "delete"},
+	},
+	{
+		Name:          "ephemeral-namespace",
+		Usage:         "Deploy to a namespace created for the session, and deleted on cleanup",
+		Value:         &opts.EphemeralNamespace,
+		DefValue:      false,
+		FlagAddMethod: "BoolVar",
+		DefinedOn:     []string{"dev", "run", "debug"},
+	},
+	{
+		Name:          "ephemeral-namespace-ttl",
+		Usage:         "Duration after which `skaffold janitor` deletes an ephemeral namespace that wasn't cleaned up",
+		Value:         &opts.EphemeralNamespaceTTL,
+		DefValue:      "24h",
+		FlagAddMethod: "StringVar",
+		DefinedOn:     []string{"dev", "run", "debug"},
+	},
+	{
+		Name:          "prune-dry-run",
+		Usage:         "Only print the resources removed from the manifests, instead of deleting them from the cluster",
+		Value:         &opts.PruneDryRun,
+		DefValue:      false,
+		FlagAddMethod: "BoolVar",
+		DefinedOn:     []string{"dev", "run", "debug", "deploy"},
+	},
 	{
 		Name:          "skip-tests",
 		Usage:         "Whether to skip the tests after building",
@@ -188,6 +220,14 @@ var FlagRegistry = []Flag{
 		FlagAddMethod: "BoolVar",
 		DefinedOn:     []string{"dev", "run", "debug", "build"},
 	},
+	{
+		Name:          "test-report-dir",
+		Usage:         "Directory in which to write JUnit and JSON reports of the tests",
+		Value:         &opts.TestReportDir,
+		DefValue:      "",
+		FlagAddMethod: "StringVar",
+		DefinedOn:     []string{"dev", "run", "debug", "build"},
+	},
 	{
 		Name:          "cleanup",
 		Usage:         "Delete deployments after dev or debug mode is interrupted",
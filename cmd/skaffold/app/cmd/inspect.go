@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/inspect"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	inspectOutput  string
+	inspectQuery   string
+	inspectCommand string
+)
+
+// NewCmdInspect describes the CLI command to print the effective configuration.
+func NewCmdInspect(out io.Writer) *cobra.Command {
+	return NewCmd(out, "inspect").
+		WithDescription("Print the effective configuration, once profiles and default values are applied").
+		WithFlags(func(f *pflag.FlagSet) {
+			f.StringVarP(&opts.ConfigurationFile, "filename", "f", "skaffold.yaml", "Filename or URL to the pipeline file")
+			f.StringSliceVarP(&opts.Profiles, "profile", "p", nil, "Activate profiles by name")
+			f.StringVarP(&opts.Namespace, "namespace", "n", "", "Run as if deploying in the specified namespace")
+			f.StringVarP(&inspectOutput, "output", "o", "yaml", "Output format: yaml or json")
+			f.StringVarP(&inspectQuery, "query", "q", "", "Only print the part of the configuration at this path. For example: build.artifacts[*].image")
+			f.StringVar(&inspectCommand, "command", "", "Activate profiles as if running this skaffold command. For example: dev")
+		}).
+		NoArgs(doInspect)
+}
+
+func doInspect(out io.Writer) error {
+	if inspectCommand != "" {
+		opts.Command = inspectCommand
+	}
+
+	config, profiles, err := effectiveConfig(opts)
+	if err != nil {
+		if profiles != nil {
+			// Show the profiles that were applied before the failure.
+			report, _ := inspect.NewReport(profiles, nil)
+			inspect.Print(out, report, inspectOutput)
+		}
+		return err
+	}
+
+	report, err := inspect.NewReport(profiles, config)
+	if err != nil {
+		return err
+	}
+
+	if inspectQuery == "" {
+		return inspect.Print(out, report, inspectOutput)
+	}
+
+	result, err := inspect.Query(report.Config, inspectQuery)
+	if err != nil {
+		return errors.Wrap(err, "querying configuration")
+	}
+
+	return inspect.Print(out, result, inspectOutput)
+}
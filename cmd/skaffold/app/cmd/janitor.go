@@ -0,0 +1,36 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdJanitor describes the CLI command to delete abandoned ephemeral namespaces.
+func NewCmdJanitor(out io.Writer) *cobra.Command {
+	return NewCmd(out, "janitor").
+		WithDescription("Delete the ephemeral namespaces that outlived their ttl").
+		NoArgs(doJanitor)
+}
+
+func doJanitor(out io.Writer) error {
+	return kubernetes.ReapEphemeralNamespaces(out, time.Now())
+}
@@ -45,6 +45,23 @@ func withRunner(action func(*runner.SkaffoldRunner, *latest.SkaffoldConfig) erro
 
 // newRunner creates a SkaffoldRunner and returns the SkaffoldConfig associated with it.
 func newRunner(opts *config.SkaffoldOptions) (*runner.SkaffoldRunner, *latest.SkaffoldConfig, error) {
+	config, _, err := effectiveConfig(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runner, err := runner.NewForConfig(opts, config)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating runner")
+	}
+
+	return runner, config, nil
+}
+
+// effectiveConfig parses the pipeline file and returns the configuration obtained
+// once profiles, default values and the default repo are applied. The profiles that
+// were applied are returned too, even if one of them couldn't be applied.
+func effectiveConfig(opts *config.SkaffoldOptions) (*latest.SkaffoldConfig, []schema.ActivatedProfile, error) {
 	parsed, err := schema.ParseConfig(opts.ConfigurationFile, true)
 	if err != nil {
 		// If the error is NOT that the file doesn't exist, then we warn the user
@@ -59,31 +76,27 @@ func newRunner(opts *config.SkaffoldOptions) (*runner.SkaffoldRunner, *latest.Sk
 
 	config := parsed.(*latest.SkaffoldConfig)
 
-	if err = schema.ApplyProfiles(config, opts); err != nil {
-		return nil, nil, errors.Wrap(err, "applying profiles")
+	profiles, err := schema.ApplyProfilesWithReport(config, opts)
+	if err != nil {
+		return nil, profiles, errors.Wrap(err, "applying profiles")
 	}
 
 	if err := defaults.Set(config); err != nil {
-		return nil, nil, errors.Wrap(err, "setting default values")
+		return nil, profiles, errors.Wrap(err, "setting default values")
 	}
 
 	if err := validation.Process(config); err != nil {
-		return nil, nil, errors.Wrap(err, "invalid skaffold config")
+		return nil, profiles, errors.Wrap(err, "invalid skaffold config")
 	}
 
 	defaultRepo, err := configutil.GetDefaultRepo(opts.DefaultRepo)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "getting default repo")
+		return nil, profiles, errors.Wrap(err, "getting default repo")
 	}
 
 	applyDefaultRepoSubstitution(config, defaultRepo)
 
-	runner, err := runner.NewForConfig(opts, config)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "creating runner")
-	}
-
-	return runner, config, nil
+	return config, profiles, nil
 }
 
 func warnIfUpdateIsAvailable() {
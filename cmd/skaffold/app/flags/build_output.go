@@ -32,9 +32,14 @@ type BuildOutputFileFlag struct {
 	buildOutput BuildOutput
 }
 
+// BuildOutputVersion is the version of the BuildOutput format written by `skaffold build --file-output`.
+// Files without a version are read as this version.
+const BuildOutputVersion = "skaffold/build/v1"
+
 // BuildOutput is the output of `skaffold build`.
 type BuildOutput struct {
-	Builds []build.Artifact `json:"builds"`
+	Version string           `json:"version,omitempty"`
+	Builds  []build.Artifact `json:"builds"`
 }
 
 func (t *BuildOutputFileFlag) String() string {
@@ -43,7 +48,7 @@ func (t *BuildOutputFileFlag) String() string {
 
 // Usage Implements Usage() method for pflag interface
 func (t *BuildOutputFileFlag) Usage() string {
-	return "Input file with json encoded BuildOutput e.g.`skaffold build -q -o >build.out`"
+	return "Input file with json encoded BuildOutput e.g.`skaffold build --file-output=build.out`"
 }
 
 // Set Implements Set() method for pflag interface
@@ -87,5 +92,21 @@ func ParseBuildOutput(b []byte) (*BuildOutput, error) {
 	if err := json.Unmarshal(b, buildOutput); err != nil {
 		return nil, err
 	}
+	if buildOutput.Version != "" && buildOutput.Version != BuildOutputVersion {
+		return nil, fmt.Errorf("unsupported build output version %q, expected %q", buildOutput.Version, BuildOutputVersion)
+	}
 	return buildOutput, nil
 }
+
+// WriteBuildOutput writes the result of a build to a file, in the current version of the BuildOutput format.
+func WriteBuildOutput(filename string, builds []build.Artifact) error {
+	buf, err := json.MarshalIndent(BuildOutput{
+		Version: BuildOutputVersion,
+		Builds:  builds,
+	}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling build output")
+	}
+
+	return ioutil.WriteFile(filename, append(buf, '\n'), 0644)
+}
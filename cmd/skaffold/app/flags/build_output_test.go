@@ -64,6 +64,34 @@ func TestBuildOutputSet(t *testing.T) {
 				}},
 			},
 		},
+		{
+			description: "set reads a versioned build output",
+			buildOutputBytes: []byte(`{
+"version": "skaffold/build/v1",
+"builds": [{
+	"imageName": "gcr.io/k8s/test1",
+	"tag": "gcr.io/k8s/test1:v1@sha256:foo",
+	"digest": "sha256:foo",
+	"builder": {"type": "local", "artifactType": "docker"}
+  }]
+}`),
+			setValue: "test.in",
+			expectedBuildOutput: BuildOutput{
+				Version: "skaffold/build/v1",
+				Builds: []build.Artifact{{
+					ImageName: "gcr.io/k8s/test1",
+					Tag:       "gcr.io/k8s/test1:v1@sha256:foo",
+					Digest:    "sha256:foo",
+					Builder:   &build.Metadata{Type: "local", ArtifactType: "docker"},
+				}},
+			},
+		},
+		{
+			description:      "set errors with an unsupported version",
+			buildOutputBytes: []byte(`{"version": "skaffold/build/v2", "builds": []}`),
+			setValue:         "test.in",
+			shouldErr:        true,
+		},
 		{
 			description:      "set errors with in-correct build output format",
 			buildOutputBytes: []byte{},
@@ -101,3 +129,24 @@ func TestBuildOutputString(t *testing.T) {
 	testutil.CheckDeepEqual(t, "test.in", flag.String())
 	testutil.CheckDeepEqual(t, "*flags.BuildOutputFileFlag", flag.Type())
 }
+
+func TestWriteBuildOutput(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		builds := []build.Artifact{{
+			ImageName: "gcr.io/k8s/test1",
+			Tag:       "gcr.io/k8s/test1:v1@sha256:foo",
+			Digest:    "sha256:foo",
+			Builder:   &build.Metadata{Type: "local", ArtifactType: "docker"},
+		}}
+		file := t.NewTempDir().Path("build.out")
+
+		err := WriteBuildOutput(file, builds)
+		t.CheckError(false, err)
+
+		flag := NewBuildOutputFileFlag("")
+		err = flag.Set(file)
+
+		t.CheckErrorAndDeepEqual(false, err, builds, flag.BuildArtifacts())
+		t.CheckDeepEqual(BuildOutputVersion, flag.buildOutput.Version)
+	})
+}
@@ -26,8 +26,18 @@ import (
 
 // Artifact is the result corresponding to each successful build.
 type Artifact struct {
-	ImageName string `json:"imageName"`
-	Tag       string `json:"tag"`
+	ImageName string    `json:"imageName"`
+	Tag       string    `json:"tag"`
+	Digest    string    `json:"digest,omitempty"`
+	Builder   *Metadata `json:"builder,omitempty"`
+}
+
+// Metadata describes how an artifact was built.
+type Metadata struct {
+	// Type is the builder that was used: local, googleCloudBuild or cluster.
+	Type string `json:"type"`
+	// ArtifactType is the type of artifact: docker, bazel, jibMaven, jibGradle, kaniko or custom.
+	ArtifactType string `json:"artifactType"`
 }
 
 // Builder is an interface to the Build API of Skaffold.
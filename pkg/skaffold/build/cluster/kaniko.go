@@ -22,6 +22,7 @@ import (
 	"io"
 	"sort"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/cache"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/cluster/sources"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
@@ -35,15 +36,42 @@ import (
 )
 
 func (b *Builder) runKanikoBuild(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
+	platforms := artifact.KanikoArtifact.Platforms
+	if len(platforms) > 1 {
+		return build.ForPlatforms(ctx, out, tag, platforms, b.insecureRegistries, func(ctx context.Context, out io.Writer, platform, tag string) error {
+			return b.runKanikoPod(ctx, out, artifact, platform, tag)
+		})
+	}
+
+	platform := ""
+	if len(platforms) == 1 {
+		platform = platforms[0]
+	}
+
+	destinations := []string{tag}
+	if artifact.WorkspaceHash != "" {
+		destinations = append(destinations, cache.HashTag(artifact))
+	}
+
+	if err := b.runKanikoPod(ctx, out, artifact, platform, destinations...); err != nil {
+		return "", err
+	}
+
+	return docker.RemoteDigest(tag, b.insecureRegistries)
+}
+
+// runKanikoPod builds an image in a kaniko pod and pushes it to the given destinations.
+// When a platform is given, the pod is scheduled on a node of that platform.
+func (b *Builder) runKanikoPod(ctx context.Context, out io.Writer, artifact *latest.Artifact, platform string, destinations ...string) error {
 	// Prepare context
 	s := sources.Retrieve(b.ClusterDetails, artifact.KanikoArtifact)
 	dependencies, err := b.DependenciesForArtifact(ctx, artifact)
 	if err != nil {
-		return "", errors.Wrapf(err, "getting dependencies for %s", artifact.ImageName)
+		return errors.Wrapf(err, "getting dependencies for %s", artifact.ImageName)
 	}
 	context, err := s.Setup(ctx, out, artifact, util.RandomID(), dependencies)
 	if err != nil {
-		return "", errors.Wrap(err, "setting up build context")
+		return errors.Wrap(err, "setting up build context")
 	}
 	defer s.Cleanup(ctx)
 
@@ -51,9 +79,11 @@ func (b *Builder) runKanikoBuild(ctx context.Context, out io.Writer, artifact *l
 	// Create pod spec
 	args := []string{
 		"--dockerfile", kanikoArtifact.DockerfilePath,
-		"--context", context,
-		"--destination", tag,
-		"-v", logLevel().String()}
+		"--context", context}
+	for _, destination := range destinations {
+		args = append(args, "--destination", destination)
+	}
+	args = append(args, "-v", logLevel().String())
 
 	// TODO: remove since AdditionalFlags will be deprecated (priyawadhwa@)
 	if kanikoArtifact.AdditionalFlags != nil {
@@ -64,22 +94,26 @@ func (b *Builder) runKanikoBuild(ctx context.Context, out io.Writer, artifact *l
 	args = appendTargetIfExists(args, kanikoArtifact.Target)
 	args = appendCacheIfExists(args, kanikoArtifact.Cache)
 
-	if artifact.WorkspaceHash != "" {
-		hashTag := cache.HashTag(artifact)
-		args = append(args, []string{"--destination", hashTag}...)
+	nodeSelector, err := platformNodeSelector(platform)
+	if err != nil {
+		return err
+	}
+	if platform != "" {
+		args = append(args, fmt.Sprintf("--customPlatform=%s", platform))
 	}
 
 	podSpec := s.Pod(args)
+	podSpec.Spec.NodeSelector = nodeSelector
 	// Create pod
 	client, err := kubernetes.GetClientset()
 	if err != nil {
-		return "", errors.Wrap(err, "")
+		return errors.Wrap(err, "")
 	}
 	pods := client.CoreV1().Pods(b.Namespace)
 
 	pod, err := pods.Create(podSpec)
 	if err != nil {
-		return "", errors.Wrap(err, "creating kaniko pod")
+		return errors.Wrap(err, "creating kaniko pod")
 	}
 	defer func() {
 		if err := pods.Delete(pod.Name, &metav1.DeleteOptions{
@@ -90,18 +124,35 @@ func (b *Builder) runKanikoBuild(ctx context.Context, out io.Writer, artifact *l
 	}()
 
 	if err := s.ModifyPod(ctx, pod); err != nil {
-		return "", errors.Wrap(err, "modifying kaniko pod")
+		return errors.Wrap(err, "modifying kaniko pod")
 	}
 
 	waitForLogs := streamLogs(out, pod.Name, pods)
 
 	if err := kubernetes.WaitForPodComplete(ctx, pods, pod.Name, b.timeout); err != nil {
-		return "", errors.Wrap(err, "waiting for pod to complete")
+		return errors.Wrap(err, "waiting for pod to complete")
 	}
 
 	waitForLogs()
 
-	return docker.RemoteDigest(tag, b.insecureRegistries)
+	return nil
+}
+
+// platformNodeSelector selects the nodes that can build for a platform.
+func platformNodeSelector(platform string) (map[string]string, error) {
+	if platform == "" {
+		return nil, nil
+	}
+
+	p, err := docker.ParsePlatform(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"kubernetes.io/os":   p.OS,
+		"kubernetes.io/arch": p.Architecture,
+	}, nil
 }
 
 func appendCacheIfExists(args []string, cache *latest.KanikoCache) []string {
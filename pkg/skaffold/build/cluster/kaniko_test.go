@@ -113,6 +113,33 @@ func TestAppendBuildArgsIfExists(t *testing.T) {
 	}
 }
 
+func TestPlatformNodeSelector(t *testing.T) {
+	tests := []struct {
+		name      string
+		platform  string
+		expected  map[string]string
+		shouldErr bool
+	}{
+		{
+			name: "no platform",
+		}, {
+			name:     "arm64",
+			platform: "linux/arm64/v8",
+			expected: map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/arch": "arm64"},
+		}, {
+			name:      "invalid platform",
+			platform:  "arm64",
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := platformNodeSelector(test.platform)
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, actual)
+		})
+	}
+}
+
 func pointer(a string) *string {
 	return &a
 }
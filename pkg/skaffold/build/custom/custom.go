@@ -92,6 +92,9 @@ func (b *ArtifactBuilder) retrieveEnv(a *latest.Artifact, tag string) ([]string,
 		fmt.Sprintf("%s=%t", constants.PushImage, b.pushImages),
 		fmt.Sprintf("%s=%s", constants.BuildContext, buildContext),
 	}
+	if a.CustomArtifact != nil && len(a.CustomArtifact.Platforms) > 0 {
+		envs = append(envs, fmt.Sprintf("%s=%s", constants.Platform, strings.Join(a.CustomArtifact.Platforms, ",")))
+	}
 	envs = append(envs, b.additionalEnv...)
 	envs = append(envs, util.OSEnviron()...)
 	sort.Strings(envs)
@@ -106,6 +106,18 @@ func TestRetrieveCmd(t *testing.T) {
 			},
 			tag:      "image:tag",
 			expected: expectedCmd("./build.sh", "", []string{"--flag", "--anotherflag"}, []string{"BUILD_CONTEXT=", "IMAGES=image:tag", "PUSH_IMAGE=false"}),
+		}, {
+			description: "artifact with a platform",
+			artifact: &latest.Artifact{
+				ArtifactType: latest.ArtifactType{
+					CustomArtifact: &latest.CustomArtifact{
+						BuildCommand: "./build.sh",
+						Platforms:    []string{"linux/arm64"},
+					},
+				},
+			},
+			tag:      "image:tag-linux-arm64",
+			expected: expectedCmd("./build.sh", "", nil, []string{"BUILD_CONTEXT=", "IMAGES=image:tag-linux-arm64", "PLATFORM=linux/arm64", "PUSH_IMAGE=false"}),
 		},
 	}
 	for _, test := range tests {
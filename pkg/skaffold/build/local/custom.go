@@ -20,6 +20,7 @@ import (
 	"context"
 	"io"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/custom"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
@@ -29,6 +30,22 @@ import (
 func (b *Builder) buildCustom(ctx context.Context, out io.Writer, artifact *latest.Artifact, tag string) (string, error) {
 	customArtifactBuilder := custom.NewArtifactBuilder(b.pushImages, b.localDocker.ExtraEnv())
 
+	if platforms := artifact.CustomArtifact.Platforms; len(platforms) > 1 {
+		if !b.pushImages {
+			return "", errors.New("building for several platforms requires pushing the images")
+		}
+
+		return build.ForPlatforms(ctx, out, tag, platforms, b.insecureRegistries, func(ctx context.Context, out io.Writer, platform, tag string) error {
+			// Run the build command once per platform
+			customArtifact := *artifact.CustomArtifact
+			customArtifact.Platforms = []string{platform}
+			a := *artifact
+			a.CustomArtifact = &customArtifact
+
+			return customArtifactBuilder.Build(ctx, out, &a, tag)
+		})
+	}
+
 	if err := customArtifactBuilder.Build(ctx, out, artifact, tag); err != nil {
 		return "", errors.Wrap(err, "building custom artifact")
 	}
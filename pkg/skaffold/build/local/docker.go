@@ -20,7 +20,9 @@ import (
 	"context"
 	"io"
 	"os/exec"
+	"strings"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
@@ -33,17 +35,26 @@ func (b *Builder) buildDocker(ctx context.Context, out io.Writer, a *latest.Arti
 		return "", errors.Wrap(err, "pulling cache-from images")
 	}
 
-	var (
-		imageID string
-		err     error
-	)
+	if len(a.DockerArtifact.Platforms) > 1 {
+		if !b.pushImages {
+			return "", errors.New("building for several platforms requires pushing the images")
+		}
+
+		return build.ForPlatforms(ctx, out, tag, a.DockerArtifact.Platforms, b.insecureRegistries, func(ctx context.Context, out io.Writer, platform, tag string) error {
+			// Build one platform at a time
+			artifact := *a.DockerArtifact
+			artifact.Platforms = []string{platform}
+
+			if _, err := b.dockerBuild(ctx, out, a.Workspace, &artifact, tag); err != nil {
+				return err
+			}
 
-	if b.cfg.UseDockerCLI || b.cfg.UseBuildkit {
-		imageID, err = b.dockerCLIBuild(ctx, out, a.Workspace, a.ArtifactType.DockerArtifact, tag)
-	} else {
-		imageID, err = b.localDocker.Build(ctx, out, a.Workspace, a.ArtifactType.DockerArtifact, tag)
+			_, err := b.localDocker.Push(ctx, out, tag)
+			return err
+		})
 	}
 
+	imageID, err := b.dockerBuild(ctx, out, a.Workspace, a.DockerArtifact, tag)
 	if err != nil {
 		return "", err
 	}
@@ -55,6 +66,14 @@ func (b *Builder) buildDocker(ctx context.Context, out io.Writer, a *latest.Arti
 	return imageID, nil
 }
 
+func (b *Builder) dockerBuild(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, tag string) (string, error) {
+	if b.cfg.UseDockerCLI || b.useBuildKit(a) {
+		return b.dockerCLIBuild(ctx, out, workspace, a, tag)
+	}
+
+	return b.localDocker.Build(ctx, out, workspace, a, tag)
+}
+
 func (b *Builder) dockerCLIBuild(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, tag string) (string, error) {
 	dockerfilePath, err := docker.NormalizeDockerfilePath(workspace, a.DockerfilePath)
 	if err != nil {
@@ -62,18 +81,33 @@ func (b *Builder) dockerCLIBuild(ctx context.Context, out io.Writer, workspace s
 	}
 
 	args := []string{"build", workspace, "--file", dockerfilePath, "-t", tag}
+	if docker.RequiresBuildx(a) {
+		// Load the image into the daemon, like `docker build` does
+		args = append([]string{"buildx"}, append(args, "--load")...)
+	}
+
 	ba, err := docker.GetBuildArgs(a)
 	if err != nil {
 		return "", errors.Wrap(err, "getting docker build args")
 	}
 	args = append(args, ba...)
 
+	bka, err := docker.GetBuildKitArgs(workspace, a)
+	if err != nil {
+		return "", errors.Wrap(err, "getting BuildKit args")
+	}
+	args = append(args, bka...)
+
+	if len(a.Platforms) > 0 {
+		args = append(args, "--platform", strings.Join(a.Platforms, ","))
+	}
+
 	if b.prune {
 		args = append(args, "--force-rm")
 	}
 
 	cmd := exec.CommandContext(ctx, "docker", args...)
-	if b.cfg.UseBuildkit {
+	if b.useBuildKit(a) {
 		cmd.Env = append(util.OSEnviron(), "DOCKER_BUILDKIT=1")
 	}
 	cmd.Stdout = out
@@ -91,6 +125,12 @@ func (b *Builder) pullCacheFromImages(ctx context.Context, out io.Writer, a *lat
 		return nil
 	}
 
+	// Artifacts that require BuildKit read the cache from the registry.
+	// Other BuildKit builds still use the pulled images.
+	if docker.RequiresBuildKit(a) {
+		return nil
+	}
+
 	for _, image := range a.CacheFrom {
 		imageID, err := b.localDocker.ImageID(ctx, image)
 		if err != nil {
@@ -108,3 +148,7 @@ func (b *Builder) pullCacheFromImages(ctx context.Context, out io.Writer, a *lat
 
 	return nil
 }
+
+func (b *Builder) useBuildKit(a *latest.DockerArtifact) bool {
+	return b.cfg.UseBuildkit || docker.RequiresBuildKit(a)
+}
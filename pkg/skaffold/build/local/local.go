@@ -56,7 +56,8 @@ func (b *Builder) buildArtifact(ctx context.Context, out io.Writer, artifact *la
 	if b.pushImages {
 		// only track images for pruning when building with docker
 		// if we're pushing a bazel image, it was built directly to the registry
-		if artifact.DockerArtifact != nil {
+		// if we're pushing an image index, there's no local image for its tag
+		if artifact.DockerArtifact != nil && len(artifact.DockerArtifact.Platforms) <= 1 {
 			imageID, err := b.getImageIDForTag(ctx, tag)
 			if err != nil {
 				logrus.Warnf("unable to inspect image: built images may not be cleaned up correctly by skaffold")
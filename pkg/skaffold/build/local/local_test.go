@@ -131,6 +131,19 @@ func TestLocalRun(t *testing.T) {
 			},
 			shouldErr: true,
 		},
+		{
+			description: "several platforms require pushing",
+			artifacts: []*latest.Artifact{{
+				ImageName: "gcr.io/test/image",
+				ArtifactType: latest.ArtifactType{
+					DockerArtifact: &latest.DockerArtifact{
+						Platforms: []string{"linux/amd64", "linux/arm64"},
+					},
+				}},
+			},
+			tags:      tag.ImageTags(map[string]string{"gcr.io/test/image": "gcr.io/test/image:tag"}),
+			shouldErr: true,
+		},
 		{
 			description: "unkown artifact type",
 			artifacts:   []*latest.Artifact{{}},
@@ -246,3 +259,47 @@ func TestLocalRun(t *testing.T) {
 		})
 	}
 }
+
+func TestPullCacheFromImages(t *testing.T) {
+	tests := []struct {
+		description string
+		useBuildkit bool
+		artifact    *latest.DockerArtifact
+		expected    []string
+	}{
+		{
+			description: "pull with the docker daemon",
+			artifact:    &latest.DockerArtifact{CacheFrom: []string{"pull1"}},
+			expected:    []string{"Cache-From image couldn't be pulled: pull1\n"},
+		},
+		{
+			description: "pull with BuildKit",
+			useBuildkit: true,
+			artifact:    &latest.DockerArtifact{CacheFrom: []string{"pull1"}},
+			expected:    []string{"Cache-From image couldn't be pulled: pull1\n"},
+		},
+		{
+			description: "artifacts that require BuildKit read the cache from the registry",
+			useBuildkit: true,
+			artifact:    &latest.DockerArtifact{CacheFrom: []string{"pull1"}, CacheTo: []string{"type=inline"}},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			l := Builder{
+				cfg: &latest.LocalBuild{UseBuildkit: test.useBuildkit},
+				localDocker: docker.NewLocalDaemon(&testutil.FakeAPIClient{
+					ErrImagePull: true,
+					TagToImageID: map[string]string{"pull1": ""},
+				}, nil, false, map[string]bool{}),
+			}
+
+			err := l.pullCacheFromImages(context.Background(), ioutil.Discard, test.artifact)
+
+			t.CheckErrorAndDeepEqual(false, err, test.expected, fakeWarner.Warnings)
+		})
+	}
+}
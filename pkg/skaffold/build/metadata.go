@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// WithMetadata fills in the digest of the images and how they were built.
+func WithMetadata(builds []Artifact, cfg latest.BuildConfig) []Artifact {
+	artifacts := map[string]*latest.Artifact{}
+	for _, a := range cfg.Artifacts {
+		artifacts[a.ImageName] = a
+	}
+
+	var described []Artifact
+	for _, b := range builds {
+		if i := strings.Index(b.Tag, "@"); i != -1 && b.Digest == "" {
+			b.Digest = b.Tag[i+1:]
+		}
+
+		if a, found := artifacts[b.ImageName]; found && b.Builder == nil {
+			b.Builder = &Metadata{
+				Type:         oneOfName(cfg.BuildType),
+				ArtifactType: oneOfName(a.ArtifactType),
+			}
+		}
+
+		described = append(described, b)
+	}
+
+	return described
+}
+
+// oneOfName returns the yaml name of the field that's set in a oneOf struct.
+func oneOfName(v interface{}) string {
+	value := reflect.ValueOf(v)
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			return strings.Split(value.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestWithMetadata(t *testing.T) {
+	cfg := latest.BuildConfig{
+		Artifacts: []*latest.Artifact{
+			{ImageName: "img1", ArtifactType: latest.ArtifactType{DockerArtifact: &latest.DockerArtifact{}}},
+			{ImageName: "img2", ArtifactType: latest.ArtifactType{JibMavenArtifact: &latest.JibMavenArtifact{}}},
+		},
+		BuildType: latest.BuildType{GoogleCloudBuild: &latest.GoogleCloudBuild{}},
+	}
+
+	builds := WithMetadata([]Artifact{
+		{ImageName: "img1", Tag: "gcr.io/img1:v1@sha256:abac"},
+		{ImageName: "img2", Tag: "gcr.io/img2:v1"},
+		{ImageName: "unknown", Tag: "unknown:v1"},
+	}, cfg)
+
+	testutil.CheckDeepEqual(t, []Artifact{
+		{ImageName: "img1", Tag: "gcr.io/img1:v1@sha256:abac", Digest: "sha256:abac", Builder: &Metadata{Type: "googleCloudBuild", ArtifactType: "docker"}},
+		{ImageName: "img2", Tag: "gcr.io/img2:v1", Builder: &Metadata{Type: "googleCloudBuild", ArtifactType: "jibMaven"}},
+		{ImageName: "unknown", Tag: "unknown:v1"},
+	}, builds)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"context"
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+var (
+	// For testing
+	createImageIndex = docker.CreateImageIndex
+)
+
+// PlatformBuilder builds and pushes the image of an artifact for a single platform.
+type PlatformBuilder func(ctx context.Context, out io.Writer, platform, tag string) error
+
+// Platforms returns the target platforms of an artifact.
+func Platforms(a *latest.Artifact) []string {
+	switch {
+	case a.DockerArtifact != nil:
+		return a.DockerArtifact.Platforms
+	case a.KanikoArtifact != nil:
+		return a.KanikoArtifact.Platforms
+	case a.CustomArtifact != nil:
+		return a.CustomArtifact.Platforms
+	default:
+		return nil
+	}
+}
+
+// ForPlatforms builds an image per platform, each with its own tag, and combines
+// them into an image index pushed to the given tag.
+// It returns the digest of the image index.
+func ForPlatforms(ctx context.Context, out io.Writer, tag string, platforms []string, insecureRegistries map[string]bool, buildPlatform PlatformBuilder) (string, error) {
+	var images []string
+
+	for _, platform := range platforms {
+		platformTag, err := docker.PlatformTag(tag, platform)
+		if err != nil {
+			return "", errors.Wrapf(err, "tagging %s for %s", tag, platform)
+		}
+
+		color.Default.Fprintf(out, "Building %s for %s\n", tag, platform)
+		if err := buildPlatform(ctx, out, platform, platformTag); err != nil {
+			return "", errors.Wrapf(err, "building for %s", platform)
+		}
+
+		images = append(images, platformTag)
+	}
+
+	color.Default.Fprintf(out, "Pushing image index %s\n", tag)
+	return createImageIndex(tag, platforms, images, insecureRegistries)
+}
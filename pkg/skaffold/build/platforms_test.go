@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestForPlatforms(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		var indexed []string
+		t.Override(&createImageIndex, func(tag string, platforms, images []string, _ map[string]bool) (string, error) {
+			indexed = append([]string{tag}, images...)
+			return "sha256:index", nil
+		})
+
+		var built []string
+		digest, err := ForPlatforms(context.Background(), ioutil.Discard, "app:v1", []string{"linux/amd64", "linux/arm64"}, nil, func(_ context.Context, _ io.Writer, platform, tag string) error {
+			built = append(built, platform+"="+tag)
+			return nil
+		})
+
+		t.CheckErrorAndDeepEqual(false, err, "sha256:index", digest)
+		t.CheckDeepEqual([]string{"linux/amd64=app:v1-linux-amd64", "linux/arm64=app:v1-linux-arm64"}, built)
+		t.CheckDeepEqual([]string{"app:v1", "app:v1-linux-amd64", "app:v1-linux-arm64"}, indexed)
+	})
+}
+
+func TestForPlatformsBuildError(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&createImageIndex, func(string, []string, []string, map[string]bool) (string, error) {
+			t.Fatal("no image index should be created")
+			return "", nil
+		})
+
+		_, err := ForPlatforms(context.Background(), ioutil.Discard, "app:v1", []string{"linux/amd64", "linux/arm64"}, nil, func(_ context.Context, _ io.Writer, platform, _ string) error {
+			if platform == "linux/arm64" {
+				return errors.New("no arm64 node")
+			}
+			return nil
+		})
+
+		t.CheckErrorContains("building for linux/arm64", err)
+	})
+}
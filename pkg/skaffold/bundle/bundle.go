@@ -0,0 +1,100 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/pkg/errors"
+)
+
+// Version is the version of the bundle format.
+const Version = "skaffold/bundle/v1"
+
+// ConfigFile is the name of the effective configuration, in the project directory of a bundle.
+const ConfigFile = "skaffold.yaml"
+
+// ChartsDir is where the remote helm charts are saved, in the project directory of a bundle.
+const ChartsDir = "charts"
+
+const (
+	indexFile  = "bundle.json"
+	imagesDir  = "images"
+	projectDir = "project"
+)
+
+// Index lists the images saved in a bundle.
+type Index struct {
+	Version string  `json:"version"`
+	Images  []Image `json:"images"`
+}
+
+// Image is an image saved as a docker tarball.
+type Image struct {
+	build.Artifact
+
+	// File is the path to the docker tarball, relative to the bundle.
+	File string `json:"file"`
+}
+
+// ProjectDir is the directory of a bundle that holds the effective
+// configuration and the deployment files.
+func ProjectDir(dir string) string {
+	return filepath.Join(dir, projectDir)
+}
+
+func readIndex(dir string) (*Index, error) {
+	buf, err := ioutil.ReadFile(filepath.Join(dir, indexFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading bundle index")
+	}
+
+	var index Index
+	if err := json.Unmarshal(buf, &index); err != nil {
+		return nil, errors.Wrap(err, "parsing bundle index")
+	}
+	if index.Version != Version {
+		return nil, fmt.Errorf("unsupported bundle version %q, expected %q", index.Version, Version)
+	}
+
+	return &index, nil
+}
+
+func writeIndex(dir string, index *Index) error {
+	buf, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling bundle index")
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, indexFile), append(buf, '\n'), 0644)
+}
+
+// tarballName is the name of the docker tarball for an image.
+func tarballName(i int, imageName string) string {
+	sanitized := strings.NewReplacer("/", "_", ":", "_", ".", "_").Replace(imageName)
+	return fmt.Sprintf("%s/%d-%s.tar", imagesDir, i, sanitized)
+}
+
+// withoutDigest removes the digest from an image reference.
+func withoutDigest(tag string) string {
+	return strings.Split(tag, "@")[0]
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/pkg/errors"
+)
+
+func fakeSave(_ context.Context, tag, file string, _ map[string]bool) error {
+	return ioutil.WriteFile(file, []byte(tag), 0644)
+}
+
+func TestCreateAndLoad(t *testing.T) {
+	tests := []struct {
+		description string
+		defaultRepo string
+		expected    []build.Artifact
+		expectedOps []string
+	}{
+		{
+			description: "load into the docker daemon",
+			expected: []build.Artifact{
+				{ImageName: "gcr.io/project/app", Tag: "gcr.io/project/app:v1"},
+				{ImageName: "worker", Tag: "worker"},
+			},
+			expectedOps: []string{
+				"load gcr.io/project/app:v1 from gcr.io/project/app:v1@sha256:123",
+				"load worker from worker",
+			},
+		},
+		{
+			description: "push to a default repo",
+			defaultRepo: "registry.local:5000",
+			expected: []build.Artifact{
+				{ImageName: "registry.local:5000/gcr_io_project_app", Tag: "registry.local:5000/gcr_io_project_app:v1@sha256:pushed"},
+				{ImageName: "registry.local:5000/worker", Tag: "registry.local:5000/worker:latest@sha256:pushed"},
+			},
+			expectedOps: []string{
+				"push registry.local:5000/gcr_io_project_app:v1 from gcr.io/project/app:v1@sha256:123",
+				"push registry.local:5000/worker:latest from worker",
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			project := t.NewTempDir().
+				Write("k8s/app.yaml", "app manifest").
+				Write("k8s/worker.yaml", "worker manifest")
+			dir := t.NewTempDir()
+
+			var ops []string
+			t.Override(&saveImage, fakeSave)
+			t.Override(&loadImage, func(_ context.Context, _ io.Writer, file, ref string, _ map[string]bool) error {
+				content, err := ioutil.ReadFile(file)
+				ops = append(ops, "load "+ref+" from "+string(content))
+				return err
+			})
+			t.Override(&pushImage, func(file, target string, _ map[string]bool) (string, error) {
+				content, err := ioutil.ReadFile(file)
+				ops = append(ops, "push "+target+" from "+string(content))
+				return target + "@sha256:pushed", err
+			})
+
+			cfg := &latest.SkaffoldConfig{
+				APIVersion: latest.Version,
+				Kind:       "Config",
+				Profiles:   []latest.Profile{{Name: "applied"}},
+			}
+			builds := []build.Artifact{
+				{ImageName: "gcr.io/project/app", Tag: "gcr.io/project/app:v1@sha256:123"},
+				{ImageName: "worker", Tag: "worker"},
+			}
+			files := []string{"k8s/app.yaml", project.Path("k8s/worker.yaml")}
+
+			err := Create(context.Background(), ioutil.Discard, dir.Root(), cfg, builds, files, project.Root(), nil)
+			t.CheckError(false, err)
+
+			manifest, err := ioutil.ReadFile(dir.Path("project/k8s/worker.yaml"))
+			t.CheckErrorAndDeepEqual(false, err, "worker manifest", string(manifest))
+			config, err := ioutil.ReadFile(dir.Path("project/skaffold.yaml"))
+			t.CheckError(false, err)
+			t.CheckDeepEqual(false, strings.Contains(string(config), "applied"))
+
+			loaded, err := Load(context.Background(), ioutil.Discard, dir.Root(), test.defaultRepo, nil)
+			t.CheckErrorAndDeepEqual(false, err, test.expected, loaded)
+			t.CheckDeepEqual(test.expectedOps, ops)
+		})
+	}
+}
+
+func TestCreateOutsideOfProject(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		project := t.NewTempDir()
+		outside := t.NewTempDir().Write("app.yaml", "")
+		t.Override(&saveImage, fakeSave)
+
+		err := Create(context.Background(), ioutil.Discard, t.NewTempDir().Root(), &latest.SkaffoldConfig{}, nil, []string{outside.Path("app.yaml")}, project.Root(), nil)
+
+		t.CheckError(true, err)
+	})
+}
+
+func TestCreateSaveError(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&saveImage, func(context.Context, string, string, map[string]bool) error {
+			return errors.New("not found")
+		})
+
+		err := Create(context.Background(), ioutil.Discard, t.NewTempDir().Root(), &latest.SkaffoldConfig{}, []build.Artifact{{ImageName: "app", Tag: "app:v1"}}, nil, t.NewTempDir().Root(), nil)
+
+		t.CheckErrorContains("saving image app:v1", err)
+	})
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		dir := t.NewTempDir().Write("bundle.json", `{"version":"skaffold/bundle/v2","images":[]}`)
+
+		_, err := Load(context.Background(), ioutil.Discard, dir.Root(), "", nil)
+
+		t.CheckErrorContains(`unsupported bundle version "skaffold/bundle/v2"`, err)
+	})
+}
+
+func TestLoadMissingBundle(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		_, err := Load(context.Background(), ioutil.Discard, t.NewTempDir().Root(), "", nil)
+
+		t.CheckErrorContains("reading bundle index", err)
+	})
+}
+
+func TestTarballName(t *testing.T) {
+	testutil.CheckDeepEqual(t, "images/0-gcr_io_project_app.tar", tarballName(0, "gcr.io/project/app"))
+	testutil.CheckDeepEqual(t, "images/3-localhost_5000_app.tar", tarballName(3, "localhost:5000/app"))
+}
+
+func TestWithLocalCharts(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		helm := &latest.HelmDeploy{
+			Repositories: []latest.HelmRepository{{Name: "charts", URL: "https://charts.example.com"}},
+			Releases: []latest.HelmRelease{
+				{Name: "local", ChartPath: "charts/app"},
+				{Name: "remote", ChartPath: "charts/redis", Version: "10.5.7", Remote: true},
+			},
+		}
+		cfg := &latest.SkaffoldConfig{
+			Pipeline: latest.Pipeline{
+				Deploy: latest.DeployConfig{DeployType: latest.DeployType{HelmDeploy: helm}},
+			},
+		}
+
+		local, err := WithLocalCharts(cfg, "/bundle", map[string]string{
+			"remote": "/bundle/project/charts/1/redis-10.5.7.tgz",
+		})
+
+		t.CheckError(false, err)
+		t.CheckDeepEqual(&latest.HelmDeploy{
+			Releases: []latest.HelmRelease{
+				{Name: "local", ChartPath: "charts/app"},
+				{Name: "remote", ChartPath: "charts/1/redis-10.5.7.tgz", SkipBuildDependencies: true},
+			},
+		}, local.Deploy.HelmDeploy)
+
+		// The original configuration is left untouched.
+		t.CheckDeepEqual("charts/redis", cfg.Deploy.HelmDeploy.Releases[1].ChartPath)
+		t.CheckDeepEqual(1, len(cfg.Deploy.HelmDeploy.Repositories))
+	})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Create saves the built images, the effective configuration and the deployment
+// files to a bundle directory. The deployment files are copied with the same
+// path, relative to the working directory, that they have in the project.
+func Create(ctx context.Context, out io.Writer, dir string, cfg *latest.SkaffoldConfig, builds []build.Artifact, files []string, workingDir string, insecureRegistries map[string]bool) error {
+	if err := os.MkdirAll(filepath.Join(dir, imagesDir), 0755); err != nil {
+		return errors.Wrap(err, "creating bundle directory")
+	}
+
+	color.Default.Fprintln(out, "Copying the deployment files...")
+	if err := writeConfig(cfg, filepath.Join(ProjectDir(dir), ConfigFile)); err != nil {
+		return errors.Wrap(err, "writing effective configuration")
+	}
+	for _, file := range files {
+		if err := copyProjectFile(workingDir, file, ProjectDir(dir)); err != nil {
+			return errors.Wrapf(err, "copying %s", file)
+		}
+	}
+
+	color.Default.Fprintln(out, "Saving the images...")
+	index := &Index{Version: Version}
+	for i, b := range builds {
+		file := tarballName(i, b.ImageName)
+
+		color.Default.Fprintf(out, " - %s -> %s\n", b.Tag, file)
+		if err := saveImage(ctx, b.Tag, filepath.Join(dir, filepath.FromSlash(file)), insecureRegistries); err != nil {
+			return errors.Wrapf(err, "saving image %s", b.Tag)
+		}
+
+		index.Images = append(index.Images, Image{
+			Artifact: b,
+			File:     file,
+		})
+	}
+
+	return writeIndex(dir, index)
+}
+
+// WithLocalCharts returns a copy of a configuration where the remote helm releases
+// are deployed from the charts saved in a bundle, so that nothing is downloaded when
+// the bundle is deployed. Charts are given by release name.
+func WithLocalCharts(cfg *latest.SkaffoldConfig, dir string, charts map[string]string) (*latest.SkaffoldConfig, error) {
+	helm := cfg.Deploy.HelmDeploy
+	if helm == nil || len(charts) == 0 {
+		return cfg, nil
+	}
+
+	local := *helm
+	local.Releases = make([]latest.HelmRelease, len(helm.Releases))
+	remote := false
+	for i, r := range helm.Releases {
+		if archive, found := charts[r.Name]; found && r.Remote {
+			rel, err := filepath.Rel(ProjectDir(dir), archive)
+			if err != nil {
+				return nil, errors.Wrapf(err, "chart of release %s", r.Name)
+			}
+
+			r.ChartPath = filepath.ToSlash(rel)
+			r.Version = ""
+			r.Remote = false
+			r.SkipBuildDependencies = true
+		}
+		remote = remote || r.Remote
+		local.Releases[i] = r
+	}
+
+	// Chart repositories are only needed to download remote charts.
+	if !remote {
+		local.Repositories = nil
+	}
+
+	effective := *cfg
+	effective.Deploy.HelmDeploy = &local
+	return &effective, nil
+}
+
+// writeConfig writes the effective configuration. Profiles are already applied.
+func writeConfig(cfg *latest.SkaffoldConfig, path string) error {
+	effective := *cfg
+	effective.Profiles = nil
+
+	buf, err := yaml.Marshal(&effective)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf, 0644)
+}
+
+func copyProjectFile(workingDir, file, projectDir string) error {
+	absFile := file
+	if !filepath.IsAbs(file) {
+		absFile = filepath.Join(workingDir, file)
+	}
+
+	rel, err := filepath.Rel(workingDir, absFile)
+	if err != nil {
+		return err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("file is outside of %s", workingDir)
+	}
+
+	buf, err := ioutil.ReadFile(absFile)
+	if err != nil {
+		return err
+	}
+
+	dest := filepath.Join(projectDir, rel)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dest, buf, 0644)
+}
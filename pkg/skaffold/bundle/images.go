@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
+)
+
+var (
+	// For testing
+	saveImage = saveDockerTarball
+	loadImage = loadDockerTarball
+	pushImage = pushDockerTarball
+)
+
+// saveDockerTarball saves an image from the local docker daemon if it's
+// found there, or from its registry otherwise.
+func saveDockerTarball(ctx context.Context, tag, file string, insecureRegistries map[string]bool) error {
+	ref := withoutDigest(tag)
+
+	client, err := docker.NewAPIClient(false, insecureRegistries)
+	if err == nil && client.ImageExists(ctx, ref) {
+		rc, err := client.Save(ctx, ref)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(f, rc)
+		return err
+	}
+
+	img, err := docker.RemoteImage(tag, insecureRegistries)
+	if err != nil {
+		return errors.Wrap(err, "getting image from registry")
+	}
+
+	t, err := name.NewTag(ref, name.WeakValidation)
+	if err != nil {
+		return errors.Wrap(err, "parsing tag")
+	}
+
+	return tarball.WriteToFile(file, t, img)
+}
+
+// loadDockerTarball loads a docker tarball into the local docker daemon.
+func loadDockerTarball(ctx context.Context, out io.Writer, file, ref string, insecureRegistries map[string]bool) error {
+	client, err := docker.NewAPIClient(false, insecureRegistries)
+	if err != nil {
+		return errors.Wrap(err, "getting docker client")
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = client.Load(ctx, out, f, ref)
+	return err
+}
+
+// pushDockerTarball pushes a docker tarball to a registry and returns
+// the pushed reference, with its digest.
+func pushDockerTarball(file, target string, insecureRegistries map[string]bool) (string, error) {
+	img, err := tarball.ImageFromPath(file, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "reading docker tarball")
+	}
+
+	ref, err := name.NewTag(target, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing tag")
+	}
+	if insecureRegistries[ref.Context().Registry.Name()] {
+		if ref, err = name.NewTag(target, name.WeakValidation, name.Insecure); err != nil {
+			return "", errors.Wrap(err, "parsing tag")
+		}
+	}
+
+	auth, err := authn.DefaultKeychain.Resolve(ref.Context().Registry)
+	if err != nil {
+		return "", errors.Wrap(err, "getting registry credentials")
+	}
+
+	if err := remote.Write(ref, img, auth, http.DefaultTransport); err != nil {
+		return "", err
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", errors.Wrap(err, "getting digest")
+	}
+
+	return target + "@" + digest.String(), nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+)
+
+// Load makes the images of a bundle available to the cluster. With a default repo,
+// the images are pushed to this repo, and their names are rewritten like `--default-repo`
+// does. Otherwise, they are loaded into the local docker daemon.
+// It returns the build results to deploy.
+func Load(ctx context.Context, out io.Writer, dir, defaultRepo string, insecureRegistries map[string]bool) ([]build.Artifact, error) {
+	index, err := readIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var builds []build.Artifact
+	for _, image := range index.Images {
+		file := filepath.Join(dir, filepath.FromSlash(image.File))
+		ref := withoutDigest(image.Tag)
+
+		if defaultRepo == "" {
+			color.Default.Fprintf(out, "Loading %s\n", ref)
+			if err := loadImage(ctx, out, file, ref, insecureRegistries); err != nil {
+				return nil, errors.Wrapf(err, "loading image %s", ref)
+			}
+
+			builds = append(builds, build.Artifact{
+				ImageName: image.ImageName,
+				Tag:       ref,
+			})
+			continue
+		}
+
+		imageName := util.SubstituteDefaultRepoIntoImage(defaultRepo, image.ImageName)
+		target, err := retag(ref, imageName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", ref)
+		}
+
+		color.Default.Fprintf(out, "Pushing %s\n", target)
+		tag, err := pushImage(file, target, insecureRegistries)
+		if err != nil {
+			return nil, errors.Wrapf(err, "pushing image %s", target)
+		}
+
+		builds = append(builds, build.Artifact{
+			ImageName: imageName,
+			Tag:       tag,
+		})
+	}
+
+	return builds, nil
+}
+
+// retag gives the tag of a reference to another image name.
+func retag(ref, imageName string) (string, error) {
+	parsed, err := docker.ParseReference(ref)
+	if err != nil {
+		return "", err
+	}
+
+	tag := parsed.Tag
+	if tag == "" {
+		tag = "latest"
+	}
+	return imageName + ":" + tag, nil
+}
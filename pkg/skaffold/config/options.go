@@ -25,32 +25,42 @@ import (
 // SkaffoldOptions are options that are set by command line arguments not included
 // in the config file itself
 type SkaffoldOptions struct {
-	ConfigurationFile  string
-	Cleanup            bool
-	Notification       bool
-	Tail               bool
-	TailDev            bool
-	PortForward        bool
-	SkipTests          bool
-	CacheArtifacts     bool
-	EnableRPC          bool
-	Force              bool
-	ForceDev           bool
-	NoPrune            bool
-	NoPruneChildren    bool
-	CustomTag          string
-	Namespace          string
-	CacheFile          string
-	Trigger            string
-	WatchPollInterval  int
-	DefaultRepo        string
-	CustomLabels       []string
-	TargetImages       []string
-	Profiles           []string
-	InsecureRegistries []string
-	Command            string
-	RPCPort            int
-	RPCHTTPPort        int
+	ConfigurationFile     string
+	Cleanup               bool
+	Notification          bool
+	Tail                  bool
+	TailDev               bool
+	PortForward           bool
+	SkipTests             bool
+	CacheArtifacts        bool
+	EnableRPC             bool
+	Force                 bool
+	ForceDev              bool
+	NativeApply           bool
+	PruneDryRun           bool
+	EphemeralNamespace    bool
+	Interactive           bool
+	Resume                bool
+	NoPrune               bool
+	NoPruneChildren       bool
+	CustomTag             string
+	Namespace             string
+	EphemeralNamespaceTTL string
+	CacheFile             string
+	TestReportDir         string
+	Trigger               string
+	WatchPollInterval     int
+	WatchQuietPeriod      string
+	WatchMaxWait          string
+	WatchMinInterval      string
+	DefaultRepo           string
+	CustomLabels          []string
+	TargetImages          []string
+	Profiles              []string
+	InsecureRegistries    []string
+	Command               string
+	RPCPort               int
+	RPCHTTPPort           int
 }
 
 // Labels returns a map of labels to be applied to all deployed
@@ -82,9 +92,10 @@ func (opts *SkaffoldOptions) Labels() map[string]string {
 }
 
 // Prune returns true iff the user did NOT specify the --no-prune flag,
-// and the user did NOT specify the --cache-artifacts flag.
+// the user did NOT specify the --cache-artifacts flag
+// and the user did NOT specify the --resume flag.
 func (opts *SkaffoldOptions) Prune() bool {
-	return !opts.NoPrune && !opts.CacheArtifacts
+	return !opts.NoPrune && !opts.CacheArtifacts && !opts.Resume
 }
 
 func (opts *SkaffoldOptions) ForceDeploy() bool {
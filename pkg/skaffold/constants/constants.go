@@ -61,6 +61,8 @@ const (
 
 	DefaultSkaffoldDir = ".skaffold"
 	DefaultCacheFile   = "cache"
+	DefaultChartsDir   = "charts"
+	DefaultAppliedDir  = "applied"
 
 	DefaultRPCPort     = 50051
 	DefaultRPCHTTPPort = 50052
@@ -75,6 +77,12 @@ var (
 
 	// BuildContext is the absolute path to a directory this artifact is meant to be built from for custom artifacts
 	BuildContext = "BUILD_CONTEXT"
+
+	// Platform is the target platform, like `linux/arm64`, passed in to a custom build script.
+	Platform = "PLATFORM"
+
+	// Image is an environment variable key, whose value is the fully qualified image name passed in to a custom test command.
+	Image = "IMAGE"
 )
 
 var DefaultKubectlManifests = []string{"k8s/*.yaml"}
@@ -82,13 +90,27 @@ var DefaultKubectlManifests = []string{"k8s/*.yaml"}
 var LatestDownloadURL = fmt.Sprintf("https://storage.googleapis.com/skaffold/releases/latest/skaffold-%s-%s", runtime.GOOS, runtime.GOARCH)
 
 var Labels = struct {
-	TagPolicy        string
-	Deployer         string
-	Builder          string
-	DockerAPIVersion string
+	TagPolicy          string
+	Deployer           string
+	Builder            string
+	DockerAPIVersion   string
+	HelmRelease        string
+	EphemeralNamespace string
+	User               string
+	RunID              string
+}{
+	TagPolicy:          "skaffold.dev/tag-policy",
+	Deployer:           "skaffold.dev/deployer",
+	Builder:            "skaffold.dev/builder",
+	DockerAPIVersion:   "skaffold.dev/docker-api-version",
+	HelmRelease:        "skaffold.dev/helm-release",
+	EphemeralNamespace: "skaffold.dev/ephemeral-namespace",
+	User:               "skaffold.dev/user",
+	RunID:              "skaffold.dev/run-id",
+}
+
+var Annotations = struct {
+	TTL string
 }{
-	TagPolicy:        "skaffold.dev/tag-policy",
-	Deployer:         "skaffold.dev/deployer",
-	Builder:          "skaffold.dev/builder",
-	DockerAPIVersion: "skaffold.dev/docker-api-version",
+	TTL: "skaffold.dev/ttl",
 }
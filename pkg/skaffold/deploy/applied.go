@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// appliedRootDir returns the directory where deployers record the manifests they applied.
+// An empty directory disables the recording.
+var appliedRootDir = func() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, constants.DefaultSkaffoldDir, constants.DefaultAppliedDir), nil
+}
+
+// appliedDir returns the directory where the deployers of a pipeline record
+// the manifests they applied. Pipelines are told apart by their kube context,
+// namespace, working directory, configuration file and active profiles.
+func appliedDir(runCtx *runcontext.RunContext) string {
+	root, err := appliedRootDir()
+	if err != nil {
+		logrus.Warnln("Unable to record applied manifests:", err)
+		return ""
+	}
+	if root == "" {
+		return ""
+	}
+
+	configFile := runCtx.Opts.ConfigurationFile
+	if !util.IsURL(configFile) && !filepath.IsAbs(configFile) {
+		configFile = filepath.Join(runCtx.WorkingDir, configFile)
+	}
+
+	profiles := append([]string{}, runCtx.Opts.Profiles...)
+	sort.Strings(profiles)
+
+	key := strings.Join([]string{runCtx.KubeContext, runCtx.Opts.Namespace, runCtx.WorkingDir, configFile, strings.Join(profiles, ",")}, "\n")
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(root, hex.EncodeToString(hash[:])[:16])
+}
+
+// appliedFile returns the file where a deployer records the manifests it applied.
+func appliedFile(dir, name string) string {
+	if dir == "" {
+		return ""
+	}
+
+	return filepath.Join(dir, name+".yaml")
+}
+
+// deleteApplied deletes the objects recorded by the last apply. When nothing
+// was recorded, it reads the manifests again to find what was deployed.
+func deleteApplied(ctx context.Context, out io.Writer, cli *kubectl.CLI, readManifests func() (kubectl.ManifestList, error)) error {
+	manifests, err := cli.Applied()
+	if err != nil {
+		return err
+	}
+
+	if len(manifests) == 0 {
+		manifests, err = readManifests()
+		if err != nil {
+			return errors.Wrap(err, "reading manifests")
+		}
+	}
+
+	if err := cli.Delete(ctx, out, manifests); err != nil {
+		return errors.Wrap(err, "delete")
+	}
+
+	return cli.ForgetApplied()
+}
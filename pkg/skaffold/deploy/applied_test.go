@@ -0,0 +1,47 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestAppliedDir(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&appliedRootDir, func() (string, error) { return "/state", nil })
+
+		dir := func(configFile string, profiles ...string) string {
+			return appliedDir(&runcontext.RunContext{
+				WorkingDir:  "/project",
+				KubeContext: "kubecontext",
+				Opts: &config.SkaffoldOptions{
+					ConfigurationFile: configFile,
+					Profiles:          profiles,
+				},
+			})
+		}
+
+		t.CheckDeepEqual(dir("skaffold.yaml"), dir("/project/skaffold.yaml"))
+		t.CheckDeepEqual(dir("skaffold.yaml", "a", "b"), dir("skaffold.yaml", "b", "a"))
+		t.CheckDeepEqual(true, dir("skaffold.yaml") != dir("other.yaml"))
+		t.CheckDeepEqual(true, dir("skaffold.yaml", "a") != dir("skaffold.yaml", "b"))
+	})
+}
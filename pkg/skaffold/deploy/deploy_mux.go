@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/pkg/errors"
+)
+
+// DeployerMux runs several deployers, one after the other.
+type DeployerMux []Deployer
+
+// Labels merges the labels of all the deployers.
+func (m DeployerMux) Labels() map[string]string {
+	labels := make(map[string]string)
+	var names []string
+
+	for _, deployer := range m {
+		copyMap(labels, deployer.Labels())
+		names = append(names, deployerName(deployer))
+	}
+	labels[constants.Labels.Deployer] = strings.Join(names, "_")
+
+	return labels
+}
+
+// Deploy runs the deployers in order and stops at the first failure.
+// Resources are labelled with the name of the deployer that deployed them.
+// The mux sends the deploy events, the deployers only report their own progress.
+func (m DeployerMux) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []Labeller) error {
+	event.DeployInProgress()
+
+	for _, deployer := range m {
+		name := deployerName(deployer)
+		event.DeployerInProgress(name)
+
+		deployerLabellers := append(append([]Labeller{}, labellers...), deployer)
+		if err := deployer.Deploy(ctx, out, builds, deployerLabellers); err != nil {
+			event.DeployerFailed(name, err)
+			err = errors.Wrapf(err, "deploying with %s", name)
+			event.DeployFailed(err)
+			return err
+		}
+
+		event.DeployerComplete(name)
+	}
+
+	event.DeployComplete()
+	return nil
+}
+
+// Dependencies lists the files that any of the deployers depends on.
+func (m DeployerMux) Dependencies() ([]string, error) {
+	seen := make(map[string]bool)
+	var deps []string
+
+	for _, deployer := range m {
+		result, err := deployer.Dependencies()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dep := range result {
+			if !seen[dep] {
+				seen[dep] = true
+				deps = append(deps, dep)
+			}
+		}
+	}
+
+	sort.Strings(deps)
+	return deps, nil
+}
+
+// Cleanup runs the cleanup of every deployer, in the reverse order.
+// It keeps going when a cleanup fails and returns the first error.
+func (m DeployerMux) Cleanup(ctx context.Context, out io.Writer) error {
+	var firstErr error
+
+	for i := len(m) - 1; i >= 0; i-- {
+		if err := m[i].Cleanup(ctx, out); err != nil && firstErr == nil {
+			firstErr = errors.Wrapf(err, "cleaning up %s", deployerName(m[i]))
+		}
+	}
+
+	return firstErr
+}
+
+func deployerName(deployer Deployer) string {
+	return deployer.Labels()[constants.Labels.Deployer]
+}
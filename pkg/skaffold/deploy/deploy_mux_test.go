@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+type fakeDeployer struct {
+	name      string
+	deps      []string
+	deployErr error
+	cleanErr  error
+	calls     *[]string
+	labels    map[string]string
+}
+
+func (f *fakeDeployer) Labels() map[string]string {
+	return map[string]string{constants.Labels.Deployer: f.name}
+}
+
+func (f *fakeDeployer) Deploy(_ context.Context, _ io.Writer, _ []build.Artifact, labellers []Labeller) error {
+	*f.calls = append(*f.calls, "deploy "+f.name)
+	f.labels = merge(labellers...)
+	return f.deployErr
+}
+
+func (f *fakeDeployer) Dependencies() ([]string, error) {
+	return f.deps, nil
+}
+
+func (f *fakeDeployer) Cleanup(context.Context, io.Writer) error {
+	*f.calls = append(*f.calls, "cleanup "+f.name)
+	return f.cleanErr
+}
+
+func TestDeployerMux(t *testing.T) {
+	event.InitializeState(&runcontext.RunContext{Cfg: &latest.Pipeline{}})
+
+	testutil.Run(t, "deploy in order and clean up in reverse order", func(t *testutil.T) {
+		var calls []string
+		helm := &fakeDeployer{name: "helm", deps: []string{"values.yaml", "chart/Chart.yaml"}, calls: &calls}
+		kubectl := &fakeDeployer{name: "kubectl", deps: []string{"k8s/pod.yaml", "values.yaml"}, calls: &calls}
+		mux := DeployerMux{helm, kubectl}
+
+		err := mux.Deploy(context.Background(), ioutil.Discard, nil, []Labeller{mux})
+		t.CheckError(false, err)
+		err = mux.Cleanup(context.Background(), ioutil.Discard)
+		t.CheckError(false, err)
+		deps, err := mux.Dependencies()
+
+		t.CheckErrorAndDeepEqual(false, err, []string{"chart/Chart.yaml", "k8s/pod.yaml", "values.yaml"}, deps)
+		t.CheckDeepEqual([]string{"deploy helm", "deploy kubectl", "cleanup kubectl", "cleanup helm"}, calls)
+		t.CheckDeepEqual("helm_kubectl", mux.Labels()[constants.Labels.Deployer])
+		t.CheckDeepEqual("helm", helm.labels[constants.Labels.Deployer])
+		t.CheckDeepEqual("kubectl", kubectl.labels[constants.Labels.Deployer])
+	})
+
+	testutil.Run(t, "stop at the first failure", func(t *testutil.T) {
+		var calls []string
+		mux := DeployerMux{
+			&fakeDeployer{name: "helm", deployErr: errors.New("BUG"), calls: &calls},
+			&fakeDeployer{name: "kubectl", calls: &calls},
+		}
+
+		err := mux.Deploy(context.Background(), ioutil.Discard, nil, nil)
+
+		t.CheckErrorContains("deploying with helm: BUG", err)
+		t.CheckDeepEqual([]string{"deploy helm"}, calls)
+	})
+
+	testutil.Run(t, "clean up every deployer", func(t *testutil.T) {
+		var calls []string
+		mux := DeployerMux{
+			&fakeDeployer{name: "helm", calls: &calls},
+			&fakeDeployer{name: "kubectl", cleanErr: errors.New("BUG"), calls: &calls},
+		}
+
+		err := mux.Cleanup(context.Background(), ioutil.Discard)
+
+		t.CheckErrorContains("cleaning up kubectl: BUG", err)
+		t.CheckDeepEqual([]string{"cleanup kubectl", "cleanup helm"}, calls)
+	})
+}
@@ -22,21 +22,26 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
-	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
+	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	yaml "gopkg.in/yaml.v2"
@@ -45,22 +50,62 @@ import (
 type HelmDeployer struct {
 	*latest.HelmDeploy
 
-	kubeContext string
-	namespace   string
-	defaultRepo string
-	forceDeploy bool
+	kubeContext        string
+	namespace          string
+	defaultRepo        string
+	forceDeploy        bool
+	nativeApply        bool
+	pruneDryRun        bool
+	appliedDir         string
+	insecureRegistries map[string]bool
+	kubectls           map[string]*kubectl.CLI
+	kubectlsLock       sync.Mutex
+	chartsDir          string
+	repositoriesAdded  bool
+	repositoriesLock   sync.Mutex
+
+	versionOnce sync.Once
+	version     semver.Version
 }
 
+var (
+	// helm2 is assumed when the version of the helm client can't be found.
+	helm2 = semver.MustParse("2.0.0")
+
+	// helm32 is the first version of helm to support `--create-namespace`
+	// and `helm repo add --password-stdin`.
+	helm32 = semver.MustParse("3.2.0")
+)
+
 // NewHelmDeployer returns a new HelmDeployer for a DeployConfig filled
 // with the needed configuration for `helm`
 func NewHelmDeployer(runCtx *runcontext.RunContext) *HelmDeployer {
 	return &HelmDeployer{
-		HelmDeploy:  runCtx.Cfg.Deploy.HelmDeploy,
-		kubeContext: runCtx.KubeContext,
-		namespace:   runCtx.Opts.Namespace,
-		defaultRepo: runCtx.DefaultRepo,
-		forceDeploy: runCtx.Opts.ForceDeploy(),
+		HelmDeploy:         runCtx.Cfg.Deploy.HelmDeploy,
+		kubeContext:        runCtx.KubeContext,
+		namespace:          runCtx.Opts.Namespace,
+		defaultRepo:        runCtx.DefaultRepo,
+		forceDeploy:        runCtx.Opts.ForceDeploy(),
+		nativeApply:        runCtx.Opts.NativeApply,
+		pruneDryRun:        runCtx.Opts.PruneDryRun,
+		appliedDir:         appliedDir(runCtx),
+		insecureRegistries: runCtx.InsecureRegistries,
+	}
+}
+
+// Reconfigure applies a new configuration to the deployer. It still knows
+// what each release applied before. Chart repositories are added again
+// only if they changed.
+func (h *HelmDeployer) Reconfigure(runCtx *runcontext.RunContext) {
+	if !reflect.DeepEqual(h.Repositories, runCtx.Cfg.Deploy.HelmDeploy.Repositories) {
+		h.repositoriesLock.Lock()
+		h.repositoriesAdded = false
+		h.repositoriesLock.Unlock()
 	}
+
+	h.HelmDeploy = runCtx.Cfg.Deploy.HelmDeploy
+	h.defaultRepo = runCtx.DefaultRepo
+	h.insecureRegistries = runCtx.InsecureRegistries
 }
 
 func (h *HelmDeployer) Labels() map[string]string {
@@ -70,28 +115,27 @@ func (h *HelmDeployer) Labels() map[string]string {
 }
 
 func (h *HelmDeployer) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []Labeller) error {
-	var dRes []Artifact
-
-	event.DeployInProgress()
-
-	for _, r := range h.Releases {
-		results, err := h.deployRelease(ctx, out, r, builds)
-		if err != nil {
-			releaseName, _ := evaluateReleaseName(r.Name)
+	if err := h.addRepositories(ctx, out); err != nil {
+		return err
+	}
 
-			event.DeployFailed(err)
-			return errors.Wrapf(err, "deploying %s", releaseName)
+	deploy := h.deployRelease
+	if h.RenderTemplates {
+		deploy = func(ctx context.Context, out io.Writer, r latest.HelmRelease, builds []build.Artifact) ([]Artifact, error) {
+			return h.applyRelease(ctx, out, r, builds, labellers)
 		}
-
-		dRes = append(dRes, results...)
 	}
 
-	event.DeployComplete()
+	// The releases that were deployed are labelled even if others failed.
+	dRes, err := h.deployReleases(ctx, out, builds, deploy)
 
-	labels := merge(labellers...)
-	labelDeployResults(labels, dRes)
+	// Rendered releases are labelled before they are applied.
+	if !h.RenderTemplates {
+		labels := merge(labellers...)
+		labelDeployResults(labels, dRes)
+	}
 
-	return nil
+	return err
 }
 
 func (h *HelmDeployer) Dependencies() ([]string, error) {
@@ -132,6 +176,10 @@ func (h *HelmDeployer) Dependencies() ([]string, error) {
 
 // Cleanup deletes what was deployed by calling Deploy.
 func (h *HelmDeployer) Cleanup(ctx context.Context, out io.Writer) error {
+	if h.RenderTemplates {
+		return h.cleanupRendered(ctx, out)
+	}
+
 	for _, r := range h.Releases {
 		if err := h.deleteRelease(ctx, out, r); err != nil {
 			releaseName, _ := evaluateReleaseName(r.Name)
@@ -141,7 +189,81 @@ func (h *HelmDeployer) Cleanup(ctx context.Context, out io.Writer) error {
 	return nil
 }
 
+// helmVersion returns the version of the helm client.
+func (h *HelmDeployer) helmVersion(ctx context.Context) semver.Version {
+	h.versionOnce.Do(func() {
+		h.version = helm2
+
+		cmd := exec.CommandContext(ctx, "helm", "version", "--client", "--short")
+		out, err := util.RunCmdOut(cmd)
+		if err != nil {
+			warnings.Printf("unable to get helm client version, assuming helm 2: %v", err)
+			return
+		}
+
+		version, err := parseHelmVersion(string(out))
+		if err != nil {
+			warnings.Printf("unable to parse helm client version, assuming helm 2: %v", err)
+			return
+		}
+
+		h.version = version
+	})
+
+	return h.version
+}
+
+// parseHelmVersion parses the output of `helm version --client --short`, which is
+// `Client: v2.14.1+g5270352` with Helm 2 and `v3.0.0+ge29ce2a` with Helm 3.
+func parseHelmVersion(output string) (semver.Version, error) {
+	version := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(output), "Client:"))
+	return semver.ParseTolerant(version)
+}
+
+func (h *HelmDeployer) isHelm3(ctx context.Context) bool {
+	return h.helmVersion(ctx).Major >= 3
+}
+
+// releaseNamespace returns the namespace a release is deployed to,
+// or an empty string to use the namespace of the current context.
+func (h *HelmDeployer) releaseNamespace(r latest.HelmRelease) string {
+	if h.namespace != "" {
+		return h.namespace
+	}
+	return r.Namespace
+}
+
+// namespaced appends the `--namespace` flag to the arguments, if a namespace is set.
+func namespaced(args []string, ns string) []string {
+	if ns == "" {
+		return args
+	}
+	return append(args, "--namespace", ns)
+}
+
 func (h *HelmDeployer) helm(ctx context.Context, out io.Writer, useSecrets bool, arg ...string) error {
+	return h.helmWithStderr(ctx, out, out, useSecrets, arg...)
+}
+
+func (h *HelmDeployer) helmWithStderr(ctx context.Context, out io.Writer, errOut io.Writer, useSecrets bool, arg ...string) error {
+	cmd := h.helmCommand(ctx, useSecrets, arg...)
+	cmd.Stdout = out
+	cmd.Stderr = errOut
+
+	return util.RunCmd(cmd)
+}
+
+// helmWithInput runs helm with the given stdin.
+func (h *HelmDeployer) helmWithInput(ctx context.Context, out io.Writer, in io.Reader, arg ...string) error {
+	cmd := h.helmCommand(ctx, false, arg...)
+	cmd.Stdin = in
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	return util.RunCmd(cmd)
+}
+
+func (h *HelmDeployer) helmCommand(ctx context.Context, useSecrets bool, arg ...string) *exec.Cmd {
 	args := append([]string{"--kube-context", h.kubeContext}, arg...)
 	args = append(args, h.Flags.Global...)
 
@@ -149,11 +271,7 @@ func (h *HelmDeployer) helm(ctx context.Context, out io.Writer, useSecrets bool,
 		args = append([]string{"secrets"}, args...)
 	}
 
-	cmd := exec.CommandContext(ctx, "helm", args...)
-	cmd.Stdout = out
-	cmd.Stderr = out
-
-	return util.RunCmd(cmd)
+	return exec.CommandContext(ctx, "helm", args...)
 }
 
 func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r latest.HelmRelease, builds []build.Artifact) ([]Artifact, error) {
@@ -163,22 +281,108 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse the release name template")
 	}
-	if err := h.helm(ctx, out, false, "get", releaseName); err != nil {
+
+	helm3 := h.isHelm3(ctx)
+	ns := h.releaseNamespace(r)
+
+	// Helm 3 releases are scoped to a namespace.
+	getArgs := []string{"get", releaseName}
+	if helm3 {
+		getArgs = namespaced([]string{"get", "all", releaseName}, ns)
+	}
+	if err := h.helm(ctx, out, false, getArgs...); err != nil {
 		color.Red.Fprintf(out, "Helm release %s not installed. Installing...\n", releaseName)
 		isInstalled = false
 	}
+
+	if err := h.buildDependencies(ctx, out, r); err != nil {
+		return nil, err
+	}
+
+	var args []string
+	switch {
+	case !isInstalled && helm3:
+		args = append(args, "install", releaseName)
+		if ns != "" && h.helmVersion(ctx).GTE(helm32) {
+			args = append(args, "--create-namespace")
+		}
+		args = append(args, h.Flags.Install...)
+	case !isInstalled:
+		args = append(args, "install", "--name", releaseName)
+		args = append(args, h.Flags.Install...)
+	default:
+		args = append(args, "upgrade", releaseName)
+		args = append(args, h.Flags.Upgrade...)
+		if h.forceDeploy {
+			args = append(args, "--force")
+		}
+		if r.RecreatePods {
+			if helm3 {
+				warnings.Printf("recreatePods is not supported by helm 3 and is ignored for release %s", releaseName)
+			} else {
+				args = append(args, "--recreate-pods")
+			}
+		}
+	}
+	if r.Wait {
+		args = append(args, "--wait")
+	}
+
+	releaseArgs, cleanup, err := h.releaseArgs(ctx, out, r, builds, ns)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	args = append(args, releaseArgs...)
+
+	helmErr := h.helm(ctx, out, r.UseHelmSecrets, args...)
+	return h.getDeployResults(ctx, ns, releaseName), helmErr
+}
+
+// buildDependencies runs `helm dep build` on the chart of a release, if needed.
+func (h *HelmDeployer) buildDependencies(ctx context.Context, out io.Writer, r latest.HelmRelease) error {
+	// Dependency builds should be skipped when trying to install a chart
+	// with local dependencies in the chart folder, e.g. the istio helm chart.
+	// This decision is left to the user.
+	// Dep builds should also be skipped whenever a remote chart path is specified.
+	if r.SkipBuildDependencies || r.Remote {
+		return nil
+	}
+
+	logrus.Infof("Building helm dependencies...")
+	if err := h.helm(ctx, out, false, "dep", "build", r.ChartPath); err != nil {
+		return errors.Wrap(err, "building helm dependencies")
+	}
+
+	return nil
+}
+
+// releaseArgs returns the arguments that select the chart, the namespace and the values
+// of a release. The returned function removes the temporary files these arguments refer to.
+func (h *HelmDeployer) releaseArgs(ctx context.Context, out io.Writer, r latest.HelmRelease, builds []build.Artifact, ns string) ([]string, func(), error) {
+	noop := func() {}
+
 	params, err := h.joinTagsToBuildResult(builds, r.Values)
 	if err != nil {
-		return nil, errors.Wrap(err, "matching build results to chart values")
+		return nil, noop, errors.Wrap(err, "matching build results to chart values")
 	}
 
 	var setOpts []string
 	for k, v := range params {
+		if templated := r.ImageStrategy.HelmImageConfig.HelmTemplatedConfig; templated != nil {
+			values, err := templatedImageValues(k, v.Tag, templated)
+			if err != nil {
+				return nil, noop, err
+			}
+			setOpts = append(setOpts, values...)
+			continue
+		}
+
 		setOpts = append(setOpts, "--set")
 		if r.ImageStrategy.HelmImageConfig.HelmConventionConfig != nil {
 			dockerRef, err := docker.ParseReference(v.Tag)
 			if err != nil {
-				return nil, errors.Wrapf(err, "cannot parse the docker image reference %s", v.Tag)
+				return nil, noop, errors.Wrapf(err, "cannot parse the docker image reference %s", v.Tag)
 			}
 			imageRepositoryTag := fmt.Sprintf("%s.repository=%s,%s.tag=%s", k, dockerRef.BaseName, k, dockerRef.Tag)
 			setOpts = append(setOpts, imageRepositoryTag)
@@ -187,32 +391,7 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 		}
 	}
 
-	// Dependency builds should be skipped when trying to install a chart
-	// with local dependencies in the chart folder, e.g. the istio helm chart.
-	// This decision is left to the user.
-	// Dep builds should also be skipped whenever a remote chart path is specified.
-	if !r.SkipBuildDependencies && !r.Remote {
-		// First build dependencies.
-		logrus.Infof("Building helm dependencies...")
-		if err := h.helm(ctx, out, false, "dep", "build", r.ChartPath); err != nil {
-			return nil, errors.Wrap(err, "building helm dependencies")
-		}
-	}
-
 	var args []string
-	if !isInstalled {
-		args = append(args, "install", "--name", releaseName)
-		args = append(args, h.Flags.Install...)
-	} else {
-		args = append(args, "upgrade", releaseName)
-		args = append(args, h.Flags.Upgrade...)
-		if h.forceDeploy {
-			args = append(args, "--force")
-		}
-		if r.RecreatePods {
-			args = append(args, "--recreate-pods")
-		}
-	}
 
 	// There are 2 strategies:
 	// 1) Deploy chart directly from filesystem path or from repository
@@ -222,44 +401,52 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 	//    that packaged chart. This way user can apply any version and appVersion
 	//    for the chart.
 	if r.Packaged == nil {
-		if r.Version != "" {
-			args = append(args, "--version", r.Version)
+		chartPath, version := r.ChartPath, r.Version
+
+		// Remote charts with an exact version are downloaded once and then
+		// deployed from the cache.
+		if h.isCacheable(r) {
+			cached, err := h.cachedChart(ctx, out, r)
+			if err != nil {
+				return nil, noop, err
+			}
+			chartPath, version = cached, ""
+		}
+
+		if version != "" {
+			args = append(args, "--version", version)
 		}
-		args = append(args, r.ChartPath)
+		args = append(args, chartPath)
 	} else {
 		chartPath, err := h.packageChart(ctx, r)
 		if err != nil {
-			return nil, errors.WithMessage(err, "cannot package chart")
+			return nil, noop, errors.WithMessage(err, "cannot package chart")
 		}
 		args = append(args, chartPath)
 	}
 
-	var ns string
-	if h.namespace != "" {
-		ns = h.namespace
-	} else if r.Namespace != "" {
-		ns = r.Namespace
-	}
-	if ns != "" {
-		args = append(args, "--namespace", ns)
-	}
+	args = namespaced(args, ns)
+
+	cleanup := noop
 	if len(r.Overrides.Values) != 0 {
 		overrides, err := yaml.Marshal(r.Overrides)
 		if err != nil {
-			return nil, errors.Wrap(err, "cannot marshal overrides to create overrides values.yaml")
+			return nil, noop, errors.Wrap(err, "cannot marshal overrides to create overrides values.yaml")
 		}
-		overridesFile, err := os.Create(constants.HelmOverridesFilename)
+		// Each release gets its own file since releases can be deployed in parallel.
+		overridesFile, err := ioutil.TempFile("", "*-"+constants.HelmOverridesFilename)
 		if err != nil {
-			return nil, errors.Wrapf(err, "cannot create file %s", constants.HelmOverridesFilename)
+			return nil, noop, errors.Wrapf(err, "cannot create file %s", constants.HelmOverridesFilename)
 		}
-		defer func() {
+		cleanup = func() {
 			overridesFile.Close()
-			os.Remove(constants.HelmOverridesFilename)
-		}()
+			os.Remove(overridesFile.Name())
+		}
 		if _, err := overridesFile.WriteString(string(overrides)); err != nil {
-			return nil, errors.Wrapf(err, "failed to write file %s", constants.HelmOverridesFilename)
+			cleanup()
+			return nil, noop, errors.Wrapf(err, "failed to write file %s", overridesFile.Name())
 		}
-		args = append(args, "-f", constants.HelmOverridesFilename)
+		args = append(args, "-f", overridesFile.Name())
 	}
 	for _, valuesFile := range r.ValuesFiles {
 		args = append(args, "-f", valuesFile)
@@ -285,11 +472,13 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 		for k, v := range r.SetValueTemplates {
 			t, err := util.ParseEnvTemplate(v)
 			if err != nil {
-				return nil, errors.Wrapf(err, "failed to parse setValueTemplates")
+				cleanup()
+				return nil, noop, errors.Wrapf(err, "failed to parse setValueTemplates")
 			}
 			result, err := util.ExecuteEnvTemplate(t, envMap)
 			if err != nil {
-				return nil, errors.Wrapf(err, "failed to generate setValueTemplates")
+				cleanup()
+				return nil, noop, errors.Wrapf(err, "failed to generate setValueTemplates")
 			}
 			setValues[k] = result
 		}
@@ -298,15 +487,168 @@ func (h *HelmDeployer) deployRelease(ctx context.Context, out io.Writer, r lates
 		setOpts = append(setOpts, "--set")
 		setOpts = append(setOpts, fmt.Sprintf("%s=%s", k, v))
 	}
-	if r.Wait {
-		args = append(args, "--wait")
-	}
 	args = append(args, setOpts...)
 
-	helmErr := h.helm(ctx, out, r.UseHelmSecrets, args...)
-	return h.getDeployResults(ctx, ns, releaseName), helmErr
+	return args, cleanup, nil
+}
+
+// renderRelease renders the manifests of a release with `helm template`.
+func (h *HelmDeployer) renderRelease(ctx context.Context, out io.Writer, r latest.HelmRelease, builds []build.Artifact) (kubectl.ManifestList, error) {
+	releaseName, err := evaluateReleaseName(r.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse the release name template")
+	}
+
+	if err := h.buildDependencies(ctx, out, r); err != nil {
+		return nil, err
+	}
+
+	args := []string{"template", releaseName}
+	if !h.isHelm3(ctx) {
+		args = []string{"template", "--name", releaseName}
+
+		// Helm 2 only templates local charts, so remote charts are downloaded first.
+		if r.Remote && !h.isCacheable(r) {
+			tmpDir, err := ioutil.TempDir("", "chart")
+			if err != nil {
+				return nil, errors.Wrap(err, "creating temporary directory")
+			}
+			defer os.RemoveAll(tmpDir)
+
+			archive, err := h.fetchChart(ctx, out, r, tmpDir)
+			if err != nil {
+				return nil, err
+			}
+			r.ChartPath, r.Version, r.Remote = archive, "", false
+		}
+	}
+
+	releaseArgs, cleanup, err := h.releaseArgs(ctx, out, r, builds, h.releaseNamespace(r))
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	args = append(args, releaseArgs...)
+
+	var buf bytes.Buffer
+	if err := h.helmWithStderr(ctx, &buf, out, r.UseHelmSecrets, args...); err != nil {
+		return nil, errors.Wrap(err, "helm template")
+	}
+
+	var manifests kubectl.ManifestList
+	manifests.Append(buf.Bytes())
+	return manifests, nil
+}
+
+// applyRelease renders a release and deploys it the same way as the kubectl deployer does.
+// It returns the applied resources.
+func (h *HelmDeployer) applyRelease(ctx context.Context, out io.Writer, r latest.HelmRelease, builds []build.Artifact, labellers []Labeller) ([]Artifact, error) {
+	manifests, err := h.renderRelease(ctx, out, r, builds)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err = manifests.ReplaceImages(builds, h.defaultRepo)
+	if err != nil {
+		return nil, errors.Wrap(err, "replacing images in manifests")
+	}
+
+	// The release label keeps track of the release the resources belong to,
+	// since helm doesn't know about releases it only rendered.
+	releaseName, _ := evaluateReleaseName(r.Name)
+	labels := merge(labellers...)
+	labels[constants.Labels.HelmRelease] = releaseName
+
+	manifests, err = manifests.SetLabels(labels)
+	if err != nil {
+		return nil, errors.Wrap(err, "setting labels in manifests")
+	}
+
+	for _, transform := range manifestTransforms {
+		manifests, err = transform(manifests, builds, h.insecureRegistries)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to transform manifests")
+		}
+	}
+
+	if err := h.kubectlFor(r).Apply(ctx, out, manifests); err != nil {
+		return nil, errors.Wrap(err, "kubectl error")
+	}
+
+	return parseReleaseInfo(h.releaseNamespace(r), bufio.NewReader(strings.NewReader(manifests.String()))), nil
+}
+
+// kubectlFor returns the kubectl CLI used to deploy a rendered release.
+// Each release gets its own CLI since releases can be deployed in parallel.
+func (h *HelmDeployer) kubectlFor(r latest.HelmRelease) *kubectl.CLI {
+	h.kubectlsLock.Lock()
+	defer h.kubectlsLock.Unlock()
+
+	if h.kubectls == nil {
+		h.kubectls = map[string]*kubectl.CLI{}
+	}
+
+	cli, found := h.kubectls[r.Name]
+	if !found {
+		releaseName, err := evaluateReleaseName(r.Name)
+		if err != nil {
+			releaseName = r.Name
+		}
+
+		cli = &kubectl.CLI{
+			Namespace:   h.releaseNamespace(r),
+			KubeContext: h.kubeContext,
+			ForceDeploy: h.forceDeploy,
+			Native:      h.nativeApply,
+			StateFile:   appliedFile(h.appliedDir, "helm-"+releaseName),
+			PruneDryRun: h.pruneDryRun,
+		}
+		h.kubectls[r.Name] = cli
+	}
+
+	return cli
+}
+
+// templatedImageValues evaluates the templated image configuration for an image
+// and returns the values to set, under the given key, as `--set-string` arguments.
+// Each value is set with its own argument and escaped, so that helm doesn't
+// split values that contain commas.
+func templatedImageValues(key string, image string, config *latest.HelmTemplatedConfig) ([]string, error) {
+	data, err := util.NewImageTemplateData(image)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for path := range config.Values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var args []string
+	for _, path := range paths {
+		tmpl, err := util.ParseImageTemplate(config.Values[path])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing template for %s", path)
+		}
+		value, err := util.ExecuteImageTemplate(tmpl, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "evaluating template for %s", path)
+		}
+		args = append(args, "--set-string", fmt.Sprintf("%s.%s=%s", key, path, escapeSetValue(value)))
+	}
+
+	return args, nil
+}
+
+// escapeSetValue escapes the characters that helm's `--set` parser
+// would otherwise interpret in a value.
+func escapeSetValue(value string) string {
+	return setValueEscaper.Replace(value)
 }
 
+var setValueEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`)
+
 func createEnvVarMap(imageName string, digest string) map[string]string {
 	customMap := map[string]string{}
 	customMap["IMAGE_NAME"] = imageName
@@ -372,9 +714,15 @@ func (h *HelmDeployer) packageChart(ctx context.Context, r latest.HelmRelease) (
 	return filepath.Join(tmp, fpath), nil
 }
 
-func (h *HelmDeployer) getReleaseInfo(ctx context.Context, release string) (*bufio.Reader, error) {
+func (h *HelmDeployer) getReleaseInfo(ctx context.Context, namespace string, release string) (*bufio.Reader, error) {
+	// Helm 3 prints the manifests only with `get manifest`.
+	args := []string{"get", release}
+	if h.isHelm3(ctx) {
+		args = namespaced([]string{"get", "manifest", release}, namespace)
+	}
+
 	var releaseInfo bytes.Buffer
-	if err := h.helm(ctx, &releaseInfo, false, "get", release); err != nil {
+	if err := h.helm(ctx, &releaseInfo, false, args...); err != nil {
 		return nil, fmt.Errorf("error retrieving helm deployment info: %s", releaseInfo.String())
 	}
 	return bufio.NewReader(&releaseInfo), nil
@@ -384,7 +732,7 @@ func (h *HelmDeployer) getReleaseInfo(ctx context.Context, release string) (*buf
 // Skaffold labels will be applied to each deployed k8s object
 // Since helm isn't always consistent with retrieving results, don't return errors here
 func (h *HelmDeployer) getDeployResults(ctx context.Context, namespace string, release string) []Artifact {
-	b, err := h.getReleaseInfo(ctx, release)
+	b, err := h.getReleaseInfo(ctx, namespace, release)
 	if err != nil {
 		logrus.Warnf(err.Error())
 		return nil
@@ -398,13 +746,48 @@ func (h *HelmDeployer) deleteRelease(ctx context.Context, out io.Writer, r lates
 		return errors.Wrap(err, "cannot parse the release name template")
 	}
 
-	if err := h.helm(ctx, out, false, "delete", releaseName, "--purge"); err != nil {
+	args := []string{"delete", releaseName, "--purge"}
+	if h.isHelm3(ctx) {
+		args = namespaced([]string{"uninstall", releaseName}, h.releaseNamespace(r))
+	}
+
+	if err := h.helm(ctx, out, false, args...); err != nil {
 		logrus.Debugf("deleting release %s: %v\n", releaseName, err)
 	}
 
 	return nil
 }
 
+// cleanupRendered deletes what was recorded as deployed for each release.
+// Releases are not rendered again: without the builds, the manifests
+// would differ from what was deployed.
+func (h *HelmDeployer) cleanupRendered(ctx context.Context, out io.Writer) error {
+	for _, r := range h.Releases {
+		releaseName, _ := evaluateReleaseName(r.Name)
+
+		cli := h.kubectlFor(r)
+		manifests, err := cli.Applied()
+		if err != nil {
+			return errors.Wrapf(err, "deleting %s", releaseName)
+		}
+
+		if len(manifests) == 0 {
+			logrus.Debugln("Nothing was recorded as deployed for release", releaseName)
+			continue
+		}
+
+		if err := cli.Delete(ctx, out, manifests); err != nil {
+			return errors.Wrapf(err, "deleting %s", releaseName)
+		}
+
+		if err := cli.ForgetApplied(); err != nil {
+			return errors.Wrapf(err, "deleting %s", releaseName)
+		}
+	}
+
+	return nil
+}
+
 func (h *HelmDeployer) joinTagsToBuildResult(builds []build.Artifact, params map[string]string) (map[string]build.Artifact, error) {
 	imageToBuildResult := map[string]build.Artifact{}
 	for _, b := range builds {
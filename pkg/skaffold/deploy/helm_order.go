@@ -0,0 +1,286 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// releaseDeployer deploys a single release and returns the deployed resources.
+type releaseDeployer func(ctx context.Context, out io.Writer, r latest.HelmRelease, builds []build.Artifact) ([]Artifact, error)
+
+// releaseReadyTimeout is how long a release can take to be ready.
+const releaseReadyTimeout = 5 * time.Minute
+
+// waitForRelease waits for the workloads of a release to be ready. It's a var for testing.
+var waitForRelease = func(ctx context.Context, out io.Writer, release string, resources []Artifact) error {
+	color.Default.Fprintf(out, "Waiting for release %s to be ready\n", release)
+
+	client, err := kubernetes.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+
+	for _, res := range resources {
+		ns := res.Namespace
+		if accessor, err := meta.Accessor(res.Obj); err == nil && accessor.GetNamespace() != "" {
+			ns = accessor.GetNamespace()
+		}
+		if ns == "" {
+			ns = currentNamespace()
+		}
+
+		if err := kubernetes.WaitForWorkloadReady(ctx, client, ns, res.Obj, releaseReadyTimeout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// currentNamespace is the namespace of the current kubernetes context.
+func currentNamespace() string {
+	cfg, err := kubectx.CurrentConfig()
+	if err != nil {
+		return "default"
+	}
+
+	if current, found := cfg.Contexts[cfg.CurrentContext]; found && current.Namespace != "" {
+		return current.Namespace
+	}
+	return "default"
+}
+
+// deployReleases deploys the releases level by level. Releases of a same level
+// are deployed in parallel. When a release fails, the releases that depend on it,
+// directly or not, are skipped. The others are still deployed.
+// Releases with `wait: true` must be ready before their dependents are deployed,
+// whether they are installed by helm or rendered and applied.
+func (h *HelmDeployer) deployReleases(ctx context.Context, out io.Writer, builds []build.Artifact, deploy releaseDeployer) ([]Artifact, error) {
+	dependencies, err := releaseDependencies(h.Releases)
+	if err != nil {
+		return nil, err
+	}
+
+	levels, err := releaseLevels(h.Releases, dependencies)
+	if err != nil {
+		return nil, err
+	}
+
+	hasDependents := make([]bool, len(h.Releases))
+	for _, indexes := range dependencies {
+		for _, i := range indexes {
+			hasDependents[i] = true
+		}
+	}
+
+	var dRes []Artifact
+	var errs []error
+	failed := make([]bool, len(h.Releases))
+
+	for _, level := range levels {
+		var releases []int
+		for _, i := range level {
+			if dependency, found := failedDependency(dependencies[i], failed); found {
+				color.Default.Fprintf(out, "Skipping release %s: release %s failed to deploy\n", releaseName(h.Releases[i]), releaseName(h.Releases[dependency]))
+				failed[i] = true
+				continue
+			}
+			releases = append(releases, i)
+		}
+
+		results, levelErrs := h.deployLevel(ctx, out, builds, releases, deploy)
+		for j, i := range releases {
+			if levelErrs[j] != nil {
+				errs = append(errs, errors.Wrapf(levelErrs[j], "deploying %s", releaseName(h.Releases[i])))
+				failed[i] = true
+				continue
+			}
+			dRes = append(dRes, results[j]...)
+
+			if h.Releases[i].Wait && hasDependents[i] {
+				if err := waitForRelease(ctx, out, releaseName(h.Releases[i]), results[j]); err != nil {
+					errs = append(errs, errors.Wrapf(err, "waiting for %s", releaseName(h.Releases[i])))
+					failed[i] = true
+				}
+			}
+		}
+	}
+
+	return dRes, joinErrors(errs)
+}
+
+// deployLevel deploys releases in parallel. Their output is buffered
+// and printed in declaration order, once they are all deployed.
+func (h *HelmDeployer) deployLevel(ctx context.Context, out io.Writer, builds []build.Artifact, releases []int, deploy releaseDeployer) ([][]Artifact, []error) {
+	results := make([][]Artifact, len(releases))
+	errs := make([]error, len(releases))
+
+	if len(releases) == 1 {
+		results[0], errs[0] = deploy(ctx, out, h.Releases[releases[0]], builds)
+		return results, errs
+	}
+
+	outputs := make([]bytes.Buffer, len(releases))
+
+	var wg sync.WaitGroup
+	for j, i := range releases {
+		wg.Add(1)
+		go func(j, i int) {
+			defer wg.Done()
+			results[j], errs[j] = deploy(ctx, &outputs[j], h.Releases[i], builds)
+		}(j, i)
+	}
+	wg.Wait()
+
+	for j := range outputs {
+		if _, err := outputs[j].WriteTo(out); err != nil {
+			errs[j] = err
+		}
+	}
+
+	return results, errs
+}
+
+// releaseDependencies returns, for each release, the indexes of the releases it depends on.
+// When no release declares dependencies, each release depends on the previous one, so that
+// releases are deployed one at a time, in declaration order.
+func releaseDependencies(releases []latest.HelmRelease) ([][]int, error) {
+	dependencies := make([][]int, len(releases))
+
+	if !hasDependencies(releases) {
+		for i := 1; i < len(releases); i++ {
+			dependencies[i] = []int{i - 1}
+		}
+		return dependencies, nil
+	}
+
+	indexes := map[string]int{}
+	for i, r := range releases {
+		indexes[r.Name] = i
+	}
+
+	for i, r := range releases {
+		for _, name := range r.DependsOn {
+			j, found := indexes[name]
+			if !found || j == i {
+				return nil, fmt.Errorf("release %s depends on unknown release %s", r.Name, name)
+			}
+			dependencies[i] = append(dependencies[i], j)
+		}
+	}
+
+	return dependencies, nil
+}
+
+// releaseLevels groups the releases into levels. A release only depends
+// on releases of the previous levels. Declaration order is kept within a level.
+func releaseLevels(releases []latest.HelmRelease, dependencies [][]int) ([][]int, error) {
+	var levels [][]int
+	placed := make([]bool, len(releases))
+
+	for remaining := len(releases); remaining > 0; {
+		var level []int
+		for i := range releases {
+			if !placed[i] && allPlaced(dependencies[i], placed) {
+				level = append(level, i)
+			}
+		}
+
+		if len(level) == 0 {
+			var names []string
+			for i, r := range releases {
+				if !placed[i] {
+					names = append(names, r.Name)
+				}
+			}
+			return nil, fmt.Errorf("cycle detected in the dependencies of releases: %s", strings.Join(names, ", "))
+		}
+
+		for _, i := range level {
+			placed[i] = true
+		}
+		remaining -= len(level)
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+func hasDependencies(releases []latest.HelmRelease) bool {
+	for _, r := range releases {
+		if len(r.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func allPlaced(indexes []int, placed []bool) bool {
+	for _, i := range indexes {
+		if !placed[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func failedDependency(dependencies []int, failed []bool) (int, bool) {
+	for _, i := range dependencies {
+		if failed[i] {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func releaseName(r latest.HelmRelease) string {
+	name, err := evaluateReleaseName(r.Name)
+	if err != nil {
+		return r.Name
+	}
+	return name
+}
+
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return errors.New(strings.Join(messages, "\n"))
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestReleaseLevels(t *testing.T) {
+	tests := []struct {
+		description string
+		releases    []latest.HelmRelease
+		expected    [][]int
+		shouldErr   bool
+	}{
+		{
+			description: "no dependencies means declaration order",
+			releases:    []latest.HelmRelease{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			expected:    [][]int{{0}, {1}, {2}},
+		},
+		{
+			description: "independent releases share a level",
+			releases: []latest.HelmRelease{
+				{Name: "app1", DependsOn: []string{"db"}},
+				{Name: "db"},
+				{Name: "app2", DependsOn: []string{"db"}},
+				{Name: "app3", DependsOn: []string{"db"}},
+			},
+			expected: [][]int{{1}, {0, 2, 3}},
+		},
+		{
+			description: "transitive dependencies",
+			releases: []latest.HelmRelease{
+				{Name: "frontend", DependsOn: []string{"backend"}},
+				{Name: "backend", DependsOn: []string{"db", "cache"}},
+				{Name: "db"},
+				{Name: "cache"},
+			},
+			expected: [][]int{{2, 3}, {1}, {0}},
+		},
+		{
+			description: "unknown dependency",
+			releases:    []latest.HelmRelease{{Name: "app", DependsOn: []string{"db"}}},
+			shouldErr:   true,
+		},
+		{
+			description: "cycle",
+			releases: []latest.HelmRelease{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"a"}},
+				{Name: "c"},
+			},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			dependencies, err := releaseDependencies(test.releases)
+			var levels [][]int
+			if err == nil {
+				levels, err = releaseLevels(test.releases, dependencies)
+			}
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, levels)
+		})
+	}
+}
+
+func TestDeployReleasesSkipsDependentsOfFailedReleases(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		deployer := &HelmDeployer{
+			HelmDeploy: &latest.HelmDeploy{
+				Releases: []latest.HelmRelease{
+					{Name: "db"},
+					{Name: "broker"},
+					{Name: "app1", DependsOn: []string{"db"}},
+					{Name: "app2", DependsOn: []string{"db", "broker"}},
+					{Name: "frontend", DependsOn: []string{"app2"}},
+				},
+			},
+		}
+
+		var lock sync.Mutex
+		var deployed []string
+		deploy := func(_ context.Context, out io.Writer, r latest.HelmRelease, _ []build.Artifact) ([]Artifact, error) {
+			fmt.Fprintf(out, "deploying %s\n", r.Name)
+			if r.Name == "broker" {
+				return nil, errors.New("timeout")
+			}
+
+			lock.Lock()
+			deployed = append(deployed, r.Name)
+			lock.Unlock()
+			return []Artifact{{Namespace: r.Name}}, nil
+		}
+
+		var out bytes.Buffer
+		results, err := deployer.deployReleases(context.Background(), &out, nil, deploy)
+
+		t.CheckErrorContains("deploying broker: timeout", err)
+		t.CheckDeepEqual([]Artifact{{Namespace: "db"}, {Namespace: "app1"}}, results)
+		t.CheckDeepEqual([]string{"db", "app1"}, deployed)
+		t.CheckDeepEqual("deploying db\ndeploying broker\nSkipping release app2: release broker failed to deploy\ndeploying app1\nSkipping release frontend: release app2 failed to deploy\n", out.String())
+	})
+}
+
+func TestDeployReleasesWaitsBetweenLevels(t *testing.T) {
+	tests := []struct {
+		description string
+		waitErr     error
+		shouldErr   bool
+		expected    []string
+	}{
+		{
+			description: "dependents are deployed once the release is ready",
+			expected:    []string{"deploy cache", "deploy db", "wait db", "deploy app"},
+		},
+		{
+			description: "dependents are skipped when the release is not ready",
+			waitErr:     errors.New("timeout"),
+			shouldErr:   true,
+			expected:    []string{"deploy cache", "deploy db", "wait db"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			deployer := &HelmDeployer{
+				HelmDeploy: &latest.HelmDeploy{
+					Releases: []latest.HelmRelease{
+						{Name: "db", Wait: true},
+						{Name: "cache", Wait: true},
+						{Name: "app", DependsOn: []string{"db"}, Wait: true},
+					},
+				},
+			}
+
+			var lock sync.Mutex
+			var steps []string
+			step := func(s string) {
+				lock.Lock()
+				steps = append(steps, s)
+				lock.Unlock()
+			}
+
+			t.Override(&waitForRelease, func(_ context.Context, _ io.Writer, release string, resources []Artifact) error {
+				t.CheckDeepEqual([]Artifact{{Namespace: release}}, resources)
+				step("wait " + release)
+				return test.waitErr
+			})
+			deploy := func(_ context.Context, _ io.Writer, r latest.HelmRelease, _ []build.Artifact) ([]Artifact, error) {
+				step("deploy " + r.Name)
+				return []Artifact{{Namespace: r.Name}}, nil
+			}
+
+			_, err := deployer.deployReleases(context.Background(), ioutil.Discard, nil, deploy)
+
+			// db and cache are deployed in parallel.
+			sort.Strings(steps[:2])
+
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.expected, steps)
+		})
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/blang/semver"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+)
+
+// addRepositories adds the chart repositories and updates them.
+// This is done once, before the first deployment.
+func (h *HelmDeployer) addRepositories(ctx context.Context, out io.Writer) error {
+	h.repositoriesLock.Lock()
+	defer h.repositoriesLock.Unlock()
+
+	if h.repositoriesAdded || len(h.Repositories) == 0 {
+		return nil
+	}
+
+	for _, repo := range h.Repositories {
+		args, password, err := repoAddArgs(repo)
+		if err != nil {
+			return errors.Wrapf(err, "chart repository %s", repo.Name)
+		}
+
+		// The password is read from stdin, so that it's neither logged
+		// nor visible in the list of processes.
+		var in io.Reader
+		if password != "" {
+			if h.helmVersion(ctx).LT(helm32) {
+				return fmt.Errorf("chart repository %s: a password requires helm 3.2 or later", repo.Name)
+			}
+			in = strings.NewReader(password)
+		}
+
+		if err := h.helmWithInput(ctx, out, in, args...); err != nil {
+			return errors.Wrapf(err, "adding chart repository %s", repo.Name)
+		}
+	}
+
+	if err := h.helm(ctx, out, false, "repo", "update"); err != nil {
+		return errors.Wrap(err, "updating chart repositories")
+	}
+
+	h.repositoriesAdded = true
+	return nil
+}
+
+// repoAddArgs returns the arguments to add a chart repository
+// and the password, if any, to pass on stdin.
+func repoAddArgs(repo latest.HelmRepository) ([]string, string, error) {
+	args := []string{"repo", "add", repo.Name, repo.URL}
+
+	username, err := concretize(repo.Username)
+	if err != nil {
+		return nil, "", errors.Wrap(err, `concretize "username" template`)
+	}
+	if username != "" {
+		args = append(args, "--username", username)
+	}
+
+	password, err := concretize(repo.Password)
+	if err != nil {
+		return nil, "", errors.Wrap(err, `concretize "password" template`)
+	}
+	if password != "" {
+		args = append(args, "--password-stdin")
+	}
+
+	return args, password, nil
+}
+
+// chartURL returns the URL a remote chart is downloaded from: the chart path
+// itself or the URL of the chart repository it's part of.
+// It returns false when the URL is unknown, because the repository was not
+// added by Skaffold.
+func (h *HelmDeployer) chartURL(r latest.HelmRelease) (string, bool) {
+	if strings.Contains(r.ChartPath, "://") {
+		return r.ChartPath, true
+	}
+
+	parts := strings.SplitN(r.ChartPath, "/", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+
+	for _, repo := range h.Repositories {
+		if repo.Name == parts[0] {
+			return strings.TrimSuffix(repo.URL, "/") + "/" + parts[1], true
+		}
+	}
+
+	return "", false
+}
+
+// isCacheable tells if the chart of a release can be downloaded once and reused.
+// Only remote charts with an exact version, from a known URL, can.
+func (h *HelmDeployer) isCacheable(r latest.HelmRelease) bool {
+	if !r.Remote || r.Version == "" {
+		return false
+	}
+
+	if _, found := h.chartURL(r); !found {
+		return false
+	}
+
+	_, err := semver.Parse(r.Version)
+	return err == nil
+}
+
+// cachedChart returns the path to the archive of a remote chart, in the version
+// required by the release. The archive is only downloaded the first time.
+// Releases deployed in parallel can share a chart, so the chart is downloaded
+// to a temporary directory that is then renamed: the cache never contains a
+// partially downloaded archive.
+func (h *HelmDeployer) cachedChart(ctx context.Context, out io.Writer, r latest.HelmRelease) (string, error) {
+	cacheDir, err := h.chartsCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	url, _ := h.chartURL(r)
+	dir := chartCacheDir(cacheDir, url, r.Version)
+	if archive, found := chartArchive(dir); found {
+		return archive, nil
+	}
+
+	parent := filepath.Dir(dir)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", errors.Wrapf(err, "creating chart cache directory %s", parent)
+	}
+
+	tmpDir, err := ioutil.TempDir(parent, "download")
+	if err != nil {
+		return "", errors.Wrap(err, "creating temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := h.helm(ctx, out, false, "fetch", r.ChartPath, "--version", r.Version, "--destination", tmpDir); err != nil {
+		return "", errors.Wrapf(err, "downloading chart %s version %s", r.ChartPath, r.Version)
+	}
+
+	if _, found := chartArchive(tmpDir); !found {
+		return "", fmt.Errorf("cannot locate downloaded chart archive in %s", tmpDir)
+	}
+
+	// Renaming fails if another release has cached the chart in the meantime.
+	if err := os.Rename(tmpDir, dir); err != nil {
+		if _, found := chartArchive(dir); !found {
+			return "", errors.Wrapf(err, "caching chart %s version %s", r.ChartPath, r.Version)
+		}
+	}
+
+	archive, found := chartArchive(dir)
+	if !found {
+		return "", fmt.Errorf("cannot locate downloaded chart archive in %s", dir)
+	}
+
+	return archive, nil
+}
+
+// FetchRemoteCharts downloads the charts of the remote releases to a directory,
+// each in its own subdirectory. It returns the path to the archive of each
+// chart, by release name.
+func (h *HelmDeployer) FetchRemoteCharts(ctx context.Context, out io.Writer, dir string) (map[string]string, error) {
+	charts := map[string]string{}
+
+	for i, r := range h.Releases {
+		if !r.Remote {
+			continue
+		}
+
+		if err := h.addRepositories(ctx, out); err != nil {
+			return nil, err
+		}
+
+		releaseDir := filepath.Join(dir, strconv.Itoa(i))
+		if err := os.MkdirAll(releaseDir, 0755); err != nil {
+			return nil, errors.Wrapf(err, "creating chart directory %s", releaseDir)
+		}
+
+		archive, err := h.fetchChart(ctx, out, r, releaseDir)
+		if err != nil {
+			return nil, err
+		}
+		charts[r.Name] = archive
+	}
+
+	return charts, nil
+}
+
+// fetchChart downloads the chart of a remote release to a directory
+// and returns the path to the archive.
+func (h *HelmDeployer) fetchChart(ctx context.Context, out io.Writer, r latest.HelmRelease, dir string) (string, error) {
+	args := []string{"fetch", r.ChartPath}
+	if r.Version != "" {
+		args = append(args, "--version", r.Version)
+	}
+	args = append(args, "--destination", dir)
+
+	if err := h.helm(ctx, out, false, args...); err != nil {
+		return "", errors.Wrapf(err, "downloading chart %s", r.ChartPath)
+	}
+
+	archive, found := chartArchive(dir)
+	if !found {
+		return "", fmt.Errorf("cannot locate downloaded chart archive in %s", dir)
+	}
+
+	return archive, nil
+}
+
+// chartCacheDir returns the directory where a version of a chart is cached.
+// Charts are cached by URL, since the same repository name can refer to
+// different repositories in different projects.
+func chartCacheDir(cacheDir, url, version string) string {
+	hash := sha256.Sum256([]byte(url))
+	return filepath.Join(cacheDir, hex.EncodeToString(hash[:])[:16], version)
+}
+
+func chartArchive(dir string) (string, bool) {
+	archives, err := filepath.Glob(filepath.Join(dir, "*.tgz"))
+	if err != nil || len(archives) != 1 {
+		return "", false
+	}
+
+	return archives[0], true
+}
+
+func (h *HelmDeployer) chartsCacheDir() (string, error) {
+	if h.chartsDir != "" {
+		return h.chartsDir, nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", errors.Wrap(err, "retrieving home directory")
+	}
+
+	return filepath.Join(home, constants.DefaultSkaffoldDir, constants.DefaultChartsDir), nil
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestHelmRepositories(t *testing.T) {
+	var tests = []struct {
+		description      string
+		version          string
+		shouldErr        bool
+		expectedCommands []string
+	}{
+		{
+			description: "password on stdin",
+			version:     "v3.2.0+ge29ce2a",
+			expectedCommands: []string{
+				"add stable https://kubernetes-charts.storage.googleapis.com",
+				"add private https://charts.example.com --username user --password-stdin < password",
+				"update",
+			},
+		},
+		{
+			description: "password requires helm 3.2",
+			shouldErr:   true,
+			expectedCommands: []string{
+				"add stable https://kubernetes-charts.storage.googleapis.com",
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.SetEnvs(map[string]string{"REPO_USER": "user", "REPO_PASSWORD": "password"})
+			helm := &MockHelm{t: t.T, version: test.version}
+			t.Override(&util.DefaultExecCommand, helm)
+
+			runContext := makeRunContext(&latest.HelmDeploy{
+				Repositories: []latest.HelmRepository{
+					{Name: "stable", URL: "https://kubernetes-charts.storage.googleapis.com"},
+					{Name: "private", URL: "https://charts.example.com", Username: "{{.REPO_USER}}", Password: "{{.REPO_PASSWORD}}"},
+				},
+				Releases: testDeployRemoteChart.Releases,
+			}, false)
+			deployer := NewHelmDeployer(runContext)
+
+			event.InitializeState(runContext)
+			err := deployer.Deploy(context.Background(), ioutil.Discard, testBuilds, nil)
+			t.CheckError(test.shouldErr, err)
+			if !test.shouldErr {
+				err = deployer.Deploy(context.Background(), ioutil.Discard, testBuilds, nil)
+				t.CheckError(false, err)
+			}
+
+			t.CheckDeepEqual(test.expectedCommands, helm.repoCommands)
+		})
+	}
+}
+
+func TestHelmCachedChart(t *testing.T) {
+	var tests = []struct {
+		description     string
+		version         string
+		repositories    []latest.HelmRepository
+		expectedFetched int
+		expectedArgs    func(cacheDir *testutil.TempDir) []string
+	}{
+		{
+			description:     "exact version is downloaded once",
+			version:         "2.3.1",
+			repositories:    []latest.HelmRepository{{Name: "stable", URL: "https://charts.example.com/"}},
+			expectedFetched: 1,
+			expectedArgs: func(cacheDir *testutil.TempDir) []string {
+				return []string{filepath.Join(chartCacheDir(cacheDir.Root(), "https://charts.example.com/chartmuseum", "2.3.1"), "chart.tgz")}
+			},
+		},
+		{
+			description:     "version range is not cached",
+			version:         "^2.3.0",
+			repositories:    []latest.HelmRepository{{Name: "stable", URL: "https://charts.example.com"}},
+			expectedFetched: 0,
+			expectedArgs: func(*testutil.TempDir) []string {
+				return []string{"--version", "^2.3.0", "stable/chartmuseum"}
+			},
+		},
+		{
+			description:     "unknown repository is not cached",
+			version:         "2.3.1",
+			expectedFetched: 0,
+			expectedArgs: func(*testutil.TempDir) []string {
+				return []string{"--version", "2.3.1", "stable/chartmuseum"}
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			cacheDir := t.NewTempDir()
+			expectedArgs := test.expectedArgs(cacheDir)
+
+			helm := &MockHelm{
+				t:         t.T,
+				getResult: fmt.Errorf("not found"),
+				installMatcher: func(cmd *exec.Cmd) bool {
+					for _, arg := range expectedArgs {
+						if !hasArg(cmd, arg) {
+							return false
+						}
+					}
+					return true
+				},
+			}
+			t.Override(&util.DefaultExecCommand, helm)
+
+			runContext := makeRunContext(&latest.HelmDeploy{
+				Repositories: test.repositories,
+				Releases: []latest.HelmRelease{{
+					Name:      "skaffold-helm-remote",
+					ChartPath: "stable/chartmuseum",
+					Version:   test.version,
+					Remote:    true,
+				}},
+			}, false)
+			deployer := NewHelmDeployer(runContext)
+			deployer.chartsDir = cacheDir.Root()
+
+			event.InitializeState(runContext)
+			for i := 0; i < 2; i++ {
+				err := deployer.Deploy(context.Background(), ioutil.Discard, testBuilds, nil)
+				t.CheckError(false, err)
+			}
+
+			t.CheckDeepEqual(test.expectedFetched, helm.fetched)
+		})
+	}
+}
+
+func TestHelmCachedChartByURL(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		cacheDir := t.NewTempDir()
+		helm := &MockHelm{t: t.T, getResult: fmt.Errorf("not found")}
+		t.Override(&util.DefaultExecCommand, helm)
+
+		// The same repository name refers to different repositories
+		for _, url := range []string{"https://charts.example.com", "https://other.example.com"} {
+			runContext := makeRunContext(&latest.HelmDeploy{
+				Repositories: []latest.HelmRepository{{Name: "stable", URL: url}},
+				Releases: []latest.HelmRelease{{
+					Name:      "skaffold-helm-remote",
+					ChartPath: "stable/chartmuseum",
+					Version:   "2.3.1",
+					Remote:    true,
+				}},
+			}, false)
+			deployer := NewHelmDeployer(runContext)
+			deployer.chartsDir = cacheDir.Root()
+
+			event.InitializeState(runContext)
+			err := deployer.Deploy(context.Background(), ioutil.Discard, testBuilds, nil)
+			t.CheckError(false, err)
+		}
+
+		t.CheckDeepEqual(2, helm.fetched)
+	})
+}
+
+func TestHelmCachedChartInParallel(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		cacheDir := t.NewTempDir()
+		helm := &MockHelm{t: t.T}
+		t.Override(&util.DefaultExecCommand, helm)
+
+		release := latest.HelmRelease{Name: "remote", ChartPath: "stable/chartmuseum", Version: "2.3.1", Remote: true}
+		deployer := NewHelmDeployer(makeRunContext(&latest.HelmDeploy{
+			Repositories: []latest.HelmRepository{{Name: "stable", URL: "https://charts.example.com"}},
+			Releases:     []latest.HelmRelease{release, release},
+		}, false))
+		deployer.chartsDir = cacheDir.Root()
+
+		var wg sync.WaitGroup
+		archives := make([]string, 2)
+		errs := make([]error, 2)
+		for i := range archives {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				archives[i], errs[i] = deployer.cachedChart(context.Background(), ioutil.Discard, release)
+			}(i)
+		}
+		wg.Wait()
+
+		dir := chartCacheDir(cacheDir.Root(), "https://charts.example.com/chartmuseum", "2.3.1")
+		expected := filepath.Join(dir, "chart.tgz")
+		t.CheckErrorAndDeepEqual(false, errs[0], expected, archives[0])
+		t.CheckErrorAndDeepEqual(false, errs[1], expected, archives[1])
+
+		// No temporary download is left behind
+		files, err := ioutil.ReadDir(filepath.Dir(dir))
+		t.CheckError(false, err)
+		t.CheckDeepEqual(1, len(files))
+	})
+}
+
+func TestHelmFetchRemoteCharts(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		dir := t.NewTempDir()
+		helm := &MockHelm{t: t.T}
+		t.Override(&util.DefaultExecCommand, helm)
+
+		deployer := NewHelmDeployer(makeRunContext(&latest.HelmDeploy{
+			Repositories: []latest.HelmRepository{{Name: "stable", URL: "https://charts.example.com"}},
+			Releases: []latest.HelmRelease{
+				{Name: "local", ChartPath: "examples/test"},
+				{Name: "remote", ChartPath: "stable/chartmuseum", Version: "2.3.1", Remote: true},
+			},
+		}, false))
+
+		charts, err := deployer.FetchRemoteCharts(context.Background(), ioutil.Discard, dir.Root())
+
+		t.CheckErrorAndDeepEqual(false, err, map[string]string{"remote": dir.Path("1/chart.tgz")}, charts)
+		t.CheckDeepEqual(1, helm.fetched)
+		t.CheckDeepEqual([]string{"add stable https://charts.example.com", "update"}, helm.repoCommands)
+	})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package deploy
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
@@ -24,11 +25,13 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
@@ -37,6 +40,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var testBuilds = []build.Artifact{
@@ -103,6 +107,29 @@ var testDeploySkipBuildDependenciesConfig = &latest.HelmDeploy{
 	},
 }
 
+var testDeployTemplatedImageConfig = &latest.HelmDeploy{
+	Releases: []latest.HelmRelease{
+		{
+			Name:      "skaffold-helm",
+			ChartPath: "examples/test",
+			Values: map[string]string{
+				"backend.containers[0].image": "skaffold-helm",
+			},
+			ImageStrategy: latest.HelmImageStrategy{
+				HelmImageConfig: latest.HelmImageConfig{
+					HelmTemplatedConfig: &latest.HelmTemplatedConfig{
+						Values: map[string]string{
+							"registry":   "{{.Registry}}",
+							"repository": "{{.Repository}}",
+							"digest":     "{{.Digest}}",
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
 var testDeployHelmStyleConfig = &latest.HelmDeploy{
 	Releases: []latest.HelmRelease{
 		{
@@ -271,6 +298,7 @@ MANIFEST:
 // TestMain disables logrus output before running tests.
 func TestMain(m *testing.M) {
 	logrus.SetOutput(ioutil.Discard)
+	appliedRootDir = func() (string, error) { return "", nil }
 	os.Exit(m.Run())
 }
 
@@ -372,6 +400,25 @@ func TestHelmDeploy(t *testing.T) {
 			runContext: makeRunContext(testDeployHelmStyleConfig, false),
 			builds:     testBuilds,
 		},
+		{
+			description: "deploy with templated image values",
+			cmd: &MockHelm{
+				t:         t,
+				getResult: fmt.Errorf("not found"),
+				installMatcher: func(cmd *exec.Cmd) bool {
+					return hasArg(cmd, "--set-string") &&
+						hasArg(cmd, "backend.containers[0].image.digest=sha256:4bf0b5ebb2ba63d0e2c7b3e4ca5da30a4c2b9f0d2fca1a5ea1c5e5e3c2d4c1d3") &&
+						hasArg(cmd, "backend.containers[0].image.registry=gcr.io") &&
+						hasArg(cmd, "backend.containers[0].image.repository=project/skaffold-helm")
+				},
+				upgradeResult: fmt.Errorf("should not have called upgrade"),
+			},
+			runContext: makeRunContext(testDeployTemplatedImageConfig, false),
+			builds: []build.Artifact{{
+				ImageName: "skaffold-helm",
+				Tag:       "gcr.io/project/skaffold-helm:v1@sha256:4bf0b5ebb2ba63d0e2c7b3e4ca5da30a4c2b9f0d2fca1a5ea1c5e5e3c2d4c1d3",
+			}},
+		},
 		{
 			description: "get success should upgrade by force, not install",
 			cmd: &MockHelm{
@@ -452,6 +499,74 @@ func TestHelmDeploy(t *testing.T) {
 			runContext:  makeRunContext(testDeployWithTemplatedName, false),
 			builds:      testBuilds,
 		},
+		{
+			description: "upgrade with recreatePods",
+			cmd: &MockHelm{
+				t: t,
+				upgradeMatcher: func(cmd *exec.Cmd) bool {
+					return hasArg(cmd, "--recreate-pods")
+				},
+			},
+			runContext: makeRunContext(testDeployRecreatePodsConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "helm3 get failure should install in namespace",
+			cmd: &MockHelm{
+				t:         t,
+				version:   "v3.0.0+ge29ce2a",
+				getResult: fmt.Errorf("not found"),
+				getMatcher: func(cmd *exec.Cmd) bool {
+					return (cmd.Args[4] == "all" || cmd.Args[4] == "manifest") && cmd.Args[5] == "skaffold-helm" && hasArg(cmd, testNamespace)
+				},
+				installMatcher: func(cmd *exec.Cmd) bool {
+					return cmd.Args[4] == "skaffold-helm" && !hasArg(cmd, "--name") && !hasArg(cmd, "--create-namespace") && hasArg(cmd, testNamespace)
+				},
+				upgradeResult: fmt.Errorf("should not have called upgrade"),
+			},
+			runContext: makeRunContext(testDeployConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "helm3.2 install should create namespace",
+			cmd: &MockHelm{
+				t:         t,
+				version:   "v3.2.4+g0ad800e",
+				getResult: fmt.Errorf("not found"),
+				installMatcher: func(cmd *exec.Cmd) bool {
+					return hasArg(cmd, "--create-namespace")
+				},
+				upgradeResult: fmt.Errorf("should not have called upgrade"),
+			},
+			runContext: makeRunContext(testDeployConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "helm3 upgrade should ignore recreatePods",
+			cmd: &MockHelm{
+				t:       t,
+				version: "v3.0.0+ge29ce2a",
+				upgradeMatcher: func(cmd *exec.Cmd) bool {
+					return !hasArg(cmd, "--recreate-pods")
+				},
+				installResult: fmt.Errorf("should not have called install"),
+			},
+			runContext: makeRunContext(testDeployRecreatePodsConfig, false),
+			builds:     testBuilds,
+		},
+		{
+			description: "unknown helm version should assume helm 2",
+			cmd: &MockHelm{
+				t:          t,
+				versionErr: fmt.Errorf("unknown flag"),
+				getResult:  fmt.Errorf("not found"),
+				installMatcher: func(cmd *exec.Cmd) bool {
+					return hasArg(cmd, "--name")
+				},
+			},
+			runContext: makeRunContext(testDeployConfig, false),
+			builds:     testBuilds,
+		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
@@ -470,6 +585,9 @@ type CommandMatcher func(*exec.Cmd) bool
 type MockHelm struct {
 	t *testing.T
 
+	version    string
+	versionErr error
+
 	getResult      error
 	getMatcher     CommandMatcher
 	installResult  error
@@ -477,17 +595,39 @@ type MockHelm struct {
 	upgradeResult  error
 	upgradeMatcher CommandMatcher
 	depResult      error
+	deleteMatcher  CommandMatcher
+
+	templateOut     string
+	templateMatcher CommandMatcher
+	kubectlMatcher  CommandMatcher
+
+	repoCommands []string
+	fetched      int
 
 	packageOut    io.Reader
 	packageResult error
 }
 
 func (m *MockHelm) RunCmdOut(c *exec.Cmd) ([]byte, error) {
-	m.t.Error("Shouldn't be used")
-	return nil, nil
+	if strings.Join(c.Args, " ") != "helm version --client --short" {
+		m.t.Error("Shouldn't be used")
+		return nil, nil
+	}
+
+	if m.version == "" {
+		return []byte("Client: v2.14.1+g5270352\n"), m.versionErr
+	}
+	return []byte(m.version + "\n"), m.versionErr
 }
 
 func (m *MockHelm) RunCmd(c *exec.Cmd) error {
+	if c.Args[0] == "kubectl" {
+		if m.kubectlMatcher != nil && !m.kubectlMatcher(c) {
+			m.t.Errorf("kubectl matcher failed to match cmd")
+		}
+		return nil
+	}
+
 	if len(c.Args) < 3 {
 		m.t.Errorf("Not enough args in command %v", c)
 	}
@@ -520,6 +660,34 @@ func (m *MockHelm) RunCmd(c *exec.Cmd) error {
 		return m.upgradeResult
 	case "dep":
 		return m.depResult
+	case "template":
+		if m.templateMatcher != nil && !m.templateMatcher(c) {
+			m.t.Errorf("template matcher failed to match cmd")
+		}
+		if _, err := io.WriteString(c.Stdout, m.templateOut); err != nil {
+			m.t.Errorf("Failed to write stdout")
+		}
+		return nil
+	case "repo":
+		command := strings.Join(c.Args[4:], " ")
+		if c.Stdin != nil {
+			in, err := ioutil.ReadAll(c.Stdin)
+			if err != nil {
+				m.t.Errorf("Failed to read stdin")
+			}
+			command += " < " + string(in)
+		}
+		m.repoCommands = append(m.repoCommands, command)
+		return nil
+	case "fetch":
+		m.fetched++
+		destination := c.Args[len(c.Args)-1]
+		return ioutil.WriteFile(filepath.Join(destination, "chart.tgz"), nil, 0644)
+	case "delete", "uninstall":
+		if m.deleteMatcher != nil && !m.deleteMatcher(c) {
+			m.t.Errorf("delete matcher failed to match cmd")
+		}
+		return nil
 	case "package":
 		if m.packageOut != nil {
 			if _, err := io.Copy(c.Stdout, m.packageOut); err != nil {
@@ -533,6 +701,271 @@ func (m *MockHelm) RunCmd(c *exec.Cmd) error {
 	}
 }
 
+var renderedTemplate = `---
+# Source: skaffold-helm/templates/pod.yaml
+apiVersion: v1
+kind: Pod
+metadata:
+  name: skaffold-helm
+spec:
+  containers:
+  - name: skaffold-helm
+    image: skaffold-helm
+`
+
+func TestHelmDeployRenderTemplates(t *testing.T) {
+	var tests = []struct {
+		description string
+		version     string
+		template    string
+	}{
+		{
+			description: "helm 2",
+			template:    "template --name skaffold-helm examples/test --namespace " + testNamespace,
+		},
+		{
+			description: "helm 3",
+			version:     "v3.0.0+ge29ce2a",
+			template:    "template skaffold-helm examples/test --namespace " + testNamespace,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			var applied string
+			transformed := false
+
+			t.Override(&manifestTransforms, []ManifestTransform{
+				func(l kubectl.ManifestList, builds []build.Artifact, insecureRegistries map[string]bool) (kubectl.ManifestList, error) {
+					transformed = true
+					return l, nil
+				},
+			})
+			t.Override(&util.DefaultExecCommand, &MockHelm{
+				t:           t.T,
+				version:     test.version,
+				templateOut: renderedTemplate,
+				templateMatcher: func(cmd *exec.Cmd) bool {
+					return strings.HasPrefix(strings.Join(cmd.Args[3:], " "), test.template)
+				},
+				kubectlMatcher: func(cmd *exec.Cmd) bool {
+					buf, _ := ioutil.ReadAll(cmd.Stdin)
+					applied = string(buf)
+					return strings.Join(cmd.Args, " ") == "kubectl --context kubecontext --namespace testNamespace apply -f -"
+				},
+				getResult:     fmt.Errorf("should not have called get"),
+				installResult: fmt.Errorf("should not have called install"),
+				upgradeResult: fmt.Errorf("should not have called upgrade"),
+			})
+
+			runContext := makeRunContext(&latest.HelmDeploy{
+				Releases:        testDeployConfig.Releases,
+				RenderTemplates: true,
+			}, false)
+
+			event.InitializeState(runContext)
+			err := NewHelmDeployer(runContext).Deploy(context.Background(), ioutil.Discard, testBuilds, nil)
+
+			t.CheckError(false, err)
+			t.CheckContains("image: "+testBuilds[0].Tag, applied)
+			t.CheckContains("skaffold.dev/helm-release: skaffold-helm", applied)
+			t.CheckDeepEqual(true, transformed)
+		})
+	}
+}
+
+func TestHelmDeployRenderRemoteChartWithHelm2(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		var template []string
+		helm := &MockHelm{
+			t:           t.T,
+			templateOut: renderedTemplate,
+			templateMatcher: func(cmd *exec.Cmd) bool {
+				template = cmd.Args[3:]
+				return true
+			},
+		}
+		t.Override(&util.DefaultExecCommand, helm)
+
+		runContext := makeRunContext(&latest.HelmDeploy{
+			Releases: []latest.HelmRelease{{
+				Name:      "skaffold-helm-remote",
+				ChartPath: "stable/chartmuseum",
+				Version:   "^2.3.0",
+				Remote:    true,
+			}},
+			RenderTemplates: true,
+		}, false)
+
+		event.InitializeState(runContext)
+		err := NewHelmDeployer(runContext).Deploy(context.Background(), ioutil.Discard, testBuilds, nil)
+
+		// The chart is downloaded and templated from the archive
+		t.CheckError(false, err)
+		t.CheckDeepEqual(1, helm.fetched)
+		t.CheckDeepEqual([]string{"template", "--name", "skaffold-helm-remote"}, template[:3])
+		t.CheckDeepEqual("chart.tgz", filepath.Base(template[3]))
+	})
+}
+
+func TestHelmCleanupRenderTemplates(t *testing.T) {
+	testutil.Run(t, "delete what was applied", func(t *testutil.T) {
+		var kubectlCommands []string
+		templates := 0
+		t.Override(&util.DefaultExecCommand, &MockHelm{
+			t:           t.T,
+			templateOut: renderedTemplate,
+			templateMatcher: func(cmd *exec.Cmd) bool {
+				templates++
+				return true
+			},
+			kubectlMatcher: func(cmd *exec.Cmd) bool {
+				kubectlCommands = append(kubectlCommands, strings.Join(cmd.Args, " "))
+				return true
+			},
+			deleteMatcher: func(cmd *exec.Cmd) bool {
+				return false
+			},
+		})
+
+		runContext := makeRunContext(&latest.HelmDeploy{
+			Releases:        testDeployConfig.Releases,
+			RenderTemplates: true,
+		}, false)
+		deployer := NewHelmDeployer(runContext)
+
+		event.InitializeState(runContext)
+		err := deployer.Deploy(context.Background(), ioutil.Discard, testBuilds, nil)
+		t.CheckError(false, err)
+		err = deployer.Cleanup(context.Background(), ioutil.Discard)
+		t.CheckError(false, err)
+
+		// The release is not rendered again
+		t.CheckDeepEqual(1, templates)
+		t.CheckDeepEqual([]string{
+			"kubectl --context kubecontext --namespace testNamespace apply -f -",
+			"kubectl --context kubecontext --namespace testNamespace delete --ignore-not-found=true -f -",
+		}, kubectlCommands)
+	})
+
+	testutil.Run(t, "nothing recorded", func(t *testutil.T) {
+		t.Override(&util.DefaultExecCommand, &MockHelm{
+			t: t.T,
+			templateMatcher: func(cmd *exec.Cmd) bool {
+				return false
+			},
+			kubectlMatcher: func(cmd *exec.Cmd) bool {
+				return false
+			},
+			deleteMatcher: func(cmd *exec.Cmd) bool {
+				return false
+			},
+		})
+
+		err := NewHelmDeployer(makeRunContext(&latest.HelmDeploy{
+			Releases:        testDeployConfig.Releases,
+			RenderTemplates: true,
+		}, false)).Cleanup(context.Background(), ioutil.Discard)
+
+		t.CheckError(false, err)
+	})
+}
+
+func hasArg(cmd *exec.Cmd, expected string) bool {
+	for _, arg := range cmd.Args {
+		if arg == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHelmCleanup(t *testing.T) {
+	var tests = []struct {
+		description string
+		cmd         util.Command
+	}{
+		{
+			description: "helm2 delete",
+			cmd: &MockHelm{
+				t: t,
+				deleteMatcher: func(cmd *exec.Cmd) bool {
+					return strings.Join(cmd.Args[3:], " ") == "delete skaffold-helm --purge"
+				},
+			},
+		},
+		{
+			description: "helm3 uninstall",
+			cmd: &MockHelm{
+				t:       t,
+				version: "v3.0.0+ge29ce2a",
+				deleteMatcher: func(cmd *exec.Cmd) bool {
+					return strings.Join(cmd.Args[3:], " ") == "uninstall skaffold-helm --namespace "+testNamespace
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&util.DefaultExecCommand, test.cmd)
+
+			err := NewHelmDeployer(makeRunContext(testDeployConfig, false)).Cleanup(context.Background(), ioutil.Discard)
+
+			t.CheckError(false, err)
+		})
+	}
+}
+
+func TestTemplatedImageValues(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		values, err := templatedImageValues("image", "gcr.io/project/app:v1", &latest.HelmTemplatedConfig{
+			Values: map[string]string{
+				"ref":  "{{.Repository}},{{.Tag}}",
+				"name": "{{.Registry}}",
+			},
+		})
+
+		t.CheckErrorAndDeepEqual(false, err, []string{
+			"--set-string", "image.name=gcr.io",
+			"--set-string", `image.ref=project/app\,v1`,
+		}, values)
+	})
+}
+
+func TestParseHelmVersion(t *testing.T) {
+	var tests = []struct {
+		description string
+		output      string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "helm 2",
+			output:      "Client: v2.14.1+g5270352\n",
+			expected:    "2.14.1+g5270352",
+		},
+		{
+			description: "helm 3",
+			output:      "v3.0.0+ge29ce2a\n",
+			expected:    "3.0.0+ge29ce2a",
+		},
+		{
+			description: "invalid",
+			output:      "Error: unknown flag: --client",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			version, err := parseHelmVersion(test.output)
+
+			t.CheckError(test.shouldErr, err)
+			if !test.shouldErr {
+				t.CheckDeepEqual(test.expected, version.String())
+			}
+		})
+	}
+}
+
 func TestParseHelmRelease(t *testing.T) {
 	var tests = []struct {
 		description string
@@ -562,6 +995,36 @@ func TestParseHelmRelease(t *testing.T) {
 	}
 }
 
+func TestParseReleaseInfo(t *testing.T) {
+	var tests = []struct {
+		description string
+		output      string
+		expected    []string
+	}{
+		{
+			description: "helm 2 get",
+			output:      invalidDeployYaml + validDeployYaml + "---" + validServiceYaml,
+			expected:    []string{"skaffold-helm", "skaffold-helm-skaffold-helm"},
+		},
+		{
+			description: "helm 3 get manifest",
+			output:      "---" + validDeployYaml + "---" + validServiceYaml,
+			expected:    []string{"skaffold-helm", "skaffold-helm-skaffold-helm"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			results := parseReleaseInfo(testNamespace, bufio.NewReader(strings.NewReader(test.output)))
+
+			var names []string
+			for _, result := range results {
+				names = append(names, result.Obj.(metav1.Object).GetName())
+			}
+			t.CheckDeepEqual(test.expected, names)
+		})
+	}
+}
+
 func TestExtractChartFilename(t *testing.T) {
 	out, err := extractChartFilename(
 		"Successfully packaged chart and saved it to: /var/folders/gm/rrs_712142x8vymmd7xq7h340000gn/T/foo-1.2.3-dirty.tgz\n",
@@ -605,6 +1068,14 @@ func TestHelmDependencies(t *testing.T) {
 				return []string{"/folder/values.yaml", folder.Path("Chart.yaml")}
 			},
 		},
+		{
+			description:           "requirements and lock files are included",
+			skipBuildDependencies: false,
+			files:                 []string{"Chart.yaml", "requirements.yaml", "requirements.lock", "Chart.lock", "charts/xyz.tgz"},
+			expected: func(folder *testutil.TempDir) []string {
+				return []string{folder.Path("Chart.lock"), folder.Path("Chart.yaml"), folder.Path("requirements.lock"), folder.Path("requirements.yaml")}
+			},
+		},
 		{
 			description:           "no deps for remote chart path",
 			skipBuildDependencies: false,
@@ -24,7 +24,6 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
-	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
@@ -53,12 +52,26 @@ func NewKubectlDeployer(runCtx *runcontext.RunContext) *KubectlDeployer {
 			KubeContext: runCtx.KubeContext,
 			Flags:       runCtx.Cfg.Deploy.KubectlDeploy.Flags,
 			ForceDeploy: runCtx.Opts.ForceDeploy(),
+			Native:      runCtx.Opts.NativeApply,
+			StateFile:   appliedFile(appliedDir(runCtx), "kubectl"),
+			PruneDryRun: runCtx.Opts.PruneDryRun,
 		},
 		defaultRepo:        runCtx.DefaultRepo,
 		insecureRegistries: runCtx.InsecureRegistries,
 	}
 }
 
+// Reconfigure applies a new configuration to the deployer. It still knows
+// what it applied before, so that unchanged manifests are not applied again
+// and removed ones are pruned.
+func (k *KubectlDeployer) Reconfigure(runCtx *runcontext.RunContext) {
+	k.KubectlDeploy = runCtx.Cfg.Deploy.KubectlDeploy
+	k.workingDir = runCtx.WorkingDir
+	k.kubectl.Flags = runCtx.Cfg.Deploy.KubectlDeploy.Flags
+	k.defaultRepo = runCtx.DefaultRepo
+	k.insecureRegistries = runCtx.InsecureRegistries
+}
+
 func (k *KubectlDeployer) Labels() map[string]string {
 	return map[string]string{
 		constants.Labels.Deployer: "kubectl",
@@ -78,16 +91,15 @@ func AddManifestTransform(newTransform ManifestTransform) {
 // Deploy templates the provided manifests with a simple `find and replace` and
 // runs `kubectl apply` on those manifests
 func (k *KubectlDeployer) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []Labeller) error {
-	color.Default.Fprintln(out, "kubectl client version:", k.kubectl.Version(ctx))
-	if err := k.kubectl.CheckVersion(ctx); err != nil {
-		color.Default.Fprintln(out, err)
+	if !k.kubectl.Native {
+		color.Default.Fprintln(out, "kubectl client version:", k.kubectl.Version(ctx))
+		if err := k.kubectl.CheckVersion(ctx); err != nil {
+			color.Default.Fprintln(out, err)
+		}
 	}
 
-	event.DeployInProgress()
-
 	manifests, err := k.readManifests(ctx)
 	if err != nil {
-		event.DeployFailed(err)
 		return errors.Wrap(err, "reading manifests")
 	}
 
@@ -97,13 +109,11 @@ func (k *KubectlDeployer) Deploy(ctx context.Context, out io.Writer, builds []bu
 
 	manifests, err = manifests.ReplaceImages(builds, k.defaultRepo)
 	if err != nil {
-		event.DeployFailed(err)
 		return errors.Wrap(err, "replacing images in manifests")
 	}
 
 	manifests, err = manifests.SetLabels(merge(labellers...))
 	if err != nil {
-		event.DeployFailed(err)
 		return errors.Wrap(err, "setting labels in manifests")
 	}
 
@@ -116,26 +126,17 @@ func (k *KubectlDeployer) Deploy(ctx context.Context, out io.Writer, builds []bu
 
 	err = k.kubectl.Apply(ctx, out, manifests)
 	if err != nil {
-		event.DeployFailed(err)
 		return errors.Wrap(err, "kubectl error")
 	}
 
-	event.DeployComplete()
 	return err
 }
 
 // Cleanup deletes what was deployed by calling Deploy.
 func (k *KubectlDeployer) Cleanup(ctx context.Context, out io.Writer) error {
-	manifests, err := k.readManifests(ctx)
-	if err != nil {
-		return errors.Wrap(err, "reading manifests")
-	}
-
-	if err := k.kubectl.Delete(ctx, out, manifests); err != nil {
-		return errors.Wrap(err, "delete")
-	}
-
-	return nil
+	return deleteApplied(ctx, out, &k.kubectl, func() (kubectl.ManifestList, error) {
+		return k.readManifests(ctx)
+	})
 }
 
 func (k *KubectlDeployer) Dependencies() ([]string, error) {
@@ -38,10 +38,35 @@ type CLI struct {
 	versionOnce   sync.Once
 	ForceDeploy   bool
 	previousApply ManifestList
+
+	// StateFile is where the applied manifests are recorded, so that a later
+	// skaffold session can delete them without reading the manifests.
+	StateFile   string
+	PruneDryRun bool
+
+	// applied lists the objects applied by this session. Objects removed
+	// from the manifests are pruned from this list.
+	applied         ManifestList
+	appliedRecorded bool
+
+	// Native if `true`, manifests are applied, deleted and read
+	// through the Kubernetes API instead of the kubectl binary.
+	Native     bool
+	native     *native
+	nativeErr  error
+	nativeOnce sync.Once
 }
 
 // Delete runs `kubectl delete` on a list of manifests.
 func (c *CLI) Delete(ctx context.Context, out io.Writer, manifests ManifestList) error {
+	if c.Native {
+		client, err := c.nativeClient()
+		if err != nil {
+			return err
+		}
+		return client.delete(out, manifests)
+	}
+
 	if err := c.Run(ctx, manifests.Reader(), out, "delete", c.Flags.Delete, "--ignore-not-found=true", "-f", "-"); err != nil {
 		return errors.Wrap(err, "kubectl delete")
 	}
@@ -49,15 +74,35 @@ func (c *CLI) Delete(ctx context.Context, out io.Writer, manifests ManifestList)
 	return nil
 }
 
-// Apply runs `kubectl apply` on a list of manifests.
+// Apply runs `kubectl apply` on a list of manifests, then prunes the objects
+// that were applied previously but are not part of the manifests anymore.
 func (c *CLI) Apply(ctx context.Context, out io.Writer, manifests ManifestList) error {
 	// Only redeploy modified or new manifests
-	// TODO(dgageot): should we delete a manifest that was deployed and is not anymore?
 	updated := c.previousApply.Diff(manifests)
 	logrus.Debugln(len(manifests), "manifests to deploy.", len(updated), "are updated or new")
 	c.previousApply = manifests
-	if len(updated) == 0 {
-		return nil
+
+	if len(updated) > 0 {
+		if err := c.apply(ctx, out, updated); err != nil {
+			return err
+		}
+	}
+
+	notPruned, err := c.prune(ctx, out, manifests)
+	if err != nil {
+		return err
+	}
+
+	return c.recordApplied(append(append(ManifestList{}, manifests...), notPruned...))
+}
+
+func (c *CLI) apply(ctx context.Context, out io.Writer, manifests ManifestList) error {
+	if c.Native {
+		client, err := c.nativeClient()
+		if err != nil {
+			return err
+		}
+		return client.apply(out, manifests)
 	}
 
 	args := []string{"-f", "-"}
@@ -65,7 +110,7 @@ func (c *CLI) Apply(ctx context.Context, out io.Writer, manifests ManifestList)
 		args = append(args, "--force")
 	}
 
-	if err := c.Run(ctx, updated.Reader(), out, "apply", c.Flags.Apply, args...); err != nil {
+	if err := c.Run(ctx, manifests.Reader(), out, "apply", c.Flags.Apply, args...); err != nil {
 		return errors.Wrap(err, "kubectl apply")
 	}
 
@@ -74,6 +119,13 @@ func (c *CLI) Apply(ctx context.Context, out io.Writer, manifests ManifestList)
 
 // ReadManifests reads a list of manifests in yaml format.
 func (c *CLI) ReadManifests(ctx context.Context, manifests []string) (ManifestList, error) {
+	if c.Native {
+		if err := c.checkNativeFlags(); err != nil {
+			return nil, err
+		}
+		return readManifestFiles(manifests)
+	}
+
 	var list []string
 	for _, manifest := range manifests {
 		list = append(list, "-f", manifest)
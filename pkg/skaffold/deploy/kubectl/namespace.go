@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// clusterScopedKinds lists the well known kinds of resources that don't live in a namespace.
+var clusterScopedKinds = map[string]bool{
+	"APIService":                     true,
+	"CertificateSigningRequest":      true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CSIDriver":                      true,
+	"CSINode":                        true,
+	"CustomResourceDefinition":       true,
+	"MutatingWebhookConfiguration":   true,
+	"Namespace":                      true,
+	"Node":                           true,
+	"PersistentVolume":               true,
+	"PodSecurityPolicy":              true,
+	"PriorityClass":                  true,
+	"RuntimeClass":                   true,
+	"StorageClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+	"VolumeAttachment":               true,
+}
+
+// SetNamespace moves the resources that explicitly set their namespace to
+// the given namespace. Resources that don't set a namespace are left untouched
+// since they are deployed to the default namespace of kubectl. So are cluster
+// scoped resources.
+func (l *ManifestList) SetNamespace(namespace string) (ManifestList, error) {
+	var updated ManifestList
+
+	for _, manifest := range *l {
+		m := make(map[interface{}]interface{})
+		if err := yaml.Unmarshal(manifest, &m); err != nil {
+			return nil, errors.Wrap(err, "reading kubernetes YAML")
+		}
+
+		if len(m) == 0 {
+			continue
+		}
+
+		if !moveToNamespace(m, namespace) {
+			updated = append(updated, manifest)
+			continue
+		}
+
+		updatedManifest, err := yaml.Marshal(m)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshalling yaml")
+		}
+
+		updated = append(updated, updatedManifest)
+	}
+
+	logrus.Debugln("manifests with namespace", updated.String())
+
+	return updated, nil
+}
+
+func moveToNamespace(m map[interface{}]interface{}, namespace string) bool {
+	if kind, ok := m["kind"].(string); !ok || clusterScopedKinds[kind] {
+		return false
+	}
+
+	metadata, ok := m["metadata"].(map[interface{}]interface{})
+	if !ok {
+		return false
+	}
+
+	current, present := metadata["namespace"]
+	if !present || current == namespace {
+		return false
+	}
+
+	metadata["namespace"] = namespace
+	return true
+}
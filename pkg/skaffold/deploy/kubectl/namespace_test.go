@@ -0,0 +1,56 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestSetNamespace(t *testing.T) {
+	manifests := ManifestList{[]byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: team
+`), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      namespace: team
+`), []byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+  namespace: team
+`)}
+
+	expected := ManifestList{[]byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: skaffold-alice-1a2b3c4d
+`), manifests[1], manifests[2]}
+
+	resultManifest, err := manifests.SetNamespace("skaffold-alice-1a2b3c4d")
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, expected.String(), resultManifest.String())
+}
@@ -0,0 +1,485 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// lastAppliedAnnotation holds the configuration that was last applied,
+// like `kubectl apply` does, so that both can be used interchangeably.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// for testing
+var (
+	newNativeClients      = kubernetes.GetDynamicClientForContext
+	crdEstablishedTimeout = 30 * time.Second
+	crdPollInterval       = 500 * time.Millisecond
+)
+
+// native applies and deletes manifests through the Kubernetes API,
+// without the kubectl binary.
+type native struct {
+	client    dynamic.Interface
+	discovery discovery.DiscoveryInterface
+	namespace string
+	force     bool
+
+	resourcesLock sync.Mutex
+	resources     map[schema.GroupVersionKind]apiResource
+}
+
+// apiResource is how a kind is served by the API server.
+type apiResource struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+func (c *CLI) nativeClient() (*native, error) {
+	c.nativeOnce.Do(func() {
+		if err := c.checkNativeFlags(); err != nil {
+			c.nativeErr = err
+			return
+		}
+
+		client, disco, err := newNativeClients(c.KubeContext)
+		if err != nil {
+			c.nativeErr = err
+			return
+		}
+
+		c.native = &native{
+			client:    client,
+			discovery: disco,
+			namespace: c.defaultNamespace(),
+			force:     c.ForceDeploy,
+			resources: map[schema.GroupVersionKind]apiResource{},
+		}
+	})
+
+	return c.native, c.nativeErr
+}
+
+// checkNativeFlags rejects the kubectl flags, since there's no kubectl
+// binary to pass them to. Silently ignoring them would deploy differently.
+func (c *CLI) checkNativeFlags() error {
+	var flags []string
+	flags = append(flags, c.Flags.Global...)
+	flags = append(flags, c.Flags.Apply...)
+	flags = append(flags, c.Flags.Delete...)
+	if len(flags) > 0 {
+		return fmt.Errorf("kubectl flags %v are not supported with native apply", flags)
+	}
+
+	return nil
+}
+
+// defaultNamespace is the namespace of resources that don't specify one.
+func (c *CLI) defaultNamespace() string {
+	if c.Namespace != "" {
+		return c.Namespace
+	}
+
+	if cfg, err := kubectx.CurrentConfig(); err == nil {
+		if current, present := cfg.Contexts[c.KubeContext]; present && current.Namespace != "" {
+			return current.Namespace
+		}
+	}
+
+	return "default"
+}
+
+// readManifestFiles reads manifests from files, without validating them.
+func readManifestFiles(files []string) (ManifestList, error) {
+	var manifests ManifestList
+
+	for _, file := range files {
+		buf, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", file)
+		}
+		manifests.Append(buf)
+	}
+
+	return manifests, nil
+}
+
+// apply creates or patches resources. Namespaces and custom resource
+// definitions are applied first, and custom resource definitions are
+// waited on until they are established.
+func (n *native) apply(out io.Writer, manifests ManifestList) error {
+	objs, err := parseManifests(manifests)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(objs, func(i, j int) bool {
+		return applyRank(objs[i]) < applyRank(objs[j])
+	})
+
+	for _, obj := range objs {
+		result, err := n.applyObject(obj)
+		if err != nil {
+			return errors.Wrapf(err, "applying %s", describe(obj))
+		}
+		fmt.Fprintf(out, "%s %s\n", describe(obj), result)
+
+		if isCRD(obj) {
+			if err := n.waitForEstablished(obj); err != nil {
+				return errors.Wrapf(err, "waiting for %s to be established", describe(obj))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (n *native) applyObject(obj *unstructured.Unstructured) (string, error) {
+	client, err := n.resourceClient(obj)
+	if err != nil {
+		return "", err
+	}
+
+	modified, err := setLastApplied(obj)
+	if err != nil {
+		return "", err
+	}
+
+	current, err := client.Get(obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := client.Create(obj); err != nil {
+			return "", err
+		}
+		return "created", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	currentJSON, err := current.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	original := []byte(current.GetAnnotations()[lastAppliedAnnotation])
+
+	patchType, patch, err := threeWayPatch(obj.GroupVersionKind(), original, modified, currentJSON)
+	if err != nil {
+		return "", errors.Wrap(err, "computing patch")
+	}
+	if string(patch) == "{}" {
+		return "unchanged", nil
+	}
+
+	if _, err := client.Patch(obj.GetName(), patchType, patch); err != nil {
+		if !n.force {
+			return "", err
+		}
+
+		// Like `kubectl apply --force`, replace resources that can't be patched.
+		logrus.Debugf("Replacing %s: %v", describe(obj), err)
+		if err := client.Delete(obj.GetName(), deleteOptions()); err != nil && !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		if _, err := client.Create(obj); err != nil {
+			return "", err
+		}
+		return "replaced", nil
+	}
+
+	return "configured", nil
+}
+
+// delete deletes resources in the reverse order they are applied.
+// Resources that are already gone are ignored.
+func (n *native) delete(out io.Writer, manifests ManifestList) error {
+	objs, err := parseManifests(manifests)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(objs, func(i, j int) bool {
+		return applyRank(objs[i]) > applyRank(objs[j])
+	})
+
+	for _, obj := range objs {
+		client, err := n.resourceClient(obj)
+		if err != nil {
+			// The definition of a custom resource might be gone already.
+			logrus.Debugf("Skipping deletion of %s: %v", describe(obj), err)
+			continue
+		}
+
+		if err := client.Delete(obj.GetName(), deleteOptions()); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "deleting %s", describe(obj))
+		}
+		fmt.Fprintf(out, "%s deleted\n", describe(obj))
+	}
+
+	return nil
+}
+
+func (n *native) waitForEstablished(crd *unstructured.Unstructured) error {
+	client, err := n.resourceClient(crd)
+	if err != nil {
+		return err
+	}
+
+	return wait.PollImmediate(crdPollInterval, crdEstablishedTimeout, func() (bool, error) {
+		current, err := client.Get(crd.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		conditions, _, _ := unstructured.NestedSlice(current.Object, "status", "conditions")
+		for _, condition := range conditions {
+			if c, ok := condition.(map[string]interface{}); ok && c["type"] == "Established" && c["status"] == "True" {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// resourceClient returns the client for an object, in the right namespace.
+func (n *native) resourceClient(obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	resource, err := n.resourceFor(obj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+
+	if !resource.namespaced {
+		return n.client.Resource(resource.gvr), nil
+	}
+
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(n.namespace)
+	}
+	return n.client.Resource(resource.gvr).Namespace(obj.GetNamespace()), nil
+}
+
+// resourceFor finds how a kind is served, using discovery. Only successful
+// lookups are cached since custom resources can be defined along the way.
+func (n *native) resourceFor(gvk schema.GroupVersionKind) (apiResource, error) {
+	n.resourcesLock.Lock()
+	defer n.resourcesLock.Unlock()
+
+	if resource, found := n.resources[gvk]; found {
+		return resource, nil
+	}
+
+	resources, err := n.discovery.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+	if err != nil {
+		return apiResource{}, errors.Wrapf(err, "getting server resources for %s", gvk.GroupVersion())
+	}
+
+	for _, r := range resources.APIResources {
+		// Skip sub-resources such as `deployments/scale`.
+		if r.Kind == gvk.Kind && !strings.Contains(r.Name, "/") {
+			resource := apiResource{
+				gvr:        gvk.GroupVersion().WithResource(r.Name),
+				namespaced: r.Namespaced,
+			}
+			n.resources[gvk] = resource
+			return resource, nil
+		}
+	}
+
+	return apiResource{}, fmt.Errorf("could not find resource for %s", gvk)
+}
+
+func parseManifests(manifests ManifestList) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+
+	for _, manifest := range manifests {
+		if len(bytes.TrimSpace(manifest)) == 0 {
+			continue
+		}
+
+		buf, err := yaml.ToJSON(manifest)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing manifest")
+		}
+		if string(buf) == "null" {
+			// Only comments
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(buf); err != nil {
+			return nil, errors.Wrap(err, "parsing manifest")
+		}
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+// applyRank ranks namespaces first, then custom resource definitions,
+// since other resources can depend on them.
+func applyRank(obj *unstructured.Unstructured) int {
+	switch {
+	case obj.GroupVersionKind().Group == "" && obj.GetKind() == "Namespace":
+		return 0
+	case isCRD(obj):
+		return 1
+	default:
+		return 2
+	}
+}
+
+func isCRD(obj *unstructured.Unstructured) bool {
+	return obj.GroupVersionKind().Group == "apiextensions.k8s.io" && obj.GetKind() == "CustomResourceDefinition"
+}
+
+// describe formats a resource like kubectl does. For example: `deployment.apps/web`.
+func describe(obj *unstructured.Unstructured) string {
+	kind := strings.ToLower(obj.GetKind())
+	if group := obj.GroupVersionKind().Group; group != "" {
+		kind += "." + group
+	}
+	return kind + "/" + obj.GetName()
+}
+
+func deleteOptions() *metav1.DeleteOptions {
+	propagation := metav1.DeletePropagationBackground
+	return &metav1.DeleteOptions{PropagationPolicy: &propagation}
+}
+
+// setLastApplied records the configuration being applied on the object
+// and returns the object as json.
+func setLastApplied(obj *unstructured.Unstructured) ([]byte, error) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	delete(annotations, lastAppliedAnnotation)
+	obj.SetAnnotations(annotations)
+	if len(annotations) == 0 {
+		unstructured.RemoveNestedField(obj.Object, "metadata", "annotations")
+	}
+
+	lastApplied, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	annotations[lastAppliedAnnotation] = string(lastApplied)
+	obj.SetAnnotations(annotations)
+
+	return obj.MarshalJSON()
+}
+
+// threeWayPatch computes the patch that turns the current state of a resource
+// into the modified configuration, removing the fields that were in the original
+// configuration and are not anymore. Built-in kinds get a strategic merge patch,
+// other kinds get a json merge patch.
+func threeWayPatch(gvk schema.GroupVersionKind, original, modified, current []byte) (types.PatchType, []byte, error) {
+	if typed, err := scheme.Scheme.New(gvk); err == nil {
+		lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(typed)
+		if err != nil {
+			return "", nil, err
+		}
+
+		patch, err := strategicpatch.CreateThreeWayMergePatch(original, modified, current, lookupPatchMeta, true)
+		return types.StrategicMergePatchType, patch, err
+	}
+
+	patch, err := threeWayJSONMergePatch(original, modified, current)
+	return types.MergePatchType, patch, err
+}
+
+func threeWayJSONMergePatch(original, modified, current []byte) ([]byte, error) {
+	var originalMap, modifiedMap, currentMap map[string]interface{}
+
+	if len(original) > 0 {
+		if err := json.Unmarshal(original, &originalMap); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(modified, &modifiedMap); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(current, &currentMap); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergePatch(originalMap, modifiedMap, currentMap))
+}
+
+// mergePatch computes a json merge patch. Maps are merged recursively
+// while lists and values are replaced.
+func mergePatch(original, modified, current map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+
+	for key, value := range modified {
+		currentValue, found := current[key]
+
+		modifiedChild, isMap := value.(map[string]interface{})
+		currentChild, currentIsMap := currentValue.(map[string]interface{})
+		if isMap && currentIsMap {
+			originalChild, _ := original[key].(map[string]interface{})
+			if childPatch := mergePatch(originalChild, modifiedChild, currentChild); len(childPatch) > 0 {
+				patch[key] = childPatch
+			}
+			continue
+		}
+
+		if !found || !reflect.DeepEqual(value, currentValue) {
+			patch[key] = value
+		}
+	}
+
+	// Remove what was configured before and is not anymore.
+	for key := range original {
+		if _, found := modified[key]; found {
+			continue
+		}
+		if _, found := current[key]; found {
+			patch[key] = nil
+		}
+	}
+
+	return patch
+}
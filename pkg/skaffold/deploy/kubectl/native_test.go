@@ -0,0 +1,384 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+const nativeDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+    tier: frontend
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: gcr.io/k8s-skaffold/web:v1`
+
+const nativeCronTab = `apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: cron
+spec:
+  cronSpec: "* * * * */5"
+  image: cron:v1`
+
+const nativeCRD = `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  version: v1
+  scope: Namespaced
+  names:
+    plural: crontabs
+    kind: CronTab`
+
+const nativeNamespace = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: test`
+
+func TestNativeApplyAndDelete(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		client := &fakeDynamic{objects: map[string]*unstructured.Unstructured{}}
+		disco := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+			{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "namespaces", Kind: "Namespace"}}},
+			{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true},
+				{Name: "deployments/scale", Kind: "Scale", Namespaced: true},
+			}},
+			{GroupVersion: "apiextensions.k8s.io/v1beta1", APIResources: []metav1.APIResource{{Name: "customresourcedefinitions", Kind: "CustomResourceDefinition"}}},
+			{GroupVersion: "stable.example.com/v1", APIResources: []metav1.APIResource{{Name: "crontabs", Kind: "CronTab", Namespaced: true}}},
+		}}}
+		t.Override(&newNativeClients, func(string) (dynamic.Interface, discovery.DiscoveryInterface, error) {
+			return client, disco, nil
+		})
+		t.Override(&crdPollInterval, time.Millisecond)
+
+		apply := func(manifests ...string) string {
+			cli := &CLI{Namespace: "testNamespace", Native: true}
+			var out bytes.Buffer
+			err := cli.Apply(context.Background(), &out, manifestList(manifests...))
+			t.CheckError(false, err)
+			return out.String()
+		}
+
+		// Namespaces and CRDs go first
+		out := apply(nativeDeployment, nativeCronTab, nativeCRD, nativeNamespace)
+		t.CheckDeepEqual(`namespace/test created
+customresourcedefinition.apiextensions.k8s.io/crontabs.stable.example.com created
+deployment.apps/web created
+crontab.stable.example.com/cron created
+`, out)
+
+		out = apply(nativeDeployment, nativeCronTab, nativeCRD, nativeNamespace)
+		t.CheckDeepEqual(`namespace/test unchanged
+customresourcedefinition.apiextensions.k8s.io/crontabs.stable.example.com unchanged
+deployment.apps/web unchanged
+crontab.stable.example.com/cron unchanged
+`, out)
+
+		// Fields removed from the manifests are removed from the resources
+		out = apply(
+			strings.NewReplacer("    tier: frontend\n", "", "replicas: 1", "replicas: 2").Replace(nativeDeployment),
+			strings.Replace(nativeCronTab, "  image: cron:v1", "", 1),
+		)
+		t.CheckDeepEqual("deployment.apps/web configured\ncrontab.stable.example.com/cron configured\n", out)
+
+		deployment := client.objects["deployments/testNamespace/web"]
+		labels := deployment.GetLabels()
+		replicas, _, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+		t.CheckDeepEqual(map[string]string{"app": "web"}, labels)
+		t.CheckDeepEqual(int64(2), replicas)
+
+		cronTab := client.objects["crontabs/testNamespace/cron"]
+		_, found, _ := unstructured.NestedString(cronTab.Object, "spec", "image")
+		t.CheckDeepEqual(false, found)
+
+		// Deletion goes in reverse order and ignores missing resources
+		cli := &CLI{Namespace: "testNamespace", Native: true}
+		var deleteOut bytes.Buffer
+		err := cli.Delete(context.Background(), &deleteOut, manifestList(nativeNamespace, nativeCRD, nativeDeployment, nativeCronTab))
+		t.CheckError(false, err)
+		t.CheckDeepEqual(`deployment.apps/web deleted
+crontab.stable.example.com/cron deleted
+customresourcedefinition.apiextensions.k8s.io/crontabs.stable.example.com deleted
+namespace/test deleted
+`, deleteOut.String())
+
+		deleteOut.Reset()
+		err = cli.Delete(context.Background(), &deleteOut, manifestList(nativeDeployment))
+		t.CheckError(false, err)
+		t.CheckDeepEqual("", deleteOut.String())
+	})
+}
+
+func TestNativeReadManifests(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("deployment.yaml", nativeDeployment).
+			Write("all.yaml", nativeNamespace+"\n---\n"+nativeCRD)
+
+		cli := &CLI{Native: true}
+		manifests, err := cli.ReadManifests(context.Background(), []string{tmpDir.Path("deployment.yaml"), tmpDir.Path("all.yaml")})
+
+		expected := manifestList(nativeDeployment, nativeNamespace, nativeCRD)
+		t.CheckErrorAndDeepEqual(false, err, expected.String(), manifests.String())
+	})
+}
+
+func TestNativeRejectsKubectlFlags(t *testing.T) {
+	tests := []struct {
+		description string
+		flags       latest.KubectlFlags
+		run         func(*CLI) error
+	}{
+		{
+			description: "apply flags",
+			flags:       latest.KubectlFlags{Apply: []string{"--server-side"}},
+			run: func(cli *CLI) error {
+				return cli.Apply(context.Background(), ioutil.Discard, manifestList(nativeDeployment))
+			},
+		},
+		{
+			description: "delete flags",
+			flags:       latest.KubectlFlags{Delete: []string{"--grace-period=0"}},
+			run: func(cli *CLI) error {
+				return cli.Delete(context.Background(), ioutil.Discard, manifestList(nativeDeployment))
+			},
+		},
+		{
+			description: "global flags",
+			flags:       latest.KubectlFlags{Global: []string{"--request-timeout=5s"}},
+			run: func(cli *CLI) error {
+				_, err := cli.ReadManifests(context.Background(), nil)
+				return err
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&newNativeClients, func(string) (dynamic.Interface, discovery.DiscoveryInterface, error) {
+				return nil, nil, fmt.Errorf("unexpected call")
+			})
+
+			err := test.run(&CLI{Native: true, Flags: test.flags})
+
+			t.CheckErrorContains("not supported with native apply", err)
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	original := `{"spec":{"image":"v1","schedule":"daily","args":["a"]},"metadata":{"name":"cron"}}`
+	modified := `{"spec":{"image":"v2","args":["a"]},"metadata":{"name":"cron"}}`
+	current := `{"spec":{"image":"v1","schedule":"daily","args":["a"],"suspend":false},"metadata":{"name":"cron","uid":"123"}}`
+
+	patch, err := threeWayJSONMergePatch([]byte(original), []byte(modified), []byte(current))
+
+	testutil.CheckErrorAndDeepEqual(t, false, err, `{"spec":{"image":"v2","schedule":null}}`, string(patch))
+}
+
+func manifestList(manifests ...string) ManifestList {
+	var list ManifestList
+	for _, manifest := range manifests {
+		list = append(list, []byte(manifest))
+	}
+	return list
+}
+
+// fakeDynamic is an in-memory dynamic client that behaves like the API server
+// for the calls made by the native applier.
+type fakeDynamic struct {
+	objects map[string]*unstructured.Unstructured
+}
+
+func (f *fakeDynamic) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &fakeResource{fakeDynamic: f, gvr: gvr}
+}
+
+type fakeResource struct {
+	*fakeDynamic
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+func (r *fakeResource) Namespace(ns string) dynamic.ResourceInterface {
+	return &fakeResource{fakeDynamic: r.fakeDynamic, gvr: r.gvr, namespace: ns}
+}
+
+func (r *fakeResource) key(name string) string {
+	if r.namespace == "" {
+		return r.gvr.Resource + "/" + name
+	}
+	return r.gvr.Resource + "/" + r.namespace + "/" + name
+}
+
+func (r *fakeResource) Create(obj *unstructured.Unstructured, _ ...string) (*unstructured.Unstructured, error) {
+	key := r.key(obj.GetName())
+	if _, found := r.objects[key]; found {
+		return nil, apierrors.NewAlreadyExists(r.gvr.GroupResource(), obj.GetName())
+	}
+
+	stored := obj.DeepCopy()
+	if r.gvr.Resource == "customresourcedefinitions" {
+		unstructured.SetNestedSlice(stored.Object, []interface{}{
+			map[string]interface{}{"type": "Established", "status": "True"},
+		}, "status", "conditions")
+	}
+	r.objects[key] = stored
+
+	return stored, nil
+}
+
+func (r *fakeResource) Get(name string, _ metav1.GetOptions, _ ...string) (*unstructured.Unstructured, error) {
+	obj, found := r.objects[r.key(name)]
+	if !found {
+		return nil, apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (r *fakeResource) Delete(name string, _ *metav1.DeleteOptions, _ ...string) error {
+	key := r.key(name)
+	if _, found := r.objects[key]; !found {
+		return apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+	delete(r.objects, key)
+	return nil
+}
+
+func (r *fakeResource) Patch(name string, pt types.PatchType, data []byte, _ ...string) (*unstructured.Unstructured, error) {
+	current, found := r.objects[r.key(name)]
+	if !found {
+		return nil, apierrors.NewNotFound(r.gvr.GroupResource(), name)
+	}
+
+	currentJSON, err := current.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch pt {
+	case types.StrategicMergePatchType:
+		typed, err := scheme.Scheme.New(current.GroupVersionKind())
+		if err != nil {
+			return nil, err
+		}
+		patched, err = strategicpatch.StrategicMergePatch(currentJSON, data, typed)
+		if err != nil {
+			return nil, err
+		}
+	case types.MergePatchType:
+		var currentMap, patch map[string]interface{}
+		if err := json.Unmarshal(currentJSON, &currentMap); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &patch); err != nil {
+			return nil, err
+		}
+		patched, err = json.Marshal(applyMergePatch(currentMap, patch))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported patch type %s", pt)
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(patched); err != nil {
+		return nil, err
+	}
+	r.objects[r.key(name)] = obj
+
+	return obj, nil
+}
+
+func applyMergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+
+		patchChild, isMap := value.(map[string]interface{})
+		targetChild, targetIsMap := target[key].(map[string]interface{})
+		if isMap && targetIsMap {
+			target[key] = applyMergePatch(targetChild, patchChild)
+		} else {
+			target[key] = value
+		}
+	}
+	return target
+}
+
+func (r *fakeResource) Update(*unstructured.Unstructured, ...string) (*unstructured.Unstructured, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *fakeResource) UpdateStatus(*unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *fakeResource) DeleteCollection(*metav1.DeleteOptions, metav1.ListOptions) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (r *fakeResource) List(metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *fakeResource) Watch(metav1.ListOptions) (watch.Interface, error) {
+	return nil, fmt.Errorf("not implemented")
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Removed lists the manifests of objects that are not part of the latest manifests.
+// Objects are identified by their group, kind, namespace and name, so that
+// changing the content of a manifest doesn't make it removed.
+func (l *ManifestList) Removed(latest ManifestList) ManifestList {
+	keys := map[string]bool{}
+	for _, manifest := range latest {
+		if key, _, ok := objectKey(manifest); ok {
+			keys[key] = true
+		}
+	}
+
+	var removed ManifestList
+	for _, manifest := range *l {
+		if key, _, ok := objectKey(manifest); ok && !keys[key] {
+			removed = append(removed, manifest)
+		}
+	}
+
+	return removed
+}
+
+// objectKey identifies the object described by a manifest.
+// It also returns a description of the object, like `deployment.apps/web`.
+func objectKey(manifest []byte) (string, string, bool) {
+	objs, err := parseManifests(ManifestList{manifest})
+	if err != nil || len(objs) != 1 || objs[0].GetKind() == "" || objs[0].GetName() == "" {
+		return "", "", false
+	}
+
+	obj := objs[0]
+	key := fmt.Sprintf("%s/%s/%s/%s", obj.GroupVersionKind().Group, obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	return key, describe(obj), true
+}
+
+// prune deletes the objects that were applied by a previous iteration of the
+// session but are not part of the given manifests anymore. Objects applied by
+// other sessions are never pruned, since they might belong to another config
+// or profile. It returns the objects that are still deployed.
+func (c *CLI) prune(ctx context.Context, out io.Writer, manifests ManifestList) (ManifestList, error) {
+	removed := c.applied.Removed(manifests)
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	for _, manifest := range removed {
+		_, description, _ := objectKey(manifest)
+		if c.PruneDryRun {
+			color.Default.Fprintf(out, "%s would be pruned (dry run)\n", description)
+		} else {
+			color.Default.Fprintf(out, "Pruning %s\n", description)
+		}
+	}
+
+	if c.PruneDryRun {
+		return removed, nil
+	}
+
+	if err := c.Delete(ctx, out, removed); err != nil {
+		return nil, errors.Wrap(err, "pruning")
+	}
+
+	return nil, nil
+}
+
+// Applied returns the manifests recorded by the last apply, if any.
+// When nothing was applied by this session, it reads what a previous
+// session recorded.
+func (c *CLI) Applied() (ManifestList, error) {
+	if c.appliedRecorded || c.StateFile == "" {
+		return c.applied, nil
+	}
+
+	buf, err := ioutil.ReadFile(c.StateFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "reading applied manifests")
+	}
+
+	var applied ManifestList
+	if len(buf) > 0 {
+		applied.Append(buf)
+	}
+
+	return applied, nil
+}
+
+// ForgetApplied forgets about the manifests recorded by the last apply.
+func (c *CLI) ForgetApplied() error {
+	c.applied = nil
+	c.appliedRecorded = true
+
+	if c.StateFile == "" {
+		return nil
+	}
+
+	if err := os.Remove(c.StateFile); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "removing applied manifests")
+	}
+	return nil
+}
+
+// recordApplied records the manifests that were applied.
+func (c *CLI) recordApplied(manifests ManifestList) error {
+	c.applied = manifests
+	c.appliedRecorded = true
+
+	if c.StateFile == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.StateFile), 0755); err != nil {
+		return errors.Wrap(err, "recording applied manifests")
+	}
+
+	logrus.Debugln("Recording applied manifests in", c.StateFile)
+	if err := ioutil.WriteFile(c.StateFile, []byte(manifests.String()), 0644); err != nil {
+		return errors.Wrap(err, "recording applied manifests")
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectl
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+const (
+	webV1 = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1`
+	webV2 = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2`
+	webService = `apiVersion: v1
+kind: Service
+metadata:
+  name: web`
+	webServiceOtherNamespace = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: other`
+)
+
+func TestRemoved(t *testing.T) {
+	tests := []struct {
+		description string
+		previous    ManifestList
+		latest      ManifestList
+		expected    ManifestList
+	}{
+		{
+			description: "nothing applied",
+			latest:      ManifestList{[]byte(webV1)},
+		},
+		{
+			description: "changed manifest",
+			previous:    ManifestList{[]byte(webV1), []byte(webService)},
+			latest:      ManifestList{[]byte(webV2), []byte(webService)},
+		},
+		{
+			description: "removed service",
+			previous:    ManifestList{[]byte(webV1), []byte(webService)},
+			latest:      ManifestList{[]byte(webV2)},
+			expected:    ManifestList{[]byte(webService)},
+		},
+		{
+			description: "moved to another namespace",
+			previous:    ManifestList{[]byte(webService)},
+			latest:      ManifestList{[]byte(webServiceOtherNamespace)},
+			expected:    ManifestList{[]byte(webService)},
+		},
+		{
+			description: "ignore empty manifests",
+			previous:    ManifestList{[]byte(webService), []byte("# comment")},
+			latest:      ManifestList{[]byte(webService)},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			removed := test.previous.Removed(test.latest)
+
+			t.CheckDeepEqual(test.expected, removed)
+		})
+	}
+}
+
+func TestRecordApplied(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		stateFile := t.NewTempDir().Path("applied/kubectl.yaml")
+
+		err := (&CLI{StateFile: stateFile}).recordApplied(ManifestList{[]byte(webV1), []byte(webService)})
+		t.CheckError(false, err)
+
+		cli := &CLI{StateFile: stateFile}
+		applied, err := cli.Applied()
+		t.CheckErrorAndDeepEqual(false, err, ManifestList{[]byte(webV1), []byte(webService)}, applied)
+
+		err = cli.ForgetApplied()
+		t.CheckError(false, err)
+
+		applied, err = (&CLI{StateFile: stateFile}).Applied()
+		t.CheckErrorAndDeepEqual(false, err, ManifestList(nil), applied)
+	})
+}
+
+func TestPruneIgnoresOtherSessions(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		stateFile := t.NewTempDir().Path("applied/kubectl.yaml")
+
+		err := (&CLI{StateFile: stateFile}).recordApplied(ManifestList{[]byte(webV1), []byte(webService)})
+		t.CheckError(false, err)
+
+		notPruned, err := (&CLI{StateFile: stateFile}).prune(context.Background(), ioutil.Discard, ManifestList{[]byte(webV1)})
+		t.CheckErrorAndDeepEqual(false, err, ManifestList(nil), notPruned)
+	})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package deploy
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
@@ -336,3 +337,102 @@ spec:
 	}, labellers)
 	testutil.CheckError(t, false, err)
 }
+
+func TestKubectlPrune(t *testing.T) {
+	const labelledApp = `apiVersion: v1
+kind: Pod
+metadata:
+  labels:
+    skaffold.dev/deployer: kubectl
+  name: leeroy-app
+spec:
+  containers:
+  - image: leeroy-app:v1
+    name: leeroy-app`
+	const labelledWeb = `apiVersion: v1
+kind: Pod
+metadata:
+  labels:
+    skaffold.dev/deployer: kubectl
+  name: leeroy-web
+spec:
+  containers:
+  - image: leeroy-web:v1
+    name: leeroy-web`
+
+	tests := []struct {
+		description string
+		dryRun      bool
+		pruneCmd    func(*testutil.FakeCmd) *testutil.FakeCmd
+		deleted     string
+		expected    string
+	}{
+		{
+			description: "prune removed objects",
+			pruneCmd: func(cmd *testutil.FakeCmd) *testutil.FakeCmd {
+				return cmd.WithRunInput("kubectl --context kubecontext --namespace testNamespace delete --ignore-not-found=true -f -", labelledApp)
+			},
+			deleted:  labelledWeb,
+			expected: "Pruning pod/leeroy-app",
+		},
+		{
+			description: "dry run",
+			dryRun:      true,
+			pruneCmd:    func(cmd *testutil.FakeCmd) *testutil.FakeCmd { return cmd },
+			deleted:     labelledWeb + "\n---\n" + labelledApp,
+			expected:    "pod/leeroy-app would be pruned (dry run)",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir().
+				Write("deployment-web.yaml", deploymentWebYAML).
+				Write("deployment-app.yaml", deploymentAppYAML)
+			stateDir := t.NewTempDir()
+			t.Override(&appliedRootDir, func() (string, error) { return stateDir.Root(), nil })
+			t.Override(&util.DefaultExecCommand, test.pruneCmd(testutil.NewFakeCmd(t.T).
+				WithRunOut("kubectl version --client -ojson", kubectlVersion).
+				WithRunOut("kubectl --context kubecontext --namespace testNamespace create --dry-run -oyaml -f "+tmpDir.Path("deployment-app.yaml")+" -f "+tmpDir.Path("deployment-web.yaml"), deploymentAppYAML+"\n"+deploymentWebYAML).
+				WithRunInput("kubectl --context kubecontext --namespace testNamespace apply -f -", labelledApp+"\n---\n"+labelledWeb).
+				WithRunOut("kubectl --context kubecontext --namespace testNamespace create --dry-run -oyaml -f "+tmpDir.Path("deployment-web.yaml"), deploymentWebYAML)).
+				WithRunInput("kubectl --context kubecontext --namespace testNamespace delete --ignore-not-found=true -f -", test.deleted))
+
+			cfg := &latest.KubectlDeploy{
+				Manifests: []string{"deployment-app.yaml", "deployment-web.yaml"},
+			}
+			runCtx := &runcontext.RunContext{
+				WorkingDir: tmpDir.Root(),
+				Cfg: &latest.Pipeline{
+					Deploy: latest.DeployConfig{
+						DeployType: latest.DeployType{
+							KubectlDeploy: cfg,
+						},
+					},
+				},
+				KubeContext: testKubeContext,
+				Opts: &config.SkaffoldOptions{
+					Namespace:   testNamespace,
+					PruneDryRun: test.dryRun,
+				},
+			}
+			builds := []build.Artifact{
+				{ImageName: "leeroy-web", Tag: "leeroy-web:v1"},
+				{ImageName: "leeroy-app", Tag: "leeroy-app:v1"},
+			}
+			deployer := NewKubectlDeployer(runCtx)
+
+			err := deployer.Deploy(context.Background(), ioutil.Discard, builds, []Labeller{deployer})
+			t.CheckError(false, err)
+
+			cfg.Manifests = []string{"deployment-web.yaml"}
+			var out bytes.Buffer
+			err = deployer.Deploy(context.Background(), &out, builds, []Labeller{deployer})
+			t.CheckError(false, err)
+			t.CheckContains(test.expected, out.String())
+
+			// A later session deletes what was recorded, without reading the manifests
+			err = NewKubectlDeployer(runCtx).Cleanup(context.Background(), ioutil.Discard)
+			t.CheckError(false, err)
+		})
+	}
+}
@@ -18,10 +18,13 @@ package deploy
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	yaml "gopkg.in/yaml.v2"
 
@@ -29,34 +32,63 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
-	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
+// kustomizationFileNames are the names kustomize looks for, in order.
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
 // kustomization is the content of a kustomization.yaml file.
 type kustomization struct {
-	Bases              []string             `yaml:"bases"`
-	Resources          []string             `yaml:"resources"`
-	Patches            []string             `yaml:"patches"`
-	CRDs               []string             `yaml:"crds"`
-	PatchesJSON6902    []patchJSON6902      `yaml:"patchesJson6902"`
-	ConfigMapGenerator []configMapGenerator `yaml:"configMapGenerator"`
-	SecretGenerator    []secretGenerator    `yaml:"secretGenerator"`
+	Bases                 []string             `yaml:"bases"`
+	Resources             []string             `yaml:"resources"`
+	Components            []string             `yaml:"components"`
+	Patches               []patchPath          `yaml:"patches"`
+	PatchesStrategicMerge []string             `yaml:"patchesStrategicMerge"`
+	PatchesJSON6902       []patchPath          `yaml:"patchesJson6902"`
+	CRDs                  []string             `yaml:"crds"`
+	Configurations        []string             `yaml:"configurations"`
+	Generators            []string             `yaml:"generators"`
+	Transformers          []string             `yaml:"transformers"`
+	Validators            []string             `yaml:"validators"`
+	ConfigMapGenerator    []configMapGenerator `yaml:"configMapGenerator"`
+	SecretGenerator       []secretGenerator    `yaml:"secretGenerator"`
+	OpenAPI               openAPI              `yaml:"openapi"`
 }
 
-type patchJSON6902 struct {
+// patchPath is a patch, either given as a path or as an object with a `path` field.
+// Inline patches have no path.
+type patchPath struct {
 	Path string `yaml:"path"`
 }
 
+func (p *patchPath) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&p.Path); err == nil {
+		return nil
+	}
+
+	type plain patchPath
+	return unmarshal((*plain)(p))
+}
+
 type configMapGenerator struct {
 	Files []string `yaml:"files"`
+	Env   string   `yaml:"env"`
+	Envs  []string `yaml:"envs"`
 }
 
 type secretGenerator struct {
 	Files []string `yaml:"files"`
+	Env   string   `yaml:"env"`
+	Envs  []string `yaml:"envs"`
+}
+
+type openAPI struct {
+	Path string `yaml:"path"`
 }
 
 // KustomizeDeployer deploys workflows using kustomize CLI.
@@ -76,12 +108,25 @@ func NewKustomizeDeployer(runCtx *runcontext.RunContext) *KustomizeDeployer {
 			KubeContext: runCtx.KubeContext,
 			Flags:       runCtx.Cfg.Deploy.KustomizeDeploy.Flags,
 			ForceDeploy: runCtx.Opts.ForceDeploy(),
+			Native:      runCtx.Opts.NativeApply,
+			StateFile:   appliedFile(appliedDir(runCtx), "kustomize"),
+			PruneDryRun: runCtx.Opts.PruneDryRun,
 		},
 		defaultRepo:        runCtx.DefaultRepo,
 		insecureRegistries: runCtx.InsecureRegistries,
 	}
 }
 
+// Reconfigure applies a new configuration to the deployer. It still knows
+// what it applied before, so that unchanged manifests are not applied again
+// and removed ones are pruned.
+func (k *KustomizeDeployer) Reconfigure(runCtx *runcontext.RunContext) {
+	k.KustomizeDeploy = runCtx.Cfg.Deploy.KustomizeDeploy
+	k.kubectl.Flags = runCtx.Cfg.Deploy.KustomizeDeploy.Flags
+	k.defaultRepo = runCtx.DefaultRepo
+	k.insecureRegistries = runCtx.InsecureRegistries
+}
+
 // Labels returns the labels specific to kustomize.
 func (k *KustomizeDeployer) Labels() map[string]string {
 	return map[string]string{
@@ -91,14 +136,15 @@ func (k *KustomizeDeployer) Labels() map[string]string {
 
 // Deploy runs `kubectl apply` on the manifest generated by kustomize.
 func (k *KustomizeDeployer) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []Labeller) error {
-	color.Default.Fprintln(out, "kubectl client version:", k.kubectl.Version(ctx))
-	if err := k.kubectl.CheckVersion(ctx); err != nil {
-		color.Default.Fprintln(out, err)
+	if !k.kubectl.Native {
+		color.Default.Fprintln(out, "kubectl client version:", k.kubectl.Version(ctx))
+		if err := k.kubectl.CheckVersion(ctx); err != nil {
+			color.Default.Fprintln(out, err)
+		}
 	}
 
 	manifests, err := k.readManifests(ctx)
 	if err != nil {
-		event.DeployFailed(err)
 		return errors.Wrap(err, "reading manifests")
 	}
 
@@ -106,17 +152,13 @@ func (k *KustomizeDeployer) Deploy(ctx context.Context, out io.Writer, builds []
 		return nil
 	}
 
-	event.DeployInProgress()
-
 	manifests, err = manifests.ReplaceImages(builds, k.defaultRepo)
 	if err != nil {
-		event.DeployFailed(err)
 		return errors.Wrap(err, "replacing images in manifests")
 	}
 
 	manifests, err = manifests.SetLabels(merge(labellers...))
 	if err != nil {
-		event.DeployFailed(err)
 		return errors.Wrap(err, "setting labels in manifests")
 	}
 
@@ -129,32 +171,27 @@ func (k *KustomizeDeployer) Deploy(ctx context.Context, out io.Writer, builds []
 
 	err = k.kubectl.Apply(ctx, out, manifests)
 	if err != nil {
-		event.DeployFailed(err)
 		return errors.Wrap(err, "kubectl error")
 	}
 
-	event.DeployComplete()
 	return nil
 }
 
 // Cleanup deletes what was deployed by calling Deploy.
 func (k *KustomizeDeployer) Cleanup(ctx context.Context, out io.Writer) error {
-	manifests, err := k.readManifests(ctx)
-	if err != nil {
-		return errors.Wrap(err, "reading manifests")
-	}
-
-	if err := k.kubectl.Delete(ctx, out, manifests); err != nil {
-		return errors.Wrap(err, "delete")
-	}
-
-	return nil
+	return deleteApplied(ctx, out, &k.kubectl, func() (kubectl.ManifestList, error) {
+		return k.readManifests(ctx)
+	})
 }
 
 func dependenciesForKustomization(dir string) ([]string, error) {
 	var deps []string
 
-	path := filepath.Join(dir, "kustomization.yaml")
+	path, err := kustomizationFile(dir)
+	if err != nil {
+		return nil, err
+	}
+
 	buf, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -162,10 +199,15 @@ func dependenciesForKustomization(dir string) ([]string, error) {
 
 	content := kustomization{}
 	if err := yaml.Unmarshal(buf, &content); err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "parsing %s", path)
 	}
 
-	for _, base := range content.Bases {
+	for _, base := range append(content.Bases, content.Components...) {
+		if isRemoteKustomization(dir, base) {
+			logrus.Debugf("Skipping remote kustomization %s", base)
+			continue
+		}
+
 		baseDeps, err := dependenciesForKustomization(filepath.Join(dir, base))
 		if err != nil {
 			return nil, err
@@ -175,22 +217,117 @@ func dependenciesForKustomization(dir string) ([]string, error) {
 	}
 
 	deps = append(deps, path)
-	deps = append(deps, joinPaths(dir, content.Resources)...)
-	deps = append(deps, joinPaths(dir, content.Patches)...)
+
+	// Resources, generators and transformers can either be files or kustomizations.
+	for _, resource := range concat(content.Resources, content.Generators, content.Transformers, content.Validators) {
+		resourceDeps, err := dependenciesForResource(dir, resource)
+		if err != nil {
+			return nil, err
+		}
+
+		deps = append(deps, resourceDeps...)
+	}
+
+	for _, patch := range content.Patches {
+		if patch.Path != "" {
+			deps = append(deps, filepath.Join(dir, patch.Path))
+		}
+	}
+	for _, patch := range content.PatchesStrategicMerge {
+		// Strategic merge patches can also be inlined.
+		if !strings.Contains(patch, "\n") {
+			deps = append(deps, filepath.Join(dir, patch))
+		}
+	}
 	deps = append(deps, joinPaths(dir, content.CRDs)...)
+	deps = append(deps, joinPaths(dir, content.Configurations)...)
 	for _, patch := range content.PatchesJSON6902 {
-		deps = append(deps, filepath.Join(dir, patch.Path))
+		if patch.Path != "" {
+			deps = append(deps, filepath.Join(dir, patch.Path))
+		}
 	}
 	for _, generator := range content.ConfigMapGenerator {
-		deps = append(deps, joinPaths(dir, generator.Files)...)
+		deps = append(deps, joinPaths(dir, generatorFiles(generator.Files, generator.Env, generator.Envs))...)
 	}
 	for _, generator := range content.SecretGenerator {
-		deps = append(deps, joinPaths(dir, generator.Files)...)
+		deps = append(deps, joinPaths(dir, generatorFiles(generator.Files, generator.Env, generator.Envs))...)
+	}
+	if content.OpenAPI.Path != "" {
+		deps = append(deps, filepath.Join(dir, content.OpenAPI.Path))
 	}
 
 	return deps, nil
 }
 
+// kustomizationFile finds the kustomization file in a directory.
+func kustomizationFile(dir string) (string, error) {
+	for _, name := range kustomizationFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no kustomization file found in %s", dir)
+}
+
+// dependenciesForResource lists the files a resource depends on. A resource
+// that is a directory is a kustomization. Remote resources are skipped.
+func dependenciesForResource(dir, resource string) ([]string, error) {
+	if isRemoteKustomization(dir, resource) {
+		logrus.Debugf("Skipping remote resource %s", resource)
+		return nil, nil
+	}
+
+	path := filepath.Join(dir, resource)
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return dependenciesForKustomization(path)
+	}
+
+	return []string{path}, nil
+}
+
+// isRemoteKustomization tells if a base or a resource is a url
+// or a git repository, such as `github.com/org/repo//path?ref=v1`.
+func isRemoteKustomization(dir, path string) bool {
+	if strings.Contains(path, "://") || strings.HasPrefix(path, "git@") || strings.HasPrefix(path, "git::") {
+		return true
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, path)); err == nil {
+		return false
+	}
+
+	host := strings.SplitN(path, "/", 2)[0]
+	return strings.Contains(host, ".") && strings.Contains(path, "/") && !strings.HasPrefix(host, ".")
+}
+
+// generatorFiles lists the files used by a generator. Files can be
+// given as `path` or as `key=path`.
+func generatorFiles(files []string, env string, envs []string) []string {
+	var paths []string
+
+	for _, file := range files {
+		if parts := strings.SplitN(file, "=", 2); len(parts) == 2 {
+			file = parts[1]
+		}
+		paths = append(paths, file)
+	}
+	if env != "" {
+		paths = append(paths, env)
+	}
+
+	return append(paths, envs...)
+}
+
+func concat(lists ...[]string) []string {
+	var all []string
+	for _, list := range lists {
+		all = append(all, list...)
+	}
+	return all
+}
+
 func joinPaths(root string, paths []string) []string {
 	var list []string
 
@@ -203,21 +340,44 @@ func joinPaths(root string, paths []string) []string {
 
 // Dependencies lists all the files that can change what needs to be deployed.
 func (k *KustomizeDeployer) Dependencies() ([]string, error) {
-	return dependenciesForKustomization(k.KustomizePath)
+	seen := map[string]bool{}
+	var deps []string
+
+	for _, kustomizePath := range k.KustomizePaths {
+		pathDeps, err := dependenciesForKustomization(kustomizePath)
+		if err != nil {
+			return nil, err
+		}
+
+		// Overlays often share the same bases.
+		for _, dep := range pathDeps {
+			if !seen[dep] {
+				seen[dep] = true
+				deps = append(deps, dep)
+			}
+		}
+	}
+
+	return deps, nil
 }
 
 func (k *KustomizeDeployer) readManifests(ctx context.Context) (kubectl.ManifestList, error) {
-	cmd := exec.CommandContext(ctx, "kustomize", "build", k.KustomizePath)
-	out, err := util.RunCmdOut(cmd)
-	if err != nil {
-		return nil, errors.Wrap(err, "kustomize build")
-	}
+	var manifests kubectl.ManifestList
 
-	if len(out) == 0 {
-		return nil, nil
+	for _, kustomizePath := range k.KustomizePaths {
+		args := append([]string{"build"}, k.BuildArgs...)
+		args = append(args, kustomizePath)
+
+		cmd := exec.CommandContext(ctx, "kustomize", args...)
+		out, err := util.RunCmdOut(cmd)
+		if err != nil {
+			return nil, errors.Wrapf(err, "kustomize build %s", kustomizePath)
+		}
+
+		if len(out) > 0 {
+			manifests.Append(out)
+		}
 	}
 
-	var manifests kubectl.ManifestList
-	manifests.Append(out)
 	return manifests, nil
 }
@@ -45,7 +45,7 @@ func TestKustomizeDeploy(t *testing.T) {
 		{
 			description: "no manifest",
 			cfg: &latest.KustomizeDeploy{
-				KustomizePath: tmpDir.Root(),
+				KustomizePaths: []string{tmpDir.Root()},
 			},
 			command: testutil.NewFakeCmd(t).
 				WithRunOut("kubectl version --client -ojson", kubectlVersion).
@@ -54,7 +54,7 @@ func TestKustomizeDeploy(t *testing.T) {
 		{
 			description: "deploy success",
 			cfg: &latest.KustomizeDeploy{
-				KustomizePath: tmpDir.Root(),
+				KustomizePaths: []string{tmpDir.Root()},
 			},
 			command: testutil.NewFakeCmd(t).
 				WithRunOut("kubectl version --client -ojson", kubectlVersion).
@@ -66,6 +66,22 @@ func TestKustomizeDeploy(t *testing.T) {
 			}},
 			forceDeploy: true,
 		},
+		{
+			description: "multiple paths with build args",
+			cfg: &latest.KustomizeDeploy{
+				KustomizePaths: []string{tmpDir.Path("a"), tmpDir.Path("b")},
+				BuildArgs:      []string{"--enable_alpha_plugins"},
+			},
+			command: testutil.NewFakeCmd(t).
+				WithRunOut("kubectl version --client -ojson", kubectlVersion).
+				WithRunOut("kustomize build --enable_alpha_plugins "+tmpDir.Path("a"), deploymentWebYAML).
+				WithRunOut("kustomize build --enable_alpha_plugins "+tmpDir.Path("b"), deploymentAppYAML).
+				WithRun("kubectl --context kubecontext --namespace testNamespace apply -f -"),
+			builds: []build.Artifact{{
+				ImageName: "leeroy-web",
+				Tag:       "leeroy-web:123",
+			}},
+		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
@@ -106,7 +122,7 @@ func TestKustomizeCleanup(t *testing.T) {
 		{
 			description: "cleanup success",
 			cfg: &latest.KustomizeDeploy{
-				KustomizePath: tmpDir.Root(),
+				KustomizePaths: []string{tmpDir.Root()},
 			},
 			command: testutil.NewFakeCmd(t).
 				WithRunOut("kustomize build "+tmpDir.Root(), deploymentWebYAML).
@@ -115,7 +131,7 @@ func TestKustomizeCleanup(t *testing.T) {
 		{
 			description: "cleanup error",
 			cfg: &latest.KustomizeDeploy{
-				KustomizePath: tmpDir.Root(),
+				KustomizePaths: []string{tmpDir.Root()},
 			},
 			command: testutil.NewFakeCmd(t).
 				WithRunOut("kustomize build "+tmpDir.Root(), deploymentWebYAML).
@@ -125,7 +141,7 @@ func TestKustomizeCleanup(t *testing.T) {
 		{
 			description: "fail to read manifests",
 			cfg: &latest.KustomizeDeploy{
-				KustomizePath: tmpDir.Root(),
+				KustomizePaths: []string{tmpDir.Root()},
 			},
 			command:   testutil.FakeRunOutErr(t, "kustomize build "+tmpDir.Root(), ``, errors.New("BUG")),
 			shouldErr: true,
@@ -199,6 +215,51 @@ func TestDependenciesForKustomization(t *testing.T) {
 - files: [secret2.file, secret3.file]`,
 			expected: []string{"kustomization.yaml", "secret1.file", "secret2.file", "secret3.file"},
 		},
+		{
+			description: "patches with paths and inline patches",
+			yaml: `patches:
+- path: patch1.yaml
+- patch: |-
+    - op: remove
+      path: /spec/replicas
+  target:
+    kind: Deployment
+patchesStrategicMerge:
+- patch2.yaml
+- |-
+  apiVersion: apps/v1
+  kind: Deployment`,
+			expected: []string{"kustomization.yaml", "patch1.yaml", "patch2.yaml"},
+		},
+		{
+			description: "generators with keys and env files",
+			yaml: `configMapGenerator:
+- files: [key=app.properties]
+  env: app.env
+secretGenerator:
+- envs: [secret.env]`,
+			expected: []string{"kustomization.yaml", "app.properties", "app.env", "secret.env"},
+		},
+		{
+			description: "configurations, transformers and openapi",
+			yaml: `configurations: [config.yaml]
+transformers: [transformer.yaml]
+openapi:
+  path: schema.json`,
+			expected: []string{"kustomization.yaml", "transformer.yaml", "config.yaml", "schema.json"},
+		},
+		{
+			description: "remote bases and resources are skipped",
+			yaml: `bases:
+- github.com/org/repo//base?ref=v1
+resources:
+- https://example.com/pod.yaml
+- git@github.com:org/repo.git
+- pod.yaml
+components:
+- git::https://github.com/org/repo//components`,
+			expected: []string{"kustomization.yaml", "pod.yaml"},
+		},
 		{
 			description: "unknown base",
 			yaml:        `bases: [other]`,
@@ -215,7 +276,7 @@ func TestDependenciesForKustomization(t *testing.T) {
 					Deploy: latest.DeployConfig{
 						DeployType: latest.DeployType{
 							KustomizeDeploy: &latest.KustomizeDeploy{
-								KustomizePath: tmp.Root(),
+								KustomizePaths: []string{tmp.Root()},
 							},
 						},
 					},
@@ -229,3 +290,43 @@ func TestDependenciesForKustomization(t *testing.T) {
 		})
 	}
 }
+
+func TestDependenciesForKustomizationPaths(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmp := t.NewTempDir().
+			Write("base/kustomization.yml", `resources: [deployment.yaml]`).
+			Write("base/deployment.yaml", "").
+			Write("components/monitoring/Kustomization", `resources: [monitor.yaml]`).
+			Write("components/monitoring/monitor.yaml", "").
+			Write("overlays/dev/kustomization.yaml", `resources: [../../base]
+components: [../../components/monitoring]`).
+			Write("overlays/prod/kustomization.yaml", `bases: [../../base]
+patchesStrategicMerge: [replicas.yaml]`).
+			Write("overlays/prod/replicas.yaml", "")
+
+		k := NewKustomizeDeployer(&runcontext.RunContext{
+			Cfg: &latest.Pipeline{
+				Deploy: latest.DeployConfig{
+					DeployType: latest.DeployType{
+						KustomizeDeploy: &latest.KustomizeDeploy{
+							KustomizePaths: []string{tmp.Path("overlays/dev"), tmp.Path("overlays/prod")},
+						},
+					},
+				},
+			},
+			KubeContext: testKubeContext,
+			Opts:        &config.SkaffoldOptions{},
+		})
+		deps, err := k.Dependencies()
+
+		t.CheckErrorAndDeepEqual(false, err, joinPaths(tmp.Root(), []string{
+			"components/monitoring/Kustomization",
+			"components/monitoring/monitor.yaml",
+			"overlays/dev/kustomization.yaml",
+			"base/kustomization.yml",
+			"base/deployment.yaml",
+			"overlays/prod/kustomization.yaml",
+			"overlays/prod/replicas.yaml",
+		}), deps)
+	})
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+// RequiresBuildKit tells if an artifact uses features that only BuildKit supports.
+// The Docker Engine API client doesn't support them, so such artifacts are built
+// with the docker CLI.
+func RequiresBuildKit(a *latest.DockerArtifact) bool {
+	return len(a.Secrets) > 0 || len(a.SSH) > 0 || RequiresBuildx(a)
+}
+
+// RequiresBuildx tells if an artifact must be built with `docker buildx`.
+func RequiresBuildx(a *latest.DockerArtifact) bool {
+	return len(a.CacheTo) > 0
+}
+
+// GetBuildKitArgs gives the docker CLI flags for the features that only BuildKit supports.
+func GetBuildKitArgs(workspace string, a *latest.DockerArtifact) ([]string, error) {
+	var args []string
+
+	for _, secret := range a.Secrets {
+		src, err := evaluateBuildArgsValue(secret.Source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get source of secret: %s", secret.ID)
+		}
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(workspace, src)
+		}
+
+		args = append(args, "--secret", fmt.Sprintf("id=%s,src=%s", secret.ID, src))
+	}
+
+	for _, ssh := range a.SSH {
+		args = append(args, "--ssh", ssh)
+	}
+
+	for _, cacheTo := range a.CacheTo {
+		args = append(args, "--cache-to", cacheTo)
+	}
+
+	return args, nil
+}
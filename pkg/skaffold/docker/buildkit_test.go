@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestRequiresBuildKit(t *testing.T) {
+	tests := []struct {
+		description      string
+		artifact         *latest.DockerArtifact
+		expectedBuildKit bool
+		expectedBuildx   bool
+	}{
+		{
+			description: "plain artifact",
+			artifact:    &latest.DockerArtifact{CacheFrom: []string{"gcr.io/project/app"}},
+		},
+		{
+			description:      "secrets",
+			artifact:         &latest.DockerArtifact{Secrets: []*latest.DockerSecret{{ID: "npmrc", Source: ".npmrc"}}},
+			expectedBuildKit: true,
+		},
+		{
+			description:      "ssh",
+			artifact:         &latest.DockerArtifact{SSH: []string{"default"}},
+			expectedBuildKit: true,
+		},
+		{
+			description:      "cacheTo",
+			artifact:         &latest.DockerArtifact{CacheTo: []string{"type=registry,ref=gcr.io/project/app:cache"}},
+			expectedBuildKit: true,
+			expectedBuildx:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.CheckDeepEqual(test.expectedBuildKit, RequiresBuildKit(test.artifact))
+			t.CheckDeepEqual(test.expectedBuildx, RequiresBuildx(test.artifact))
+		})
+	}
+}
+
+func TestGetBuildKitArgs(t *testing.T) {
+	tests := []struct {
+		description string
+		artifact    *latest.DockerArtifact
+		env         []string
+		expected    []string
+		shouldErr   bool
+	}{
+		{
+			description: "no buildkit features",
+			artifact:    &latest.DockerArtifact{},
+		},
+		{
+			description: "secrets",
+			artifact: &latest.DockerArtifact{Secrets: []*latest.DockerSecret{
+				{ID: "npmrc", Source: ".npmrc"},
+				{ID: "token", Source: "/etc/token"},
+			}},
+			expected: []string{"--secret", "id=npmrc,src=/workspace/.npmrc", "--secret", "id=token,src=/etc/token"},
+		},
+		{
+			description: "secret source from env",
+			artifact: &latest.DockerArtifact{Secrets: []*latest.DockerSecret{
+				{ID: "npmrc", Source: "{{.HOME}}/.npmrc"},
+			}},
+			env:      []string{"HOME=/home/user"},
+			expected: []string{"--secret", "id=npmrc,src=/home/user/.npmrc"},
+		},
+		{
+			description: "invalid secret source",
+			artifact: &latest.DockerArtifact{Secrets: []*latest.DockerSecret{
+				{ID: "npmrc", Source: "{{"},
+			}},
+			shouldErr: true,
+		},
+		{
+			description: "ssh and cacheTo",
+			artifact: &latest.DockerArtifact{
+				SSH:     []string{"default", "github=/home/user/.ssh/id_rsa"},
+				CacheTo: []string{"type=registry,ref=gcr.io/project/app:cache,mode=max"},
+			},
+			expected: []string{"--ssh", "default", "--ssh", "github=/home/user/.ssh/id_rsa", "--cache-to", "type=registry,ref=gcr.io/project/app:cache,mode=max"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&util.OSEnviron, func() []string { return test.env })
+
+			args, err := GetBuildKitArgs("/workspace", test.artifact)
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, args)
+		})
+	}
+}
@@ -47,6 +47,7 @@ type LocalDaemon interface {
 	Push(ctx context.Context, out io.Writer, ref string) (string, error)
 	Pull(ctx context.Context, out io.Writer, ref string) error
 	Load(ctx context.Context, out io.Writer, input io.Reader, ref string) (string, error)
+	Save(ctx context.Context, ref string) (io.ReadCloser, error)
 	Tag(ctx context.Context, image, ref string) error
 	ImageID(ctx context.Context, ref string) (string, error)
 	ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error)
@@ -162,6 +163,7 @@ func (l *localDaemon) Build(ctx context.Context, out io.Writer, workspace string
 		ForceRemove: l.forceRemove,
 		NetworkMode: a.NetworkMode,
 		NoCache:     a.NoCache,
+		Platform:    strings.Join(a.Platforms, ","),
 	})
 	if err != nil {
 		return "", errors.Wrap(err, "docker build")
@@ -284,6 +286,15 @@ func (l *localDaemon) Load(ctx context.Context, out io.Writer, input io.Reader,
 	return l.ImageID(ctx, ref)
 }
 
+// Save exports an image as a docker tarball.
+func (l *localDaemon) Save(ctx context.Context, ref string) (io.ReadCloser, error) {
+	rc, err := l.apiClient.ImageSave(ctx, []string{ref})
+	if err != nil {
+		return nil, errors.Wrap(err, "saving image from docker daemon")
+	}
+	return rc, nil
+}
+
 // Tag adds a tag to an image.
 func (l *localDaemon) Tag(ctx context.Context, image, ref string) error {
 	return l.apiClient.ImageTag(ctx, image, ref)
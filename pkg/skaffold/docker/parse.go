@@ -85,6 +85,9 @@ func expandBuildArgs(nodes []*parser.Node, buildArgs map[string]*string) error {
 			for curr := node; curr != nil; curr = curr.Next {
 				curr.Value = util.Expand(curr.Value, key, value)
 			}
+			for j, flag := range node.Flags {
+				node.Flags[j] = util.Expand(flag, key, value)
+			}
 		}
 	}
 	return nil
@@ -170,6 +173,15 @@ func copiedFiles(nodes []*parser.Node) ([][]string, error) {
 			if len(files) > 0 {
 				copied = append(copied, files)
 			}
+		case command.Run:
+			mounted, err := processMounts(node, envs)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, source := range mounted {
+				copied = append(copied, []string{source})
+			}
 		case command.Env:
 			// one env command may define multiple variables
 			for node := node.Next; node != nil && node.Next != nil; node = node.Next.Next {
@@ -426,6 +438,60 @@ func processCopy(value *parser.Node, envs map[string]string) ([]string, error) {
 	return copied, nil
 }
 
+// processMounts lists the sources of `RUN --mount=type=bind` instructions
+// that are read from the build context.
+func processMounts(value *parser.Node, envs map[string]string) ([]string, error) {
+	var mounted []string
+
+	slex := shell.NewLex('\\')
+	for _, flag := range value.Flags {
+		if !strings.HasPrefix(flag, "--mount=") {
+			continue
+		}
+
+		// Other mount types and bind mounts from another stage or image
+		// don't read the build context
+		mount := parseMount(strings.TrimPrefix(flag, "--mount="))
+		if mount["type"] != "bind" || mount["from"] != "" {
+			continue
+		}
+
+		src, err := processShellWord(slex, mount["source"], envs)
+		if err != nil {
+			return nil, errors.Wrap(err, "processing word")
+		}
+		mounted = append(mounted, src)
+	}
+
+	return mounted, nil
+}
+
+// parseMount parses the options of a `--mount` flag.
+// Like BuildKit, it defaults to bind mounting the root of the build context.
+func parseMount(value string) map[string]string {
+	mount := map[string]string{
+		"type":   "bind",
+		"source": ".",
+	}
+
+	for _, field := range strings.Split(value, ",") {
+		parts := strings.SplitN(field, "=", 2)
+
+		key := strings.ToLower(parts[0])
+		if key == "src" {
+			key = "source"
+		}
+
+		if len(parts) == 2 {
+			mount[key] = parts[1]
+		} else {
+			mount[key] = "true"
+		}
+	}
+
+	return mount
+}
+
 func processShellWord(lex *shell.Lex, word string, envs map[string]string) (string, error) {
 	envSlice := []string{}
 	for envKey, envVal := range envs {
@@ -195,6 +195,33 @@ FROM jboss/wildfly:14.0.1.Final
 ADD ./file /etc/file
 `
 
+const buildKitMounts = `
+# syntax=docker/dockerfile:1.2
+FROM golang:1.9.2 AS builder
+RUN --mount=type=cache,target=/root/.cache --mount=type=secret,id=npmrc --mount=type=ssh go version
+RUN --mount=type=bind,source=server.go,target=/src/server.go go build /src/server.go
+RUN --mount=src=worker.go,target=/worker.go true
+RUN --mount=type=bind,from=builder,source=/go/bin,target=/bin true
+`
+
+const buildKitMountContext = `
+FROM nginx
+RUN --mount=target=/src ls /src
+`
+
+const buildKitMountBuildArg = `
+FROM nginx
+ARG SRC
+RUN --mount=type=bind,source=$SRC,target=/src true
+`
+
+const buildKitCopyFlags = `
+FROM ubuntu:14.04
+COPY --chmod=755 server.go /server.go
+ADD --chown=1000 --chmod=600 file /file
+COPY --from=nginx /etc/nginx/nginx.conf /nginx.conf
+`
+
 type fakeImageFetcher struct {
 	fetched []string
 }
@@ -496,6 +523,35 @@ func TestGetDependencies(t *testing.T) {
 			expected:    []string{"Dockerfile", "server.go"},
 			fetched:     []string{"ubuntu:14.04"},
 		},
+		{
+			description: "buildkit mounts",
+			dockerfile:  buildKitMounts,
+			workspace:   ".",
+			expected:    []string{"Dockerfile", "server.go", "worker.go"},
+			fetched:     []string{"golang:1.9.2"},
+		},
+		{
+			description: "buildkit mount of the build context",
+			dockerfile:  buildKitMountContext,
+			workspace:   ".",
+			expected:    []string{".dot", "Dockerfile", "bar", "docker/bar", "docker/nginx.conf", "file", "server.go", "test.conf", "worker.go"},
+			fetched:     []string{"nginx"},
+		},
+		{
+			description: "buildkit mount with a build arg",
+			dockerfile:  buildKitMountBuildArg,
+			workspace:   ".",
+			buildArgs:   map[string]*string{"SRC": util.StringPtr("worker.go")},
+			expected:    []string{"Dockerfile", "worker.go"},
+			fetched:     []string{"nginx"},
+		},
+		{
+			description: "buildkit copy flags",
+			dockerfile:  buildKitCopyFlags,
+			workspace:   ".",
+			expected:    []string{"Dockerfile", "file", "server.go"},
+			fetched:     []string{"ubuntu:14.04"},
+		},
 		{
 			description: "invalid go template as build arg",
 			dockerfile:  copyServerGoBuildArg,
@@ -0,0 +1,193 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	// For testing
+	getRemoteDescriptorImpl = getRemoteDescriptor
+	writeIndexImpl          = writeIndex
+)
+
+// ParsePlatform parses a platform in the `os/arch[/variant]` format.
+func ParsePlatform(platform string) (v1.Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return v1.Platform{}, fmt.Errorf("invalid platform %q, should be os/arch[/variant]", platform)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return v1.Platform{}, fmt.Errorf("invalid platform %q, should be os/arch[/variant]", platform)
+		}
+	}
+
+	p := v1.Platform{
+		OS:           parts[0],
+		Architecture: parts[1],
+	}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// PlatformTag is the tag used for the image of a single platform,
+// before it's added to the image index.
+// For example, `app:v1` is built as `app:v1-linux-arm64` for `linux/arm64`.
+func PlatformTag(tag, platform string) (string, error) {
+	parsed, err := ParseReference(tag)
+	if err != nil {
+		return "", err
+	}
+
+	t := parsed.Tag
+	if t == "" {
+		t = "latest"
+	}
+	return parsed.BaseName + ":" + t + "-" + strings.Replace(platform, "/", "-", -1), nil
+}
+
+// CreateImageIndex combines images, already pushed, into an OCI image index
+// and pushes it to the given tag. It returns the digest of the image index.
+func CreateImageIndex(tag string, platforms, images []string, insecureRegistries map[string]bool) (string, error) {
+	manifest := &v1.IndexManifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIImageIndex,
+	}
+
+	for i, image := range images {
+		platform, err := ParsePlatform(platforms[i])
+		if err != nil {
+			return "", err
+		}
+
+		ref, err := parseReference(image, insecureRegistries)
+		if err != nil {
+			return "", errors.Wrapf(err, "parsing %s", image)
+		}
+
+		desc, err := getRemoteDescriptorImpl(ref)
+		if err != nil {
+			return "", errors.Wrapf(err, "getting manifest of %s", image)
+		}
+		desc.Platform = &platform
+
+		manifest.Manifests = append(manifest.Manifests, *desc)
+	}
+
+	ref, err := parseReference(tag, insecureRegistries)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing %s", tag)
+	}
+
+	index := &imageIndex{manifest: manifest}
+	if err := writeIndexImpl(ref, index); err != nil {
+		return "", errors.Wrap(err, "pushing image index")
+	}
+
+	digest, err := index.Digest()
+	if err != nil {
+		return "", errors.Wrap(err, "getting digest")
+	}
+
+	return digest.String(), nil
+}
+
+func parseReference(identifier string, insecureRegistries map[string]bool) (name.Reference, error) {
+	ref, err := name.ParseReference(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if isInsecure(ref.Context().Registry.Name(), insecureRegistries) {
+		return getInsecureRegistryImpl(identifier)
+	}
+
+	return ref, nil
+}
+
+func getRemoteDescriptor(ref name.Reference) (*v1.Descriptor, error) {
+	auth, err := authn.DefaultKeychain.Resolve(ref.Context().Registry)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting default keychain auth")
+	}
+
+	desc, err := remote.Get(ref, remote.WithAuth(auth))
+	if err != nil {
+		return nil, err
+	}
+
+	return &desc.Descriptor, nil
+}
+
+func writeIndex(ref name.Reference, index v1.ImageIndex) error {
+	auth, err := authn.DefaultKeychain.Resolve(ref.Context().Registry)
+	if err != nil {
+		return errors.Wrap(err, "getting default keychain auth")
+	}
+
+	return remote.WriteIndex(ref, index, auth, http.DefaultTransport)
+}
+
+// imageIndex is an image index whose images are already pushed.
+type imageIndex struct {
+	manifest *v1.IndexManifest
+}
+
+func (i *imageIndex) MediaType() (types.MediaType, error) {
+	return i.manifest.MediaType, nil
+}
+
+func (i *imageIndex) Digest() (v1.Hash, error) {
+	raw, err := i.RawManifest()
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	h, _, err := v1.SHA256(bytes.NewReader(raw))
+	return h, err
+}
+
+func (i *imageIndex) IndexManifest() (*v1.IndexManifest, error) {
+	return i.manifest, nil
+}
+
+func (i *imageIndex) RawManifest() ([]byte, error) {
+	return json.Marshal(i.manifest)
+}
+
+func (i *imageIndex) Image(h v1.Hash) (v1.Image, error) {
+	return nil, fmt.Errorf("image %s should already be pushed", h)
+}
+
+func (i *imageIndex) ImageIndex(h v1.Hash) (v1.ImageIndex, error) {
+	return nil, fmt.Errorf("image index %s should already be pushed", h)
+}
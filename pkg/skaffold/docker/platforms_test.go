@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		platform  string
+		expected  v1.Platform
+		shouldErr bool
+	}{
+		{platform: "linux/amd64", expected: v1.Platform{OS: "linux", Architecture: "amd64"}},
+		{platform: "linux/arm64/v8", expected: v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}},
+		{platform: "linux", shouldErr: true},
+		{platform: "linux/", shouldErr: true},
+		{platform: "linux/arm/v7/extra", shouldErr: true},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.platform, func(t *testutil.T) {
+			platform, err := ParsePlatform(test.platform)
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, platform)
+		})
+	}
+}
+
+func TestPlatformTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		platform string
+		expected string
+	}{
+		{tag: "gcr.io/project/app:v1", platform: "linux/arm64", expected: "gcr.io/project/app:v1-linux-arm64"},
+		{tag: "localhost:5000/app", platform: "linux/arm/v7", expected: "localhost:5000/app:latest-linux-arm-v7"},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.tag, func(t *testutil.T) {
+			tag, err := PlatformTag(test.tag, test.platform)
+
+			t.CheckErrorAndDeepEqual(false, err, test.expected, tag)
+		})
+	}
+}
+
+func fakeDigest(content string) v1.Hash {
+	h, _, _ := v1.SHA256(bytes.NewReader([]byte(content)))
+	return h
+}
+
+func TestCreateImageIndex(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&getRemoteDescriptorImpl, func(ref name.Reference) (*v1.Descriptor, error) {
+			return &v1.Descriptor{
+				MediaType: types.DockerManifestSchema2,
+				Size:      100,
+				Digest:    fakeDigest(ref.Identifier()),
+			}, nil
+		})
+		var (
+			pushedTo  string
+			pushedRaw []byte
+		)
+		t.Override(&writeIndexImpl, func(ref name.Reference, index v1.ImageIndex) error {
+			pushedTo = ref.String()
+			raw, err := index.RawManifest()
+			pushedRaw = raw
+			return err
+		})
+
+		digest, err := CreateImageIndex("gcr.io/project/app:v1", []string{"linux/amd64", "linux/arm64"}, []string{"gcr.io/project/app:amd64", "gcr.io/project/app:arm64"}, nil)
+		t.CheckError(false, err)
+
+		var manifest v1.IndexManifest
+		err = json.Unmarshal(pushedRaw, &manifest)
+		t.CheckError(false, err)
+		t.CheckDeepEqual("gcr.io/project/app:v1", pushedTo)
+		t.CheckDeepEqual(types.OCIImageIndex, manifest.MediaType)
+		t.CheckDeepEqual([]v1.Descriptor{
+			{
+				MediaType: types.DockerManifestSchema2,
+				Size:      100,
+				Digest:    fakeDigest("amd64"),
+				Platform:  &v1.Platform{OS: "linux", Architecture: "amd64"},
+			},
+			{
+				MediaType: types.DockerManifestSchema2,
+				Size:      100,
+				Digest:    fakeDigest("arm64"),
+				Platform:  &v1.Platform{OS: "linux", Architecture: "arm64"},
+			},
+		}, manifest.Manifests)
+
+		expectedDigest, _, _ := v1.SHA256(bytes.NewReader(pushedRaw))
+		t.CheckDeepEqual(expectedDigest.String(), digest)
+	})
+}
+
+func TestCreateImageIndexError(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&getRemoteDescriptorImpl, func(name.Reference) (*v1.Descriptor, error) {
+			return nil, fmt.Errorf("not found")
+		})
+
+		_, err := CreateImageIndex("app:v1", []string{"linux/amd64"}, []string{"app:amd64"}, nil)
+
+		t.CheckErrorContains("getting manifest of app:amd64", err)
+	})
+}
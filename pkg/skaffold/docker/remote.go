@@ -55,6 +55,11 @@ func RetrieveRemoteConfig(identifier string, insecureRegistries map[string]bool)
 	return img.ConfigFile()
 }
 
+// RemoteImage retrieves an image from its registry.
+func RemoteImage(identifier string, insecureRegistries map[string]bool) (v1.Image, error) {
+	return remoteImage(identifier, insecureRegistries)
+}
+
 func remoteImage(identifier string, insecureRegistries map[string]bool) (v1.Image, error) {
 	ref, err := name.ParseReference(identifier)
 	if err != nil {
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
@@ -142,14 +143,22 @@ func emptyState(build *latest.BuildConfig) proto.State {
 			Status: NotStarted,
 		},
 		ForwardedPorts: make(map[string]*proto.PortEvent),
+		VerifyState: &proto.VerifyState{
+			Tests: map[string]string{},
+		},
 	}
 }
 
 // InitializeState instantiates the global state of the skaffold runner, as well as the event log.
 func InitializeState(runCtx *runcontext.RunContext) {
 	once.Do(func() {
+		state := emptyState(&runCtx.Cfg.Build)
+		for _, tc := range runCtx.Cfg.Verify {
+			state.VerifyState.Tests[tc.Name] = NotStarted
+		}
+
 		handler = &eventHandler{
-			state: emptyState(&runCtx.Cfg.Build),
+			state: state,
 		}
 	})
 }
@@ -169,6 +178,21 @@ func DeployComplete() {
 	handler.handleDeployEvent(&proto.DeployEvent{Status: Complete})
 }
 
+// DeployerInProgress notifies that one of several deployers has been started.
+func DeployerInProgress(deployer string) {
+	handler.handleDeployEvent(&proto.DeployEvent{Deployer: deployer, Status: InProgress})
+}
+
+// DeployerFailed notifies that one of several deployers has failed.
+func DeployerFailed(deployer string, err error) {
+	handler.handleDeployEvent(&proto.DeployEvent{Deployer: deployer, Status: Failed, Err: err.Error()})
+}
+
+// DeployerComplete notifies that one of several deployers has completed.
+func DeployerComplete(deployer string) {
+	handler.handleDeployEvent(&proto.DeployEvent{Deployer: deployer, Status: Complete})
+}
+
 // BuildInProgress notifies that a build has been started.
 func BuildInProgress(imageName string) {
 	handler.handleBuildEvent(&proto.BuildEvent{Artifact: imageName, Status: InProgress})
@@ -184,6 +208,40 @@ func BuildComplete(imageName string) {
 	handler.handleBuildEvent(&proto.BuildEvent{Artifact: imageName, Status: Complete})
 }
 
+// VerifyInProgress notifies that a verification test has been started.
+func VerifyInProgress(name string) {
+	handler.handleVerifyEvent(&proto.VerifyEvent{Name: name, Status: InProgress})
+}
+
+// VerifyFailed notifies that a verification test has failed.
+func VerifyFailed(name string, err error) {
+	handler.handleVerifyEvent(&proto.VerifyEvent{Name: name, Status: Failed, Err: err.Error()})
+}
+
+// VerifyComplete notifies that a verification test has succeeded.
+func VerifyComplete(name string) {
+	handler.handleVerifyEvent(&proto.VerifyEvent{Name: name, Status: Complete})
+}
+
+// TestInProgress notifies that a test has been started on an artifact.
+func TestInProgress(imageName, testName string) {
+	handler.handleTestEvent(&proto.TestEvent{Artifact: imageName, Name: testName, Status: InProgress})
+}
+
+// TestFailed notifies that a test has failed on an artifact.
+func TestFailed(imageName, testName string, duration time.Duration, err error) {
+	handler.handleTestEvent(&proto.TestEvent{Artifact: imageName, Name: testName, Status: Failed, Err: err.Error(), DurationMs: durationMs(duration)})
+}
+
+// TestComplete notifies that a test has passed on an artifact.
+func TestComplete(imageName, testName string, duration time.Duration) {
+	handler.handleTestEvent(&proto.TestEvent{Artifact: imageName, Name: testName, Status: Complete, DurationMs: durationMs(duration)})
+}
+
+func durationMs(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
+
 // PortForwarded notifies that a remote port has been forwarded locally.
 func PortForwarded(localPort, remotePort int32, podName, containerName, namespace string, portName string) {
 	go handler.handle(&proto.Event{
@@ -216,6 +274,22 @@ func (ev *eventHandler) handleBuildEvent(e *proto.BuildEvent) {
 	})
 }
 
+func (ev *eventHandler) handleVerifyEvent(e *proto.VerifyEvent) {
+	go ev.handle(&proto.Event{
+		EventType: &proto.Event_VerifyEvent{
+			VerifyEvent: e,
+		},
+	})
+}
+
+func (ev *eventHandler) handleTestEvent(e *proto.TestEvent) {
+	go ev.handle(&proto.Event{
+		EventType: &proto.Event_TestEvent{
+			TestEvent: e,
+		},
+	})
+}
+
 func LogSkaffoldMetadata(info *version.Info) {
 	handler.logEvent(proto.LogEntry{
 		Timestamp: ptypes.TimestampNow(),
@@ -253,6 +327,18 @@ func (ev *eventHandler) handle(event *proto.Event) {
 		}
 	case *proto.Event_DeployEvent:
 		de := e.DeployEvent
+		if de.Deployer != "" {
+			switch de.Status {
+			case InProgress:
+				logEntry.Entry = fmt.Sprintf("Deploy started for %s", de.Deployer)
+			case Complete:
+				logEntry.Entry = fmt.Sprintf("Deploy complete for %s", de.Deployer)
+			case Failed:
+				logEntry.Entry = fmt.Sprintf("Deploy failed for %s", de.Deployer)
+			default:
+			}
+			break
+		}
 		ev.stateLock.Lock()
 		ev.state.DeployState.Status = de.Status
 		ev.stateLock.Unlock()
@@ -266,6 +352,31 @@ func (ev *eventHandler) handle(event *proto.Event) {
 			// logEntry.Err = de.Err
 		default:
 		}
+	case *proto.Event_VerifyEvent:
+		ve := e.VerifyEvent
+		ev.stateLock.Lock()
+		ev.state.VerifyState.Tests[ve.Name] = ve.Status
+		ev.stateLock.Unlock()
+		switch ve.Status {
+		case InProgress:
+			logEntry.Entry = fmt.Sprintf("Verification started for %s", ve.Name)
+		case Complete:
+			logEntry.Entry = fmt.Sprintf("Verification succeeded for %s", ve.Name)
+		case Failed:
+			logEntry.Entry = fmt.Sprintf("Verification failed for %s", ve.Name)
+		default:
+		}
+	case *proto.Event_TestEvent:
+		te := e.TestEvent
+		switch te.Status {
+		case InProgress:
+			logEntry.Entry = fmt.Sprintf("Test %s started for artifact %s", te.Name, te.Artifact)
+		case Complete:
+			logEntry.Entry = fmt.Sprintf("Test %s passed for artifact %s", te.Name, te.Artifact)
+		case Failed:
+			logEntry.Entry = fmt.Sprintf("Test %s failed for artifact %s", te.Name, te.Artifact)
+		default:
+		}
 	case *proto.Event_PortEvent:
 		pe := e.PortEvent
 		ev.stateLock.Lock()
@@ -103,6 +103,22 @@ func TestDeployComplete(t *testing.T) {
 	wait(t, func() bool { return handler.getState().DeployState.Status == Complete })
 }
 
+func TestDeployerEvents(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	DeployInProgress()
+	wait(t, func() bool { return handler.getState().DeployState.Status == InProgress })
+	DeployerComplete("helm")
+	wait(t, func() bool { return countEvents(handler) == 2 })
+
+	testutil.CheckDeepEqual(t, InProgress, handler.getState().DeployState.Status)
+	testutil.CheckDeepEqual(t, "Deploy complete for helm", handler.eventLog[1].Entry)
+}
+
 func TestBuildInProgress(t *testing.T) {
 	defer func() { handler = nil }()
 
@@ -163,6 +179,62 @@ func TestPortForwarded(t *testing.T) {
 	wait(t, func() bool { return handler.getState().ForwardedPorts["container"] != nil })
 }
 
+func TestVerifyInProgress(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	wait(t, func() bool { return handler.getState().VerifyState.Tests["integration"] == "" })
+	VerifyInProgress("integration")
+	wait(t, func() bool { return handler.getState().VerifyState.Tests["integration"] == InProgress })
+}
+
+func TestVerifyFailed(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	VerifyFailed("integration", errors.New("BUG"))
+	wait(t, func() bool { return handler.getState().VerifyState.Tests["integration"] == Failed })
+}
+
+func TestVerifyComplete(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	VerifyComplete("integration")
+	wait(t, func() bool { return handler.getState().VerifyState.Tests["integration"] == Complete })
+}
+
+func TestTestEvents(t *testing.T) {
+	defer func() { handler = nil }()
+
+	handler = &eventHandler{
+		state: emptyState(nil),
+	}
+
+	TestInProgress("img", "unit")
+	wait(t, func() bool { return countEvents(handler) == 1 })
+	TestComplete("img", "unit", time.Second)
+	wait(t, func() bool { return countEvents(handler) == 2 })
+	TestFailed("img", "contract", time.Second, errors.New("BUG"))
+	wait(t, func() bool { return countEvents(handler) == 3 })
+}
+
+func countEvents(ev *eventHandler) int {
+	ev.logLock.Lock()
+	defer ev.logLock.Unlock()
+
+	return len(ev.eventLog)
+}
+
 func wait(t *testing.T, condition func() bool) {
 	ticker := time.NewTicker(10 * time.Millisecond)
 	defer ticker.Stop()
@@ -0,0 +1,152 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Report describes the effective configuration and how it was obtained.
+type Report struct {
+	Profiles []Profile   `yaml:"profiles" json:"profiles"`
+	Config   interface{} `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// Profile describes an activated profile.
+type Profile struct {
+	Name    string  `yaml:"name" json:"name"`
+	Reason  string  `yaml:"reason" json:"reason"`
+	Patches []Patch `yaml:"patches,omitempty" json:"patches,omitempty"`
+}
+
+// Patch describes the outcome of a profile's JSON patch.
+type Patch struct {
+	Op      string `yaml:"op" json:"op"`
+	Path    string `yaml:"path" json:"path"`
+	Applied bool   `yaml:"applied" json:"applied"`
+	Error   string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// NewReport builds a report from the activated profiles and the effective configuration.
+// The configuration can be nil if it couldn't be computed.
+func NewReport(profiles []schema.ActivatedProfile, config *latest.SkaffoldConfig) (*Report, error) {
+	report := &Report{
+		Profiles: []Profile{},
+	}
+
+	for _, p := range profiles {
+		profile := Profile{
+			Name:   p.Name,
+			Reason: p.Reason,
+		}
+
+		for _, result := range p.Patches {
+			patch := Patch{
+				Op:      result.Op,
+				Path:    result.Path,
+				Applied: result.Err == nil,
+			}
+			if result.Err != nil {
+				patch.Error = result.Err.Error()
+			}
+
+			profile.Patches = append(profile.Patches, patch)
+		}
+
+		report.Profiles = append(report.Profiles, profile)
+	}
+
+	if config != nil {
+		// Profiles are already applied.
+		effective := *config
+		effective.Profiles = nil
+
+		generic, err := Generic(effective)
+		if err != nil {
+			return nil, errors.Wrap(err, "converting configuration")
+		}
+		report.Config = generic
+	}
+
+	return report, nil
+}
+
+// Generic converts a value to the maps, lists and scalars
+// it is made of when marshalled to yaml.
+func Generic(value interface{}) (interface{}, error) {
+	buf, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := yaml.Unmarshal(buf, &generic); err != nil {
+		return nil, err
+	}
+
+	return withStringKeys(generic), nil
+}
+
+// withStringKeys replaces the maps decoded by yaml, that have
+// interface{} keys, with maps that can be marshalled to json.
+func withStringKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, value := range v {
+			m[fmt.Sprintf("%v", key)] = withStringKeys(value)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = withStringKeys(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// Print writes a value to out, either as `yaml` or as `json`.
+func Print(out io.Writer, value interface{}, format string) error {
+	var buf []byte
+	var err error
+
+	switch format {
+	case "yaml":
+		buf, err = yaml.Marshal(value)
+	case "json":
+		buf, err = json.MarshalIndent(value, "", "  ")
+		buf = append(buf, '\n')
+	default:
+		return fmt.Errorf("unknown output format %q, should be yaml or json", format)
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "marshalling to %s", format)
+	}
+
+	_, err = out.Write(buf)
+	return err
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspect
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestNewReport(t *testing.T) {
+	profiles := []schema.ActivatedProfile{
+		{
+			Name:   "local",
+			Reason: "selected on the command line",
+			Patches: []schema.PatchResult{
+				{Op: "replace", Path: "/build/artifacts/0/image"},
+				{Op: "remove", Path: "/build/artifacts/1", Err: errors.New("invalid path: /build/artifacts/1")},
+			},
+		},
+	}
+	config := &latest.SkaffoldConfig{
+		APIVersion: latest.Version,
+		Kind:       "Config",
+		Pipeline: latest.Pipeline{
+			Build: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{ImageName: "image"}},
+			},
+		},
+		Profiles: []latest.Profile{{Name: "local"}},
+	}
+
+	report, err := NewReport(profiles, config)
+
+	testutil.CheckError(t, false, err)
+	testutil.CheckDeepEqual(t, []Profile{{
+		Name:   "local",
+		Reason: "selected on the command line",
+		Patches: []Patch{
+			{Op: "replace", Path: "/build/artifacts/0/image", Applied: true},
+			{Op: "remove", Path: "/build/artifacts/1", Error: "invalid path: /build/artifacts/1"},
+		},
+	}}, report.Profiles)
+
+	images, err := Query(report.Config, "build.artifacts[*].image")
+	testutil.CheckErrorAndDeepEqual(t, false, err, []interface{}{"image"}, images)
+
+	_, err = Query(report.Config, "profiles")
+	testutil.CheckError(t, true, err)
+}
+
+func TestPrint(t *testing.T) {
+	value := map[string]interface{}{"image": "image1"}
+
+	tests := []struct {
+		format    string
+		expected  string
+		shouldErr bool
+	}{
+		{format: "yaml", expected: "image: image1\n"},
+		{format: "json", expected: "{\n  \"image\": \"image1\"\n}\n"},
+		{format: "xml", shouldErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := Print(&out, value, test.format)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, out.String())
+		})
+	}
+}
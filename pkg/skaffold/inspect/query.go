@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspect
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// wildcard selects every item of a list.
+const wildcard = -1
+
+var segmentRegex = regexp.MustCompile(`^([^\[\]]*)((?:\[(?:\d+|\*)\])*)$`)
+
+type segment struct {
+	field   string
+	indexes []int
+}
+
+// Query returns the part of a generic value found at the given path.
+// A path is a list of fields separated by dots. Each field can be followed
+// by list indexes such as `[0]`, or by `[*]` to select every item of a list.
+// For example: `build.artifacts[*].image`.
+func Query(value interface{}, path string) (interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return query(value, segments, "")
+}
+
+func parsePath(path string) ([]segment, error) {
+	var segments []segment
+
+	for _, part := range strings.Split(path, ".") {
+		matches := segmentRegex.FindStringSubmatch(part)
+		if matches == nil || (matches[1] == "" && matches[2] == "") {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+
+		s := segment{field: matches[1]}
+		for _, index := range strings.Split(strings.Trim(matches[2], "[]"), "][") {
+			switch index {
+			case "":
+			case "*":
+				s.indexes = append(s.indexes, wildcard)
+			default:
+				i, err := strconv.Atoi(index)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q in path %q", index, path)
+				}
+				s.indexes = append(s.indexes, i)
+			}
+		}
+
+		segments = append(segments, s)
+	}
+
+	return segments, nil
+}
+
+func query(value interface{}, segments []segment, location string) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	s := segments[0]
+	if s.field != "" {
+		location = strings.TrimPrefix(location+"."+s.field, ".")
+
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not an object", location)
+		}
+
+		value, ok = m[s.field]
+		if !ok {
+			return nil, fmt.Errorf("%s not found", location)
+		}
+	}
+
+	return index(value, s.indexes, segments[1:], location)
+}
+
+func index(value interface{}, indexes []int, segments []segment, location string) (interface{}, error) {
+	if len(indexes) == 0 {
+		return query(value, segments, location)
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not a list", location)
+	}
+
+	if indexes[0] != wildcard {
+		i := indexes[0]
+		if i >= len(list) {
+			return nil, fmt.Errorf("%s has no item %d", location, i)
+		}
+
+		return index(list[i], indexes[1:], segments, fmt.Sprintf("%s[%d]", location, i))
+	}
+
+	results := []interface{}{}
+	for i, item := range list {
+		result, err := index(item, indexes[1:], segments, fmt.Sprintf("%s[%d]", location, i))
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspect
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestQuery(t *testing.T) {
+	config := map[string]interface{}{
+		"build": map[string]interface{}{
+			"artifacts": []interface{}{
+				map[string]interface{}{"image": "image1", "context": "app1"},
+				map[string]interface{}{"image": "image2"},
+			},
+		},
+		"deploy": map[string]interface{}{
+			"kubectl": map[string]interface{}{
+				"manifests": []interface{}{"k8s/*.yaml"},
+			},
+		},
+	}
+
+	tests := []struct {
+		description string
+		path        string
+		expected    interface{}
+		shouldErr   bool
+	}{
+		{
+			description: "field",
+			path:        "deploy.kubectl",
+			expected:    map[string]interface{}{"manifests": []interface{}{"k8s/*.yaml"}},
+		},
+		{
+			description: "index",
+			path:        "build.artifacts[1].image",
+			expected:    "image2",
+		},
+		{
+			description: "wildcard",
+			path:        "build.artifacts[*].image",
+			expected:    []interface{}{"image1", "image2"},
+		},
+		{
+			description: "last index",
+			path:        "deploy.kubectl.manifests[0]",
+			expected:    "k8s/*.yaml",
+		},
+		{
+			description: "missing field",
+			path:        "build.artifacts[*].context",
+			shouldErr:   true,
+		},
+		{
+			description: "index out of range",
+			path:        "build.artifacts[2]",
+			shouldErr:   true,
+		},
+		{
+			description: "not a list",
+			path:        "deploy[0]",
+			shouldErr:   true,
+		},
+		{
+			description: "invalid path",
+			path:        "build..artifacts",
+			shouldErr:   true,
+		},
+		{
+			description: "invalid index",
+			path:        "build.artifacts[first]",
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := Query(config, test.path)
+
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, result)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
@@ -38,8 +39,16 @@ func GetClientset() (kubernetes.Interface, error) {
 }
 
 func getClientConfig() (*restclient.Config, error) {
+	return getClientConfigForContext("")
+}
+
+// getClientConfigForContext returns the client config for a kube context,
+// or for the current context if kubeContext is empty.
+func getClientConfigForContext(kubeContext string) (*restclient.Config, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{
+		CurrentContext: kubeContext,
+	})
 	clientConfig, err := kubeConfig.ClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("error creating kubeConfig: %s", err)
@@ -54,3 +63,23 @@ func GetDynamicClient() (dynamic.Interface, error) {
 	}
 	return dynamic.NewForConfig(config)
 }
+
+// GetDynamicClientForContext returns a dynamic client and a discovery client for a kube context.
+func GetDynamicClientForContext(kubeContext string) (dynamic.Interface, discovery.DiscoveryInterface, error) {
+	config, err := getClientConfigForContext(kubeContext)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "getting client config for dynamic client")
+	}
+
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating dynamic client")
+	}
+
+	disco, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating discovery client")
+	}
+
+	return client, disco, nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxNamespaceLength is the maximum length of a namespace name, and of a label value.
+const maxNamespaceLength = 63
+
+var (
+	invalidNamespaceChars = regexp.MustCompile("[^a-z0-9-]+")
+	invalidLabelChars     = regexp.MustCompile("[^a-zA-Z0-9_.-]+")
+)
+
+// EphemeralNamespaceName returns a valid namespace name for a session of a user.
+func EphemeralNamespaceName(user, runID string) string {
+	user = strings.Trim(invalidNamespaceChars.ReplaceAllString(strings.ToLower(user), "-"), "-")
+	if user == "" {
+		user = "unknown"
+	}
+
+	maxUserLength := maxNamespaceLength - len("skaffold--") - len(runID)
+	if len(user) > maxUserLength {
+		user = strings.TrimRight(user[:maxUserLength], "-")
+	}
+
+	return fmt.Sprintf("skaffold-%s-%s", user, runID)
+}
+
+// CreateEphemeralNamespace creates a namespace for a session of a user.
+// The namespace is annotated with a ttl, after which it can be reaped.
+func CreateEphemeralNamespace(name, user, runID string, ttl time.Duration) error {
+	client, err := Client()
+	if err != nil {
+		return errors.Wrap(err, "getting Kubernetes client")
+	}
+
+	_, err = client.CoreV1().Namespaces().Create(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				constants.Labels.EphemeralNamespace: "true",
+				constants.Labels.User:               labelValue(user),
+				constants.Labels.RunID:              runID,
+			},
+			Annotations: map[string]string{
+				constants.Annotations.TTL: ttl.String(),
+			},
+		},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "creating namespace %s", name)
+	}
+
+	return nil
+}
+
+// labelValue turns any string into a valid label value.
+func labelValue(value string) string {
+	value = invalidLabelChars.ReplaceAllString(value, "_")
+	if len(value) > maxNamespaceLength {
+		value = value[:maxNamespaceLength]
+	}
+	return strings.Trim(value, "_.-")
+}
+
+// DeleteNamespace deletes a namespace and everything it contains.
+func DeleteNamespace(name string) error {
+	client, err := Client()
+	if err != nil {
+		return errors.Wrap(err, "getting Kubernetes client")
+	}
+
+	if err := client.CoreV1().Namespaces().Delete(name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "deleting namespace %s", name)
+	}
+
+	return nil
+}
+
+// ReapEphemeralNamespaces deletes the ephemeral namespaces that outlived their ttl.
+func ReapEphemeralNamespaces(out io.Writer, now time.Time) error {
+	client, err := Client()
+	if err != nil {
+		return errors.Wrap(err, "getting Kubernetes client")
+	}
+
+	list, err := client.CoreV1().Namespaces().List(metav1.ListOptions{
+		LabelSelector: constants.Labels.EphemeralNamespace + "=true",
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing namespaces")
+	}
+
+	var failures []string
+	for _, ns := range list.Items {
+		if ns.Status.Phase == v1.NamespaceTerminating {
+			continue
+		}
+
+		ttl, err := time.ParseDuration(ns.Annotations[constants.Annotations.TTL])
+		if err != nil {
+			logrus.Debugf("Ignoring namespace %s with an invalid ttl: %v", ns.Name, err)
+			continue
+		}
+
+		if ns.CreationTimestamp.Add(ttl).After(now) {
+			continue
+		}
+
+		// Keep going, so that a single failure doesn't prevent
+		// the other namespaces from being deleted.
+		if err := DeleteNamespace(ns.Name); err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		color.Default.Fprintln(out, "Deleted expired namespace", ns.Name)
+	}
+
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
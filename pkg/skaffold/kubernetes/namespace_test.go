@@ -0,0 +1,164 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestEphemeralNamespaceName(t *testing.T) {
+	tests := []struct {
+		description string
+		user        string
+		expected    string
+	}{
+		{
+			description: "simple user",
+			user:        "alice",
+			expected:    "skaffold-alice-1a2b3c4d",
+		},
+		{
+			description: "domain user",
+			user:        `CORP\Bob.Smith`,
+			expected:    "skaffold-corp-bob-smith-1a2b3c4d",
+		},
+		{
+			description: "unknown user",
+			user:        "",
+			expected:    "skaffold-unknown-1a2b3c4d",
+		},
+		{
+			description: "long user",
+			user:        strings.Repeat("a", 100),
+			expected:    "skaffold-" + strings.Repeat("a", 45) + "-1a2b3c4d",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			name := EphemeralNamespaceName(test.user, "1a2b3c4d")
+
+			t.CheckDeepEqual(test.expected, name)
+		})
+	}
+}
+
+func TestCreateEphemeralNamespace(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		client := fake.NewSimpleClientset()
+		t.Override(&Client, func() (kubernetes.Interface, error) { return client, nil })
+
+		err := CreateEphemeralNamespace("skaffold-alice-1a2b3c4d", `CORP\alice`, "1a2b3c4d", time.Hour)
+		t.CheckError(false, err)
+
+		ns, err := client.CoreV1().Namespaces().Get("skaffold-alice-1a2b3c4d", metav1.GetOptions{})
+		t.CheckError(false, err)
+		t.CheckDeepEqual(map[string]string{
+			"skaffold.dev/ephemeral-namespace": "true",
+			"skaffold.dev/user":                "CORP_alice",
+			"skaffold.dev/run-id":              "1a2b3c4d",
+		}, ns.Labels)
+		t.CheckDeepEqual(map[string]string{"skaffold.dev/ttl": "1h0m0s"}, ns.Annotations)
+
+		err = DeleteNamespace("skaffold-alice-1a2b3c4d")
+		t.CheckError(false, err)
+
+		list, _ := client.CoreV1().Namespaces().List(metav1.ListOptions{})
+		t.CheckDeepEqual(0, len(list.Items))
+	})
+}
+
+func TestReapEphemeralNamespaces(t *testing.T) {
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	namespace := func(name string, ephemeral bool, created time.Time, ttl string) *v1.Namespace {
+		ns := &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created),
+				Annotations:       map[string]string{"skaffold.dev/ttl": ttl},
+			},
+		}
+		if ephemeral {
+			ns.Labels = map[string]string{"skaffold.dev/ephemeral-namespace": "true"}
+		}
+		return ns
+	}
+
+	testutil.Run(t, "", func(t *testutil.T) {
+		client := fake.NewSimpleClientset(
+			namespace("expired", true, now.Add(-2*time.Hour), "1h"),
+			namespace("active", true, now.Add(-30*time.Minute), "1h"),
+			namespace("invalid-ttl", true, now.Add(-2*time.Hour), "forever"),
+			namespace("not-ephemeral", false, now.Add(-2*time.Hour), "1h"),
+		)
+		t.Override(&Client, func() (kubernetes.Interface, error) { return client, nil })
+
+		var out bytes.Buffer
+		err := ReapEphemeralNamespaces(&out, now)
+		t.CheckError(false, err)
+
+		list, _ := client.CoreV1().Namespaces().List(metav1.ListOptions{})
+		var names []string
+		for _, ns := range list.Items {
+			names = append(names, ns.Name)
+		}
+		t.CheckDeepEqual([]string{"active", "invalid-ttl", "not-ephemeral"}, names)
+		t.CheckDeepEqual("Deleted expired namespace expired\n", out.String())
+	})
+}
+
+func TestReapEphemeralNamespacesKeepsGoing(t *testing.T) {
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	namespace := func(name string) *v1.Namespace {
+		return &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+				Annotations:       map[string]string{"skaffold.dev/ttl": "1h"},
+				Labels:            map[string]string{"skaffold.dev/ephemeral-namespace": "true"},
+			},
+		}
+	}
+
+	testutil.Run(t, "", func(t *testutil.T) {
+		client := fake.NewSimpleClientset(namespace("first"), namespace("second"))
+		client.PrependReactor("delete", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.(k8stesting.DeleteAction).GetName() == "first" {
+				return true, nil, errors.New("forbidden")
+			}
+			return false, nil, nil
+		})
+		t.Override(&Client, func() (kubernetes.Interface, error) { return client, nil })
+
+		var out bytes.Buffer
+		err := ReapEphemeralNamespaces(&out, now)
+
+		t.CheckErrorContains("deleting namespace first: forbidden", err)
+		t.CheckDeepEqual("Deleted expired namespace second\n", out.String())
+	})
+}
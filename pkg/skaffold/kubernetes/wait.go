@@ -25,11 +25,14 @@ import (
 	"github.com/golang/glog"
 	"github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -177,3 +180,106 @@ func WaitForDeploymentToStabilize(ctx context.Context, c kubernetes.Interface, n
 		return false, nil
 	})
 }
+
+// WaitForJobToComplete waits until the Job has either succeeded or failed.
+func WaitForJobToComplete(ctx context.Context, c kubernetes.Interface, ns, name string, timeout time.Duration) error {
+	logrus.Infof("Waiting for job %s to complete", name)
+
+	fields := fields.Set{
+		"metadata.name":      name,
+		"metadata.namespace": ns,
+	}
+	w, err := c.BatchV1().Jobs(ns).Watch(meta_v1.ListOptions{
+		FieldSelector: fields.AsSelector().String(),
+	})
+	if err != nil {
+		return fmt.Errorf("initializing job watcher: %s", err)
+	}
+	defer w.Stop()
+
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	defer cancelTimeout()
+
+	return watchUntil(ctx, w, func(event *watch.Event) (bool, error) {
+		if event.Type == watch.Deleted {
+			return false, apierrs.NewNotFound(schema.GroupResource{Resource: "jobs"}, name)
+		}
+
+		job, ok := event.Object.(*batchv1.Job)
+		if !ok || job.Name != name {
+			return false, nil
+		}
+
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != v1.ConditionTrue {
+				continue
+			}
+
+			switch condition.Type {
+			case batchv1.JobComplete:
+				return true, nil
+			case batchv1.JobFailed:
+				return false, fmt.Errorf("job %s failed: %s", name, condition.Message)
+			}
+		}
+		return false, nil
+	})
+}
+
+// WaitForWorkloadReady waits until a Deployment, a StatefulSet or a DaemonSet is rolled out
+// and all its replicas are ready. Other kinds of objects don't need to be waited for.
+func WaitForWorkloadReady(ctx context.Context, c kubernetes.Interface, ns string, obj runtime.Object, timeout time.Duration) error {
+	var name string
+	var ready func() (bool, error)
+
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		name = "deployment/" + o.Name
+		ready = func() (bool, error) {
+			dp, err := c.AppsV1().Deployments(ns).Get(o.Name, meta_v1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			replicas := replicasOrDefault(dp.Spec.Replicas)
+			return dp.Generation <= dp.Status.ObservedGeneration && dp.Status.UpdatedReplicas == replicas && dp.Status.AvailableReplicas == replicas, nil
+		}
+	case *appsv1.StatefulSet:
+		name = "statefulset/" + o.Name
+		ready = func() (bool, error) {
+			ss, err := c.AppsV1().StatefulSets(ns).Get(o.Name, meta_v1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			replicas := replicasOrDefault(ss.Spec.Replicas)
+			return ss.Generation <= ss.Status.ObservedGeneration && ss.Status.UpdatedReplicas == replicas && ss.Status.ReadyReplicas == replicas, nil
+		}
+	case *appsv1.DaemonSet:
+		name = "daemonset/" + o.Name
+		ready = func() (bool, error) {
+			ds, err := c.AppsV1().DaemonSets(ns).Get(o.Name, meta_v1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return ds.Generation <= ds.Status.ObservedGeneration && ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled && ds.Status.NumberReady == ds.Status.DesiredNumberScheduled, nil
+		}
+	default:
+		return nil
+	}
+
+	logrus.Infof("Waiting for %s to be ready", name)
+
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	defer cancelTimeout()
+
+	if err := wait.PollImmediateUntil(time.Second, ready, ctx.Done()); err != nil {
+		return fmt.Errorf("waiting for %s to be ready: %s", name, err)
+	}
+	return nil
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
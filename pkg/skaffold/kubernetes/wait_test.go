@@ -0,0 +1,78 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWaitForWorkloadReady(t *testing.T) {
+	deployment := func(available int32) *appsv1.Deployment {
+		replicas := int32(2)
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns", Generation: 1},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 1,
+				UpdatedReplicas:    2,
+				AvailableReplicas:  available,
+			},
+		}
+	}
+
+	tests := []struct {
+		description string
+		existing    runtime.Object
+		obj         runtime.Object
+		shouldErr   bool
+	}{
+		{
+			description: "ready deployment",
+			existing:    deployment(2),
+			obj:         deployment(0),
+		},
+		{
+			description: "deployment not ready",
+			existing:    deployment(1),
+			obj:         deployment(0),
+			shouldErr:   true,
+		},
+		{
+			description: "other objects are not waited for",
+			existing:    deployment(1),
+			obj:         &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns"}},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			client := fake.NewSimpleClientset(test.existing)
+
+			err := WaitForWorkloadReady(context.Background(), client, "ns", test.obj, 10*time.Millisecond)
+
+			t.CheckError(test.shouldErr, err)
+		})
+	}
+}
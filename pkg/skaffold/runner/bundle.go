@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/bundle"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+)
+
+var (
+	// For testing
+	createBundle = bundle.Create
+	loadBundle   = bundle.Load
+)
+
+// CreateBundle builds all the artifacts and saves them, with the effective
+// configuration and the deployment files, to a bundle directory.
+func (r *SkaffoldRunner) CreateBundle(ctx context.Context, out io.Writer, dir string, cfg *latest.SkaffoldConfig) error {
+	if r.runCtx.DefaultRepo != "" {
+		return errors.New("the default repo should be given to `skaffold bundle deploy`, not to `skaffold bundle create`")
+	}
+
+	builds, err := r.BuildAndTest(ctx, out, cfg.Build.Artifacts)
+	if err != nil {
+		return err
+	}
+
+	files, err := r.Dependencies()
+	if err != nil {
+		return errors.Wrap(err, "listing deployment files")
+	}
+
+	// Remote charts are saved in the bundle.
+	if helm, ok := deployerNamed(r.deployers, "helm").(*deploy.HelmDeployer); ok {
+		charts, err := helm.FetchRemoteCharts(ctx, out, filepath.Join(bundle.ProjectDir(dir), bundle.ChartsDir))
+		if err != nil {
+			return errors.Wrap(err, "saving remote charts")
+		}
+
+		if cfg, err = bundle.WithLocalCharts(cfg, dir, charts); err != nil {
+			return err
+		}
+	}
+
+	return createBundle(ctx, out, dir, cfg, build.WithMetadata(builds, cfg.Build), files, r.runCtx.WorkingDir, r.runCtx.InsecureRegistries)
+}
+
+// DeployBundle makes the images of a bundle available to the cluster and deploys them.
+func (r *SkaffoldRunner) DeployBundle(ctx context.Context, out io.Writer, dir string) error {
+	builds, err := loadBundle(ctx, out, dir, r.runCtx.DefaultRepo, r.runCtx.InsecureRegistries)
+	if err != nil {
+		return errors.Wrap(err, "loading bundle")
+	}
+
+	return r.Deploy(ctx, out, builds)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestCreateBundle(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	testutil.Run(t, "", func(t *testutil.T) {
+		var bundled []build.Artifact
+		t.Override(&createBundle, func(_ context.Context, _ io.Writer, _ string, _ *latest.SkaffoldConfig, builds []build.Artifact, _ []string, _ string, _ map[string]bool) error {
+			bundled = builds
+			return nil
+		})
+
+		testBench := &TestBench{}
+		runner := createRunner(t.T, testBench)
+		cfg := &latest.SkaffoldConfig{}
+		cfg.Build.Artifacts = []*latest.Artifact{{ImageName: "img1"}, {ImageName: "img2"}}
+
+		err := runner.CreateBundle(context.Background(), ioutil.Discard, "bundle", cfg)
+
+		t.CheckErrorAndDeepEqual(false, err, []Actions{{
+			Built:  []string{"img1:1", "img2:1"},
+			Tested: []string{"img1:1", "img2:1"},
+		}}, testBench.Actions())
+		t.CheckDeepEqual(2, len(bundled))
+	})
+}
+
+func TestCreateBundleWithDefaultRepo(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	testutil.Run(t, "", func(t *testutil.T) {
+		testBench := &TestBench{}
+		runner := createRunner(t.T, testBench)
+		runner.runCtx.DefaultRepo = "registry.local"
+
+		err := runner.CreateBundle(context.Background(), ioutil.Discard, "bundle", &latest.SkaffoldConfig{})
+
+		t.CheckErrorContains("should be given to `skaffold bundle deploy`", err)
+		t.CheckDeepEqual([]Actions{{}}, testBench.Actions())
+	})
+}
+
+func TestDeployBundle(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	testutil.Run(t, "", func(t *testutil.T) {
+		var defaultRepo string
+		t.Override(&loadBundle, func(_ context.Context, _ io.Writer, _, repo string, _ map[string]bool) ([]build.Artifact, error) {
+			defaultRepo = repo
+			return []build.Artifact{{ImageName: "registry.local/img1", Tag: "registry.local/img1:v1"}}, nil
+		})
+
+		testBench := &TestBench{}
+		runner := createRunner(t.T, testBench)
+		runner.runCtx.DefaultRepo = "registry.local"
+
+		err := runner.DeployBundle(context.Background(), ioutil.Discard, "bundle")
+
+		t.CheckErrorAndDeepEqual(false, err, []Actions{{
+			Deployed: []string{"registry.local/img1:v1"},
+		}}, testBench.Actions())
+		t.CheckDeepEqual("registry.local", defaultRepo)
+	})
+}
@@ -17,12 +17,18 @@ limitations under the License.
 package runner
 
 import (
+	gosync "sync"
+
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
 )
 
 type changes struct {
+	// lock guards the changes recorded by the watcher, since
+	// they can be applied from another goroutine, on resume.
+	lock gosync.Mutex
+
 	dirtyArtifacts []*artifactChange
 	needsRebuild   []*latest.Artifact
 	needsResync    []*sync.Item
@@ -36,9 +42,26 @@ type artifactChange struct {
 }
 
 func (c *changes) AddDirtyArtifact(a *latest.Artifact, e watch.Events) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	c.dirtyArtifacts = append(c.dirtyArtifacts, &artifactChange{artifact: a, events: e})
 }
 
+func (c *changes) AddRedeploy() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.needsRedeploy = true
+}
+
+func (c *changes) AddReload() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.needsReload = true
+}
+
 func (c *changes) AddRebuild(a *latest.Artifact) {
 	c.needsRebuild = append(c.needsRebuild, a)
 }
@@ -47,11 +70,22 @@ func (c *changes) AddResync(s *sync.Item) {
 	c.needsResync = append(c.needsResync, s)
 }
 
-func (c *changes) reset() {
+// take returns the changes recorded so far and resets them.
+func (c *changes) take() *changes {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	taken := &changes{
+		dirtyArtifacts: c.dirtyArtifacts,
+		needsRedeploy:  c.needsRedeploy,
+		needsReload:    c.needsReload,
+	}
+
 	c.dirtyArtifacts = nil
 	c.needsRebuild = nil
 	c.needsResync = nil
-
 	c.needsRedeploy = false
 	c.needsReload = false
+
+	return taken
 }
@@ -19,6 +19,8 @@ package runner
 import (
 	"context"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
@@ -35,18 +37,44 @@ var ErrorConfigurationChanged = errors.New("configuration changed")
 // Dev watches for changes and runs the skaffold build and deploy
 // config until interrupted by the user.
 func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*latest.Artifact) error {
+	interactive := r.runCtx.Opts.Interactive
+	if interactive && strings.ToLower(r.runCtx.Opts.Trigger) == "manual" {
+		return errors.New("interactive mode can't be used with the manual trigger")
+	}
+
+	ctx, quit := context.WithCancel(ctx)
+	defer quit()
+
 	logger := r.newLogger(out, artifacts)
 	defer logger.Stop()
 
 	portForwarder := kubernetes.NewPortForwarder(out, r.imageList, r.runCtx.Namespaces)
 	defer portForwarder.Stop()
 
+	session := &devSession{
+		out:         out,
+		logger:      logger,
+		interactive: interactive,
+		artifacts:   artifacts,
+	}
+
 	// Create watcher and register artifacts to build current state of files.
-	changed := changes{}
+	changed := &changes{}
+	var watchArtifact func(*latest.Artifact) error
 	onChange := func() error {
-		defer changed.reset()
+		// Changes made while file watching is paused are applied on resume.
+		if session.isPaused() {
+			logrus.Debugln("Queueing changes while file watching is paused")
+			return nil
+		}
+
+		changed := changed.take()
 
-		logger.Mute()
+		session.lock.Lock()
+		defer session.lock.Unlock()
+
+		start := time.Now()
+		session.muteLogs()
 
 		for _, a := range changed.dirtyArtifacts {
 			s, err := sync.NewItem(a.artifact, a.events, r.builds, r.runCtx.InsecureRegistries)
@@ -62,59 +90,105 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 
 		switch {
 		case changed.needsReload:
-			return ErrorConfigurationChanged
+			added, err := r.reload(ctx, out)
+			if err != nil {
+				return err
+			}
+			session.artifacts = r.runCtx.Cfg.Build.Artifacts
+			for _, artifact := range added {
+				if err := watchArtifact(artifact); err != nil {
+					return err
+				}
+			}
 		case len(changed.needsResync) > 0:
 			for _, s := range changed.needsResync {
 				color.Default.Fprintf(out, "Syncing %d files for %s\n", len(s.Copy)+len(s.Delete), s.Image)
 
 				if err := r.Syncer.Sync(ctx, s); err != nil {
 					logrus.Warnln("Skipping deploy due to sync error:", err)
+					session.report(start, err)
 					return nil
 				}
 			}
 		case len(changed.needsRebuild) > 0:
 			if err := r.buildTestDeploy(ctx, out, changed.needsRebuild); err != nil {
 				logrus.Warnln("Skipping deploy due to error:", err)
+				session.report(start, err)
 				return nil
 			}
 		case changed.needsRedeploy:
 			if err := r.Deploy(ctx, out, r.builds); err != nil {
 				logrus.Warnln("Skipping deploy due to error:", err)
+				session.report(start, err)
 				return nil
 			}
 		}
 
-		logger.Unmute()
+		session.unmuteLogs()
+		session.report(start, nil)
 		return nil
 	}
 
-	// Watch artifacts
-	for i := range artifacts {
-		artifact := artifacts[i]
-		if !r.runCtx.Opts.IsTargetImage(artifact) {
-			continue
+	ignorer, err := watchIgnorer(r.runCtx.WorkingDir, r.runCtx.Cfg.Watch)
+	if err != nil {
+		return errors.Wrap(err, "reading watch configuration")
+	}
+
+	// Artifacts are looked up by name, so that changes to the
+	// configuration apply to the artifacts that are already watched.
+	watched := map[string]bool{}
+	watchArtifact = func(artifact *latest.Artifact) error {
+		imageName := artifact.ImageName
+		if watched[imageName] || !r.runCtx.Opts.IsTargetImage(artifact) {
+			return nil
+		}
+		watched[imageName] = true
+
+		artifactIgnorer, err := watchArtifactIgnorer(ignorer, r.runCtx.Cfg.Watch, artifact)
+		if err != nil {
+			return errors.Wrapf(err, "reading watch configuration for artifact %s", imageName)
 		}
 
 		if err := r.Watcher.Register(
-			func() ([]string, error) { return r.Builder.DependenciesForArtifact(ctx, artifact) },
-			func(e watch.Events) { changed.AddDirtyArtifact(artifact, e) },
+			func() ([]string, error) {
+				if a := session.artifact(imageName); a != nil {
+					return r.Builder.DependenciesForArtifact(ctx, a)
+				}
+				return nil, nil
+			},
+			artifactIgnorer,
+			func(e watch.Events) {
+				if a := session.artifact(imageName); a != nil {
+					changed.AddDirtyArtifact(a, e)
+				}
+			},
 		); err != nil {
-			return errors.Wrapf(err, "watching files for artifact %s", artifact.ImageName)
+			return errors.Wrapf(err, "watching files for artifact %s", imageName)
+		}
+		return nil
+	}
+
+	// Watch artifacts
+	for _, artifact := range artifacts {
+		if err := watchArtifact(artifact); err != nil {
+			return err
 		}
 	}
 
 	// Watch test configuration
 	if err := r.Watcher.Register(
-		r.TestDependencies,
-		func(watch.Events) { changed.needsRedeploy = true },
+		func() ([]string, error) { return r.TestDependencies() },
+		ignorer,
+		func(watch.Events) { changed.AddRedeploy() },
 	); err != nil {
 		return errors.Wrap(err, "watching test files")
 	}
 
 	// Watch deployment configuration
 	if err := r.Watcher.Register(
-		r.Dependencies,
-		func(watch.Events) { changed.needsRedeploy = true },
+		func() ([]string, error) { return r.Dependencies() },
+		ignorer,
+		func(watch.Events) { changed.AddRedeploy() },
 	); err != nil {
 		return errors.Wrap(err, "watching files for deployer")
 	}
@@ -122,13 +196,14 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 	// Watch Skaffold configuration
 	if err := r.Watcher.Register(
 		func() ([]string, error) { return []string{r.runCtx.Opts.ConfigurationFile}, nil },
-		func(watch.Events) { changed.needsReload = true },
+		nil,
+		func(watch.Events) { changed.AddReload() },
 	); err != nil {
 		return errors.Wrapf(err, "watching skaffold configuration %s", r.runCtx.Opts.ConfigurationFile)
 	}
 
 	// First run
-	if err := r.buildTestDeploy(ctx, out, artifacts); err != nil {
+	if err := r.firstRun(ctx, out, artifacts); err != nil {
 		return errors.Wrap(err, "exiting dev mode because first run failed")
 	}
 
@@ -145,5 +220,85 @@ func (r *SkaffoldRunner) Dev(ctx context.Context, out io.Writer, artifacts []*la
 		}
 	}
 
-	return r.Watcher.Run(ctx, out, onChange)
+	// An error applying the changes queued during a pause ends the session,
+	// like it would if it happened while watching.
+	resumeErr := make(chan error, 1)
+	session.resume = func() {
+		if err := onChange(); err != nil {
+			select {
+			case resumeErr <- err:
+			default:
+			}
+			quit()
+		}
+	}
+
+	if interactive {
+		if restore, ok := singleKeyMode(stdin); ok {
+			defer restore()
+			session.singleKey = true
+		}
+		session.printHelp()
+		go r.readCommands(ctx, keys.read(stdin), session, quit)
+	}
+
+	if err := r.Watcher.Run(ctx, out, onChange); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-resumeErr:
+		return err
+	default:
+		return nil
+	}
+}
+
+// firstRun builds, tests and deploys the artifacts. With --resume, the
+// artifacts that didn't change since the previous session are not rebuilt.
+func (r *SkaffoldRunner) firstRun(ctx context.Context, out io.Writer, artifacts []*latest.Artifact) error {
+	if !r.runCtx.Opts.Resume {
+		return r.buildTestDeploy(ctx, out, artifacts)
+	}
+
+	needToBuild := r.resume(ctx, out, artifacts)
+	if len(needToBuild) > 0 {
+		return r.buildTestDeploy(ctx, out, needToBuild)
+	}
+
+	if err := r.deploy(ctx, out, r.builds); err != nil {
+		return errors.Wrap(err, "deploy failed")
+	}
+	return nil
+}
+
+// watchIgnorer builds the Ignorer shared by every watched component.
+func watchIgnorer(workingDir string, cfg latest.WatchConfig) (watch.Ignorer, error) {
+	ignorer, err := watch.Ignorer{}.WithPatterns(workingDir, cfg.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.GitIgnore {
+		return ignorer.WithGitIgnore(workingDir)
+	}
+	return ignorer, nil
+}
+
+// watchArtifactIgnorer adds the artifact's own watch section to the shared Ignorer.
+func watchArtifactIgnorer(ignorer watch.Ignorer, cfg latest.WatchConfig, artifact *latest.Artifact) (watch.Ignorer, error) {
+	gitIgnore := cfg.GitIgnore
+
+	if artifact.Watch != nil {
+		var err error
+		if ignorer, err = ignorer.WithPatterns(artifact.Workspace, artifact.Watch.Ignore); err != nil {
+			return nil, err
+		}
+		gitIgnore = gitIgnore || artifact.Watch.GitIgnore
+	}
+
+	if gitIgnore {
+		return ignorer.WithGitIgnore(artifact.Workspace)
+	}
+	return ignorer, nil
 }
@@ -32,7 +32,7 @@ import (
 
 type NoopWatcher struct{}
 
-func (t *NoopWatcher) Register(func() ([]string, error), func(watch.Events)) error {
+func (t *NoopWatcher) Register(func() ([]string, error), watch.Ignorer, func(watch.Events)) error {
 	return nil
 }
 
@@ -42,7 +42,7 @@ func (t *NoopWatcher) Run(context.Context, io.Writer, func() error) error {
 
 type FailWatcher struct{}
 
-func (t *FailWatcher) Register(func() ([]string, error), func(watch.Events)) error {
+func (t *FailWatcher) Register(func() ([]string, error), watch.Ignorer, func(watch.Events)) error {
 	return nil
 }
 
@@ -56,7 +56,7 @@ type TestWatcher struct {
 	testBench *TestBench
 }
 
-func (t *TestWatcher) Register(deps func() ([]string, error), onChange func(watch.Events)) error {
+func (t *TestWatcher) Register(deps func() ([]string, error), _ watch.Ignorer, onChange func(watch.Events)) error {
 	t.callbacks = append(t.callbacks, onChange)
 	return nil
 }
@@ -73,6 +73,8 @@ func (t *TestWatcher) Run(ctx context.Context, out io.Writer, onChange func() er
 				t.callbacks[1](evt) // 2nd artifact changed
 			case "manifest.yaml":
 				t.callbacks[3](evt) // deployment configuration changed
+			case "skaffold.yaml":
+				t.callbacks[4](evt) // skaffold configuration changed
 			}
 		}
 
@@ -354,3 +356,58 @@ func TestDevSync(t *testing.T) {
 		})
 	}
 }
+
+func TestWatchArtifactIgnorer(t *testing.T) {
+	var tests = []struct {
+		description string
+		global      latest.WatchConfig
+		artifact    *latest.WatchConfig
+		ignored     []string
+		watched     []string
+	}{
+		{
+			description: "no ignore",
+			watched:     []string{"app/main.go", "app/target/file"},
+		},
+		{
+			description: "global patterns are relative to the working dir",
+			global:      latest.WatchConfig{Ignore: []string{"app/target"}},
+			ignored:     []string{"app/target/file"},
+			watched:     []string{"app/main.go"},
+		},
+		{
+			description: "artifact patterns are relative to the context",
+			global:      latest.WatchConfig{Ignore: []string{"**/*.log"}},
+			artifact:    &latest.WatchConfig{Ignore: []string{"target"}},
+			ignored:     []string{"app/target/file", "app/debug.log"},
+			watched:     []string{"app/main.go", "target/file"},
+		},
+		{
+			description: "gitignore of the context",
+			artifact:    &latest.WatchConfig{GitIgnore: true},
+			ignored:     []string{"app/generated/file.go"},
+			watched:     []string{"app/main.go"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir().
+				Write("app/.gitignore", "generated/")
+
+			ignorer, err := watchIgnorer(tmpDir.Root(), test.global)
+			t.CheckError(false, err)
+			ignorer, err = watchArtifactIgnorer(ignorer, test.global, &latest.Artifact{
+				Workspace: tmpDir.Path("app"),
+				Watch:     test.artifact,
+			})
+			t.CheckError(false, err)
+
+			for _, path := range test.ignored {
+				t.CheckDeepEqual(true, ignorer.Ignored(tmpDir.Path(path)))
+			}
+			for _, path := range test.watched {
+				t.CheckDeepEqual(false, ignorer.Ignored(tmpDir.Path(path)))
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	pkgsync "github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// stdin is where interactive commands are read from.
+	stdin io.Reader = os.Stdin
+
+	// keys is the single reader of stdin, shared by the dev sessions.
+	keys = &keyReader{}
+)
+
+// keyReader reads the interactive commands, one key at a time. There's a
+// single reader per process, shared by the sessions that follow each other
+// when the configuration changes, so that no key is lost in between.
+type keyReader struct {
+	once sync.Once
+	keys chan string
+}
+
+// read starts reading keys, the first time it's called.
+func (k *keyReader) read(in io.Reader) <-chan string {
+	k.once.Do(func() {
+		k.keys = make(chan string)
+
+		go func() {
+			reader := bufio.NewReader(in)
+			for {
+				key, _, err := reader.ReadRune()
+				if err != nil {
+					if err != io.EOF {
+						logrus.Debugf("interactive mode error: %s", err)
+					}
+					return
+				}
+				if key == '\n' || key == '\r' {
+					continue
+				}
+				k.keys <- string(key)
+			}
+		}()
+	})
+
+	return k.keys
+}
+
+// devSession holds the state shared by the dev loop and the interactive commands.
+type devSession struct {
+	out         io.Writer
+	logger      *kubernetes.LogAggregator
+	interactive bool
+
+	// lock makes sure that only one iteration runs at a time.
+	// It also guards the artifacts, that change when the configuration is reloaded.
+	lock      sync.Mutex
+	artifacts []*latest.Artifact
+	paused    int32
+	muted     int32
+
+	// singleKey tells if keys are read as soon as they're pressed.
+	singleKey bool
+
+	// resume applies the changes queued while file watching was paused.
+	resume func()
+}
+
+// artifact finds a current artifact by its image name.
+func (s *devSession) artifact(imageName string) *latest.Artifact {
+	for _, a := range s.artifacts {
+		if a.ImageName == imageName {
+			return a
+		}
+	}
+	return nil
+}
+
+// isPaused tells if file changes should be ignored.
+func (s *devSession) isPaused() bool {
+	return atomic.LoadInt32(&s.paused) == 1
+}
+
+// muteLogs mutes the logs during an iteration.
+func (s *devSession) muteLogs() {
+	s.logger.Mute()
+}
+
+// unmuteLogs unmutes the logs after an iteration, unless the user muted them.
+func (s *devSession) unmuteLogs() {
+	if atomic.LoadInt32(&s.muted) == 0 {
+		s.logger.Unmute()
+	}
+}
+
+// report shows the result and timing of the last iteration.
+func (s *devSession) report(start time.Time, err error) {
+	if !s.interactive {
+		return
+	}
+
+	result := "succeeded"
+	if err != nil {
+		result = "failed: " + err.Error()
+	}
+	color.Default.Fprintf(s.out, "[%s] Last iteration %s in %v\n", start.Format("15:04:05"), result, time.Since(start).Round(time.Millisecond))
+}
+
+// printHelp lists the interactive commands.
+func (s *devSession) printHelp() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.singleKey {
+		color.Yellow.Fprintln(s.out, "Interactive mode. Press a key:")
+	} else {
+		color.Yellow.Fprintln(s.out, "Interactive mode. Type a key and press enter:")
+	}
+	color.Default.Fprintln(s.out, "  b  rebuild all the artifacts")
+	for i, artifact := range s.artifacts {
+		if i >= 9 {
+			break
+		}
+		color.Default.Fprintf(s.out, "  %d  rebuild %s\n", i+1, artifact.ImageName)
+	}
+	color.Default.Fprintln(s.out, "  d  redeploy")
+	color.Default.Fprintln(s.out, "  s  sync all the files")
+	color.Default.Fprintln(s.out, "  l  mute/unmute the logs")
+	color.Default.Fprintln(s.out, "  p  pause/resume file watching")
+	color.Default.Fprintln(s.out, "  q  quit")
+}
+
+// readCommands runs the commands bound to the keys that are pressed,
+// until the context is cancelled.
+func (r *SkaffoldRunner) readCommands(ctx context.Context, commands <-chan string, session *devSession, quit func()) {
+	for {
+		select {
+		case command := <-commands:
+			r.runCommand(ctx, session, command, quit)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runCommand runs the action bound to an interactive command.
+func (r *SkaffoldRunner) runCommand(ctx context.Context, session *devSession, command string, quit func()) {
+	out := session.out
+
+	switch command = strings.TrimSpace(command); command {
+	case "":
+		return
+	case "b":
+		session.iterate(func() error { return r.buildTestDeploy(ctx, out, session.artifacts) })
+	case "d":
+		session.iterate(func() error { return r.Deploy(ctx, out, r.builds) })
+	case "s":
+		session.iterate(func() error { return r.syncAll(ctx, out, session.artifacts) })
+	case "l":
+		if atomic.CompareAndSwapInt32(&session.muted, 0, 1) {
+			session.logger.Mute()
+			color.Default.Fprintln(out, "Logs muted")
+		} else {
+			atomic.StoreInt32(&session.muted, 0)
+			session.logger.Unmute()
+			color.Default.Fprintln(out, "Logs unmuted")
+		}
+	case "p":
+		if atomic.CompareAndSwapInt32(&session.paused, 0, 1) {
+			color.Default.Fprintln(out, "File watching paused")
+		} else {
+			atomic.StoreInt32(&session.paused, 0)
+			color.Default.Fprintln(out, "File watching resumed")
+			if session.resume != nil {
+				session.resume()
+			}
+		}
+	case "q":
+		color.Default.Fprintln(out, "Quitting")
+		quit()
+	default:
+		if i, err := strconv.Atoi(command); err == nil {
+			session.iterate(func() error {
+				if i < 1 || i > len(session.artifacts) {
+					return fmt.Errorf("no artifact number %d", i)
+				}
+				return r.buildTestDeploy(ctx, out, []*latest.Artifact{session.artifacts[i-1]})
+			})
+			return
+		}
+
+		color.Red.Fprintf(out, "Unknown command: %s\n", command)
+		session.printHelp()
+	}
+}
+
+// iterate runs an iteration triggered by an interactive command.
+func (s *devSession) iterate(action func() error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	start := time.Now()
+	s.muteLogs()
+	err := action()
+	s.unmuteLogs()
+	s.report(start, err)
+}
+
+// syncAll syncs all the files that match the sync rules of the artifacts.
+func (r *SkaffoldRunner) syncAll(ctx context.Context, out io.Writer, artifacts []*latest.Artifact) error {
+	synced := false
+
+	for _, artifact := range artifacts {
+		if artifact.Sync == nil {
+			continue
+		}
+
+		files, err := r.Builder.DependenciesForArtifact(ctx, artifact)
+		if err != nil {
+			return err
+		}
+
+		s, err := pkgsync.NewFullItem(artifact, files, r.builds, r.runCtx.InsecureRegistries)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			continue
+		}
+
+		color.Default.Fprintf(out, "Syncing %d files for %s\n", len(s.Copy), s.Image)
+		if err := r.Syncer.Sync(ctx, s); err != nil {
+			return err
+		}
+		synced = true
+	}
+
+	if !synced {
+		color.Default.Fprintln(out, "No files to sync")
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// BlockingWatcher watches until the context is cancelled.
+type BlockingWatcher struct{}
+
+func (t *BlockingWatcher) Register(func() ([]string, error), watch.Ignorer, func(watch.Events)) error {
+	return nil
+}
+
+func (t *BlockingWatcher) Run(ctx context.Context, _ io.Writer, _ func() error) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestRunCommand(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	var tests = []struct {
+		description     string
+		command         string
+		expectedActions Actions
+		expectedOutput  string
+		expectedQuit    bool
+	}{
+		{
+			description: "rebuild all",
+			command:     "b",
+			expectedActions: Actions{
+				Built:    []string{"img1:1", "img2:1"},
+				Tested:   []string{"img1:1", "img2:1"},
+				Deployed: []string{"img1:1", "img2:1"},
+			},
+			expectedOutput: "Last iteration succeeded",
+		},
+		{
+			description: "rebuild one artifact",
+			command:     "2",
+			expectedActions: Actions{
+				Built:    []string{"img2:1"},
+				Tested:   []string{"img2:1"},
+				Deployed: []string{"img2:1", "img1:0"},
+			},
+			expectedOutput: "Last iteration succeeded",
+		},
+		{
+			description: "redeploy",
+			command:     "d",
+			expectedActions: Actions{
+				Deployed: []string{"img1:0", "img2:0"},
+			},
+			expectedOutput: "Last iteration succeeded",
+		},
+		{
+			description:    "sync without sync rules",
+			command:        "s",
+			expectedOutput: "No files to sync",
+		},
+		{
+			description:    "mute logs",
+			command:        "l",
+			expectedOutput: "Logs muted",
+		},
+		{
+			description:    "pause",
+			command:        "p",
+			expectedOutput: "File watching paused",
+		},
+		{
+			description:    "quit",
+			command:        "q",
+			expectedOutput: "Quitting",
+			expectedQuit:   true,
+		},
+		{
+			description:    "unknown command",
+			command:        "x",
+			expectedOutput: "Unknown command: x",
+		},
+		{
+			description:    "artifact out of range",
+			command:        "3",
+			expectedOutput: "Last iteration failed: no artifact number 3",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			testBench := &TestBench{}
+			runner := createRunner(t.T, testBench)
+			runner.builds = []build.Artifact{
+				{ImageName: "img1", Tag: "img1:0"},
+				{ImageName: "img2", Tag: "img2:0"},
+			}
+			artifacts := []*latest.Artifact{
+				{ImageName: "img1"},
+				{ImageName: "img2"},
+			}
+
+			var out bytes.Buffer
+			session := &devSession{
+				out:         &out,
+				logger:      runner.newLogger(ioutil.Discard, artifacts),
+				interactive: true,
+				artifacts:   artifacts,
+			}
+			quit := false
+
+			runner.runCommand(context.Background(), session, test.command, func() { quit = true })
+
+			t.CheckDeepEqual([]Actions{test.expectedActions}, testBench.Actions())
+			t.CheckContains(test.expectedOutput, out.String())
+			t.CheckDeepEqual(test.expectedQuit, quit)
+		})
+	}
+}
+
+func TestToggles(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	runner := createRunner(t, &TestBench{})
+	session := &devSession{
+		out:    ioutil.Discard,
+		logger: runner.newLogger(ioutil.Discard, nil),
+	}
+
+	runner.runCommand(context.Background(), session, "l", func() {})
+	runner.runCommand(context.Background(), session, "p", func() {})
+
+	// Iterations don't unmute the logs muted by the user.
+	session.iterate(func() error { return nil })
+	testutil.CheckDeepEqual(t, true, session.logger.IsMuted())
+	testutil.CheckDeepEqual(t, true, session.isPaused())
+
+	runner.runCommand(context.Background(), session, "l", func() {})
+	runner.runCommand(context.Background(), session, "p", func() {})
+
+	testutil.CheckDeepEqual(t, false, session.logger.IsMuted())
+	testutil.CheckDeepEqual(t, false, session.isPaused())
+}
+
+func TestDevInteractive(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&stdin, strings.NewReader("1\nq\n"))
+		t.Override(&keys, &keyReader{})
+
+		testBench := &TestBench{}
+		runner := createRunner(t.T, testBench)
+		runner.runCtx.Opts.Interactive = true
+		runner.Watcher = &BlockingWatcher{}
+
+		var out bytes.Buffer
+		err := runner.Dev(context.Background(), &out, []*latest.Artifact{
+			{ImageName: "img1"},
+			{ImageName: "img2"},
+		})
+
+		// The second build only rebuilds the first artifact.
+		t.CheckErrorAndDeepEqual(false, err, []Actions{{
+			Built:    []string{"img1:2"},
+			Tested:   []string{"img1:2"},
+			Deployed: []string{"img1:2", "img2:1"},
+		}}, testBench.Actions())
+		t.CheckContains("Quitting", out.String())
+	})
+}
+
+func TestDevInteractiveManualTrigger(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	runner := createRunner(t, &TestBench{})
+	runner.runCtx.Opts.Interactive = true
+	runner.runCtx.Opts.Trigger = "manual"
+
+	err := runner.Dev(context.Background(), ioutil.Discard, nil)
+
+	testutil.CheckErrorContains(t, "manual trigger", err)
+}
+
+func TestKeyReaderSharedAcrossSessions(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		reader := &keyReader{}
+		in := strings.NewReader("bd\nq")
+
+		// A session ends after reading its first key.
+		first := reader.read(in)
+		t.CheckDeepEqual("b", <-first)
+
+		// The next session reads from the same stdin, without losing keys.
+		second := reader.read(strings.NewReader("ignored"))
+		t.CheckDeepEqual("d", <-second)
+		t.CheckDeepEqual("q", <-second)
+	})
+}
+
+// waitingWriter lets a test wait for some output.
+type waitingWriter struct {
+	lock    sync.Mutex
+	out     bytes.Buffer
+	waiting string
+	found   chan bool
+}
+
+func (w *waitingWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	n, err := w.out.Write(p)
+	if w.waiting != "" && strings.Contains(w.out.String(), w.waiting) {
+		w.waiting = ""
+		w.found <- true
+	}
+	return n, err
+}
+
+func (w *waitingWriter) waitFor(text string, action func()) {
+	w.lock.Lock()
+	w.out.Reset()
+	w.waiting = text
+	w.lock.Unlock()
+
+	action()
+	<-w.found
+}
+
+// PausingWatcher reports a change to the first artifact while file watching is paused.
+type PausingWatcher struct {
+	keys      io.Writer
+	out       *waitingWriter
+	testBench *TestBench
+	callbacks []func(watch.Events)
+}
+
+func (w *PausingWatcher) Register(_ func() ([]string, error), _ watch.Ignorer, onChange func(watch.Events)) error {
+	w.callbacks = append(w.callbacks, onChange)
+	return nil
+}
+
+func (w *PausingWatcher) Run(ctx context.Context, _ io.Writer, onChange func() error) error {
+	press := func(key string) func() {
+		return func() { w.keys.Write([]byte(key)) }
+	}
+
+	w.out.waitFor("File watching paused", press("p"))
+
+	w.testBench.enterNewCycle()
+	w.callbacks[0](watch.Events{Modified: []string{"file1"}})
+	if err := onChange(); err != nil {
+		return err
+	}
+
+	w.testBench.enterNewCycle()
+	w.out.waitFor("Last iteration succeeded", press("p"))
+	press("q")()
+
+	<-ctx.Done()
+	return nil
+}
+
+func TestDevResumeAppliesQueuedChanges(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	testutil.Run(t, "", func(t *testutil.T) {
+		in, keysWriter := io.Pipe()
+		t.Override(&stdin, in)
+		t.Override(&keys, &keyReader{})
+
+		testBench := &TestBench{}
+		out := &waitingWriter{found: make(chan bool, 1)}
+		runner := createRunner(t.T, testBench)
+		runner.runCtx.Opts.Interactive = true
+		runner.Watcher = &PausingWatcher{
+			keys:      keysWriter,
+			out:       out,
+			testBench: testBench,
+		}
+
+		err := runner.Dev(context.Background(), out, []*latest.Artifact{
+			{ImageName: "img1"},
+			{ImageName: "img2"},
+		})
+
+		// The change made during the pause is applied on resume.
+		t.CheckErrorAndDeepEqual(false, err, []Actions{{
+			Built:    []string{"img1:1", "img2:1"},
+			Tested:   []string{"img1:1", "img2:1"},
+			Deployed: []string{"img1:1", "img2:1"},
+		}, {
+			// Nothing happens during the pause
+		}, {
+			Built:    []string{"img1:2"},
+			Tested:   []string{"img1:2"},
+			Deployed: []string{"img1:2", "img2:1"},
+		}}, testBench.Actions())
+	})
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+)
+
+// ephemeralNamespace is a namespace created for a single session.
+type ephemeralNamespace struct {
+	name    string
+	user    string
+	runID   string
+	ttl     time.Duration
+	created bool
+}
+
+var (
+	// sessionNamespace is the ephemeral namespace of the command. It's reused
+	// when the runner is created again after a configuration change, so that
+	// a single namespace is created, and deleted, per command.
+	sessionNamespace *ephemeralNamespace
+
+	// For testing
+	addManifestTransform = deploy.AddManifestTransform
+)
+
+// getEphemeralNamespace returns the ephemeral namespace of the command,
+// and registers the transform that moves the resources to it.
+func getEphemeralNamespace(ttl string) (*ephemeralNamespace, error) {
+	if sessionNamespace != nil {
+		return sessionNamespace, nil
+	}
+
+	namespace, err := newEphemeralNamespace(ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	addManifestTransform(namespace.moveManifests)
+	sessionNamespace = namespace
+	return namespace, nil
+}
+
+// newEphemeralNamespace picks a unique namespace name for the current user.
+func newEphemeralNamespace(ttl string) (*ephemeralNamespace, error) {
+	duration, err := time.ParseDuration(ttl)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing ephemeral namespace ttl")
+	}
+
+	currentUser := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		currentUser = u.Username
+	}
+	runID := util.RandomID()[:8]
+
+	return &ephemeralNamespace{
+		name:  kubernetes.EphemeralNamespaceName(currentUser, runID),
+		user:  currentUser,
+		runID: runID,
+		ttl:   duration,
+	}, nil
+}
+
+// moveManifests is a manifest transform that moves the resources
+// which explicitly set their namespace to the ephemeral namespace.
+func (n *ephemeralNamespace) moveManifests(l kubectl.ManifestList, _ []build.Artifact, _ map[string]bool) (kubectl.ManifestList, error) {
+	return l.SetNamespace(n.name)
+}
+
+// WithEphemeralNamespace creates a deployer that creates the ephemeral
+// namespace before the first deployment and deletes it on cleanup.
+func WithEphemeralNamespace(d deploy.Deployer, namespace *ephemeralNamespace) deploy.Deployer {
+	return &withEphemeralNamespace{
+		Deployer:  d,
+		namespace: namespace,
+	}
+}
+
+type withEphemeralNamespace struct {
+	deploy.Deployer
+	namespace *ephemeralNamespace
+}
+
+func (w *withEphemeralNamespace) Deploy(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []deploy.Labeller) error {
+	if !w.namespace.created {
+		color.Default.Fprintln(out, "Creating namespace", w.namespace.name)
+		if err := kubernetes.CreateEphemeralNamespace(w.namespace.name, w.namespace.user, w.namespace.runID, w.namespace.ttl); err != nil {
+			return err
+		}
+		w.namespace.created = true
+	}
+
+	return w.Deployer.Deploy(ctx, out, builds, labellers)
+}
+
+// Cleanup deletes the namespace even if the cleanup of the deployer fails,
+// since deleting the namespace deletes everything that was deployed to it.
+func (w *withEphemeralNamespace) Cleanup(ctx context.Context, out io.Writer) error {
+	var failures []string
+	if err := w.Deployer.Cleanup(ctx, out); err != nil {
+		failures = append(failures, err.Error())
+	}
+
+	color.Default.Fprintln(out, "Deleting namespace", w.namespace.name)
+	if err := kubernetes.DeleteNamespace(w.namespace.name); err != nil {
+		failures = append(failures, errors.Wrapf(err, "deleting namespace %s", w.namespace.name).Error())
+	}
+
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
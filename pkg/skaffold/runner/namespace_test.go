@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	pkgkubernetes "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewEphemeralNamespace(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		namespace, err := newEphemeralNamespace("2h")
+
+		t.CheckError(false, err)
+		t.CheckDeepEqual(2*time.Hour, namespace.ttl)
+		t.CheckDeepEqual(pkgkubernetes.EphemeralNamespaceName(namespace.user, namespace.runID), namespace.name)
+	})
+
+	testutil.Run(t, "invalid ttl", func(t *testutil.T) {
+		_, err := newEphemeralNamespace("forever")
+
+		t.CheckErrorContains("parsing ephemeral namespace ttl", err)
+	})
+}
+
+func TestGetEphemeralNamespace(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		t.Override(&sessionNamespace, (*ephemeralNamespace)(nil))
+		var transforms int
+		t.Override(&addManifestTransform, func(deploy.ManifestTransform) { transforms++ })
+
+		first, err := getEphemeralNamespace("1h")
+		t.CheckError(false, err)
+		second, err := getEphemeralNamespace("1h")
+		t.CheckError(false, err)
+
+		t.CheckDeepEqual(first.name, second.name)
+		t.CheckDeepEqual(true, first == second)
+		t.CheckDeepEqual(1, transforms)
+	})
+}
+
+func TestWithEphemeralNamespace(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		client := fake.NewSimpleClientset()
+		t.Override(&pkgkubernetes.Client, func() (kubernetes.Interface, error) { return client, nil })
+		namespaces := func() int {
+			list, _ := client.CoreV1().Namespaces().List(metav1.ListOptions{})
+			return len(list.Items)
+		}
+
+		deployer := WithEphemeralNamespace(&TestBench{}, &ephemeralNamespace{
+			name:  "skaffold-alice-1a2b3c4d",
+			user:  "alice",
+			runID: "1a2b3c4d",
+			ttl:   time.Hour,
+		})
+
+		err := deployer.Deploy(context.Background(), ioutil.Discard, nil, nil)
+		t.CheckError(false, err)
+		t.CheckDeepEqual(1, namespaces())
+
+		err = deployer.Deploy(context.Background(), ioutil.Discard, nil, nil)
+		t.CheckError(false, err)
+		t.CheckDeepEqual(1, namespaces())
+
+		err = deployer.Cleanup(context.Background(), ioutil.Discard)
+		t.CheckError(false, err)
+		t.CheckDeepEqual(0, namespaces())
+	})
+}
+
+type failingCleanup struct {
+	TestBench
+}
+
+func (f *failingCleanup) Cleanup(context.Context, io.Writer) error {
+	return errors.New("BUG")
+}
+
+func TestWithEphemeralNamespaceCleanupFailure(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		client := fake.NewSimpleClientset()
+		t.Override(&pkgkubernetes.Client, func() (kubernetes.Interface, error) { return client, nil })
+
+		deployer := WithEphemeralNamespace(&failingCleanup{}, &ephemeralNamespace{
+			name:  "skaffold-alice-1a2b3c4d",
+			user:  "alice",
+			runID: "1a2b3c4d",
+			ttl:   time.Hour,
+		})
+
+		err := deployer.Deploy(context.Background(), ioutil.Discard, nil, nil)
+		t.CheckError(false, err)
+
+		err = deployer.Cleanup(context.Background(), ioutil.Discard)
+		t.CheckErrorContains("BUG", err)
+
+		list, _ := client.CoreV1().Namespaces().List(metav1.ListOptions{})
+		t.CheckDeepEqual(0, len(list.Items))
+	})
+}
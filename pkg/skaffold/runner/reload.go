@@ -0,0 +1,298 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// reconfigure creates the components of the new pipeline and reconfigures
+// the deployers that are kept. It's a var for testing.
+var reconfigure = (*SkaffoldRunner).configure
+
+// ConfigLoader loads the effective configuration, once profiles and defaults are applied.
+type ConfigLoader func() (*latest.SkaffoldConfig, error)
+
+// SetConfigLoader lets `skaffold dev` apply the changes made to the configuration
+// without restarting. Without a loader, any change stops the dev loop with
+// ErrorConfigurationChanged.
+func (r *SkaffoldRunner) SetConfigLoader(loader ConfigLoader) {
+	r.loadConfig = loader
+}
+
+// configChanges describes the differences between two pipelines.
+type configChanges struct {
+	// restart, if set, is why the changes can't be applied incrementally.
+	restart string
+	// rebuild lists the artifacts that are new or were modified.
+	rebuild []*latest.Artifact
+	// removed lists the image names of the artifacts that were removed.
+	removed []string
+	// retest lists the image names of the artifacts that are not rebuilt but whose tests were modified.
+	retest []string
+	// verify is set when the verification tests were modified.
+	verify bool
+	// redeploy lists the names of the deployers that are new or were modified.
+	redeploy []string
+	// cleanup lists the names of the deployers that were removed.
+	cleanup []string
+}
+
+// diffConfigs compares the old and new pipelines.
+func diffConfigs(old, new *latest.Pipeline) configChanges {
+	var changes configChanges
+
+	if !reflect.DeepEqual(old.Watch, new.Watch) {
+		changes.restart = "the watch section changed"
+		return changes
+	}
+
+	// A change to the tag policy or to the build environment rebuilds everything.
+	rebuildAll := !reflect.DeepEqual(buildEnv(old.Build), buildEnv(new.Build))
+
+	oldArtifacts := map[string]*latest.Artifact{}
+	for _, a := range old.Build.Artifacts {
+		oldArtifacts[a.ImageName] = a
+	}
+
+	for _, a := range new.Build.Artifacts {
+		previous, found := oldArtifacts[a.ImageName]
+		delete(oldArtifacts, a.ImageName)
+
+		if found && !reflect.DeepEqual(previous.Watch, a.Watch) {
+			changes.restart = "the watch section of " + a.ImageName + " changed"
+			return changes
+		}
+		switch {
+		case rebuildAll || !found || !reflect.DeepEqual(previous, a):
+			changes.rebuild = append(changes.rebuild, a)
+		case !reflect.DeepEqual(testCases(old.Test, a.ImageName), testCases(new.Test, a.ImageName)):
+			changes.retest = append(changes.retest, a.ImageName)
+		}
+	}
+
+	for _, a := range old.Build.Artifacts {
+		if _, removed := oldArtifacts[a.ImageName]; removed {
+			changes.removed = append(changes.removed, a.ImageName)
+		}
+	}
+
+	diffDeployer := func(name string, oldSet, newSet, equal bool) {
+		switch {
+		case oldSet && !newSet:
+			changes.cleanup = append(changes.cleanup, name)
+		case newSet && !equal:
+			changes.redeploy = append(changes.redeploy, name)
+		}
+	}
+	diffDeployer("helm", old.Deploy.HelmDeploy != nil, new.Deploy.HelmDeploy != nil, reflect.DeepEqual(old.Deploy.HelmDeploy, new.Deploy.HelmDeploy))
+	diffDeployer("kubectl", old.Deploy.KubectlDeploy != nil, new.Deploy.KubectlDeploy != nil, reflect.DeepEqual(old.Deploy.KubectlDeploy, new.Deploy.KubectlDeploy))
+	diffDeployer("kustomize", old.Deploy.KustomizeDeploy != nil, new.Deploy.KustomizeDeploy != nil, reflect.DeepEqual(old.Deploy.KustomizeDeploy, new.Deploy.KustomizeDeploy))
+
+	changes.verify = len(new.Verify) > 0 && !reflect.DeepEqual(old.Verify, new.Verify)
+
+	return changes
+}
+
+// testCases lists the tests of an artifact.
+func testCases(tests []*latest.TestCase, imageName string) []*latest.TestCase {
+	var found []*latest.TestCase
+	for _, test := range tests {
+		if test.ImageName == imageName {
+			found = append(found, test)
+		}
+	}
+	return found
+}
+
+// buildEnv is the build configuration, without the artifacts.
+func buildEnv(cfg latest.BuildConfig) latest.BuildConfig {
+	cfg.Artifacts = nil
+	return cfg
+}
+
+// reload applies the changes made to the configuration. It returns the
+// artifacts that are not watched yet, or ErrorConfigurationChanged when
+// the dev loop has to be restarted.
+func (r *SkaffoldRunner) reload(ctx context.Context, out io.Writer) ([]*latest.Artifact, error) {
+	if r.loadConfig == nil {
+		return nil, ErrorConfigurationChanged
+	}
+
+	cfg, err := r.loadConfig()
+	if err != nil {
+		logrus.Warnln("Ignoring configuration change:", err)
+		return nil, nil
+	}
+
+	oldRunCtx := r.runCtx
+	oldDeployers := r.deployers
+	changes := diffConfigs(oldRunCtx.Cfg, &cfg.Pipeline)
+	if changes.restart != "" {
+		color.Default.Fprintf(out, "Configuration changed: restarting because %s\n", changes.restart)
+		return nil, ErrorConfigurationChanged
+	}
+
+	runCtx, err := runcontext.GetRunContext(oldRunCtx.Opts, &cfg.Pipeline)
+	if err != nil {
+		logrus.Warnln("Ignoring configuration change:", errors.Wrap(err, "getting run context"))
+		return nil, nil
+	}
+	if err := reconfigure(r, runCtx); err != nil {
+		logrus.Warnln("Ignoring configuration change:", err)
+		return nil, nil
+	}
+
+	color.Default.Fprintln(out, "Configuration changed:", changes.summary())
+
+	// Stop deploying the artifacts that were removed.
+	removed := map[string]bool{}
+	for _, imageName := range changes.removed {
+		removed[imageName] = true
+	}
+	var builds []build.Artifact
+	for _, b := range r.builds {
+		if !removed[b.ImageName] {
+			builds = append(builds, b)
+		}
+	}
+	r.builds = builds
+
+	for _, d := range selectDeployers(oldDeployers, changes.cleanup) {
+		if err := d.Cleanup(ctx, out); err != nil {
+			logrus.Warnln("deployer cleanup:", err)
+		}
+	}
+
+	if err := r.applyChanges(ctx, out, changes); err != nil {
+		logrus.Warnln("Skipping deploy due to error:", err)
+	}
+
+	var added []*latest.Artifact
+	for _, a := range changes.rebuild {
+		if !artifactIn(a.ImageName, oldRunCtx.Cfg.Build.Artifacts) {
+			added = append(added, a)
+		}
+	}
+	return added, nil
+}
+
+// applyChanges tests, builds and deploys what the changes require.
+// The deployers that are kept remember what they deployed.
+func (r *SkaffoldRunner) applyChanges(ctx context.Context, out io.Writer, changes configChanges) error {
+	skipTests := r.runCtx.Opts.SkipTests
+
+	if len(changes.retest) > 0 && !skipTests {
+		if err := r.Test(ctx, out, buildsFor(r.builds, changes.retest)); err != nil {
+			return errors.Wrap(err, "test failed")
+		}
+	}
+
+	switch {
+	case len(changes.rebuild) > 0:
+		// All the deployers are run with the new builds.
+		if err := r.buildTestDeploy(ctx, out, changes.rebuild); err != nil {
+			return err
+		}
+	case len(changes.redeploy) > 0:
+		if err := r.wrapDeployer(selectDeployers(r.deployers, changes.redeploy)).Deploy(ctx, out, r.builds, r.labellers); err != nil {
+			return err
+		}
+	}
+
+	if changes.verify && !skipTests {
+		if err := r.Verify(ctx, out, r.builds, r.labellers); err != nil {
+			return errors.Wrap(err, "verification failed")
+		}
+	}
+
+	return nil
+}
+
+// buildsFor returns the builds of the given artifacts.
+func buildsFor(builds []build.Artifact, imageNames []string) []build.Artifact {
+	keep := toSet(imageNames)
+
+	var found []build.Artifact
+	for _, b := range builds {
+		if keep[b.ImageName] {
+			found = append(found, b)
+		}
+	}
+	return found
+}
+
+// summary describes the changes to the user.
+func (c *configChanges) summary() string {
+	var parts []string
+
+	if len(c.rebuild) > 0 {
+		var imageNames []string
+		for _, a := range c.rebuild {
+			imageNames = append(imageNames, a.ImageName)
+		}
+		parts = append(parts, "rebuilding "+strings.Join(imageNames, ", "))
+	}
+	if len(c.removed) > 0 {
+		parts = append(parts, "removing "+strings.Join(c.removed, ", "))
+	}
+	if len(c.retest) > 0 {
+		parts = append(parts, "testing "+strings.Join(c.retest, ", "))
+	}
+	if len(c.redeploy) > 0 {
+		parts = append(parts, "redeploying with "+strings.Join(c.redeploy, ", "))
+	}
+	if len(c.cleanup) > 0 {
+		parts = append(parts, "cleaning up "+strings.Join(c.cleanup, ", "))
+	}
+
+	if c.verify {
+		parts = append(parts, "running the verification tests")
+	}
+
+	if len(parts) == 0 {
+		return "nothing to rebuild or redeploy"
+	}
+	return strings.Join(parts, "; ")
+}
+
+func artifactIn(imageName string, artifacts []*latest.Artifact) bool {
+	for _, a := range artifacts {
+		if a.ImageName == imageName {
+			return true
+		}
+	}
+	return false
+}
+
+func toSet(values []string) map[string]bool {
+	set := map[string]bool{}
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
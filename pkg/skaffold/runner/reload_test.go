@@ -0,0 +1,341 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	kubectl := &latest.KubectlDeploy{Manifests: []string{"k8s/*.yaml"}}
+	helm := &latest.HelmDeploy{Releases: []latest.HelmRelease{{Name: "release"}}}
+
+	pipeline := func(artifacts []*latest.Artifact, deployType latest.DeployType) *latest.Pipeline {
+		return &latest.Pipeline{
+			Build: latest.BuildConfig{
+				Artifacts: artifacts,
+				TagPolicy: latest.TagPolicy{GitTagger: &latest.GitTagger{}},
+			},
+			Deploy: latest.DeployConfig{DeployType: deployType},
+		}
+	}
+	img1 := &latest.Artifact{ImageName: "img1", Workspace: "."}
+	img2 := &latest.Artifact{ImageName: "img2", Workspace: "."}
+	img2Moved := &latest.Artifact{ImageName: "img2", Workspace: "other"}
+
+	var tests = []struct {
+		description string
+		old         *latest.Pipeline
+		new         *latest.Pipeline
+		expected    configChanges
+	}{
+		{
+			description: "no change",
+			old:         pipeline([]*latest.Artifact{img1, img2}, latest.DeployType{KubectlDeploy: kubectl}),
+			new:         pipeline([]*latest.Artifact{img1, img2}, latest.DeployType{KubectlDeploy: kubectl}),
+		},
+		{
+			description: "modified and new artifacts",
+			old:         pipeline([]*latest.Artifact{img1, img2}, latest.DeployType{KubectlDeploy: kubectl}),
+			new:         pipeline([]*latest.Artifact{img2Moved, img1, {ImageName: "img3"}}, latest.DeployType{KubectlDeploy: kubectl}),
+			expected: configChanges{
+				rebuild: []*latest.Artifact{img2Moved, {ImageName: "img3"}},
+			},
+		},
+		{
+			description: "removed artifact",
+			old:         pipeline([]*latest.Artifact{img1, img2}, latest.DeployType{KubectlDeploy: kubectl}),
+			new:         pipeline([]*latest.Artifact{img1}, latest.DeployType{KubectlDeploy: kubectl}),
+			expected: configChanges{
+				removed: []string{"img2"},
+			},
+		},
+		{
+			description: "tag policy change rebuilds everything",
+			old:         pipeline([]*latest.Artifact{img1, img2}, latest.DeployType{KubectlDeploy: kubectl}),
+			new: &latest.Pipeline{
+				Build: latest.BuildConfig{
+					Artifacts: []*latest.Artifact{img1, img2},
+					TagPolicy: latest.TagPolicy{ShaTagger: &latest.ShaTagger{}},
+				},
+				Deploy: latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: kubectl}},
+			},
+			expected: configChanges{
+				rebuild: []*latest.Artifact{img1, img2},
+			},
+		},
+		{
+			description: "modified, new and removed deployers",
+			old:         pipeline([]*latest.Artifact{img1}, latest.DeployType{KubectlDeploy: kubectl, KustomizeDeploy: &latest.KustomizeDeploy{}}),
+			new:         pipeline([]*latest.Artifact{img1}, latest.DeployType{HelmDeploy: helm, KubectlDeploy: &latest.KubectlDeploy{Manifests: []string{"k8s/dev/*.yaml"}}}),
+			expected: configChanges{
+				redeploy: []string{"helm", "kubectl"},
+				cleanup:  []string{"kustomize"},
+			},
+		},
+		{
+			description: "modified tests",
+			old:         pipeline([]*latest.Artifact{img1, img2}, latest.DeployType{KubectlDeploy: kubectl}),
+			new: &latest.Pipeline{
+				Build: latest.BuildConfig{
+					Artifacts: []*latest.Artifact{img1, img2Moved},
+					TagPolicy: latest.TagPolicy{GitTagger: &latest.GitTagger{}},
+				},
+				Test: []*latest.TestCase{
+					{ImageName: "img1", StructureTests: []string{"test/*"}},
+					{ImageName: "img2", StructureTests: []string{"test/*"}},
+				},
+				Deploy: latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: kubectl}},
+			},
+			expected: configChanges{
+				rebuild: []*latest.Artifact{img2Moved},
+				retest:  []string{"img1"},
+			},
+		},
+		{
+			description: "modified verification tests",
+			old:         pipeline([]*latest.Artifact{img1}, latest.DeployType{KubectlDeploy: kubectl}),
+			new: &latest.Pipeline{
+				Build: latest.BuildConfig{
+					Artifacts: []*latest.Artifact{img1},
+					TagPolicy: latest.TagPolicy{GitTagger: &latest.GitTagger{}},
+				},
+				Deploy: latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: kubectl}},
+				Verify: []*latest.VerifyTestCase{{Name: "integration", Image: "img1"}},
+			},
+			expected: configChanges{
+				verify: true,
+			},
+		},
+		{
+			description: "watch section changed",
+			old:         pipeline([]*latest.Artifact{img1}, latest.DeployType{KubectlDeploy: kubectl}),
+			new: &latest.Pipeline{
+				Build: latest.BuildConfig{
+					Artifacts: []*latest.Artifact{img1},
+					TagPolicy: latest.TagPolicy{GitTagger: &latest.GitTagger{}},
+				},
+				Deploy: latest.DeployConfig{DeployType: latest.DeployType{KubectlDeploy: kubectl}},
+				Watch:  latest.WatchConfig{Ignore: []string{"target"}},
+			},
+			expected: configChanges{
+				restart: "the watch section changed",
+			},
+		},
+		{
+			description: "artifact watch section changed",
+			old:         pipeline([]*latest.Artifact{img1}, latest.DeployType{KubectlDeploy: kubectl}),
+			new:         pipeline([]*latest.Artifact{{ImageName: "img1", Workspace: ".", Watch: &latest.WatchConfig{GitIgnore: true}}}, latest.DeployType{KubectlDeploy: kubectl}),
+			expected: configChanges{
+				restart: "the watch section of img1 changed",
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			changes := diffConfigs(test.old, test.new)
+
+			t.CheckDeepEqual(test.expected.restart, changes.restart)
+			t.CheckDeepEqual(test.expected.rebuild, changes.rebuild)
+			t.CheckDeepEqual(test.expected.removed, changes.removed)
+			t.CheckDeepEqual(test.expected.retest, changes.retest)
+			t.CheckDeepEqual(test.expected.verify, changes.verify)
+			t.CheckDeepEqual(test.expected.redeploy, changes.redeploy)
+			t.CheckDeepEqual(test.expected.cleanup, changes.cleanup)
+		})
+	}
+}
+
+func TestDevReload(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	var tests = []struct {
+		description     string
+		artifacts       []*latest.Artifact
+		tests           []*latest.TestCase
+		verify          []*latest.VerifyTestCase
+		loadErr         error
+		expectedActions []Actions
+	}{
+		{
+			description: "rebuild the modified artifact only",
+			artifacts: []*latest.Artifact{
+				{ImageName: "img1"},
+				{ImageName: "img2", Workspace: "other"},
+			},
+			expectedActions: []Actions{
+				{
+					Built:    []string{"img1:1", "img2:1"},
+					Tested:   []string{"img1:1", "img2:1"},
+					Deployed: []string{"img1:1", "img2:1"},
+				},
+				{
+					Built:    []string{"img2:2"},
+					Tested:   []string{"img2:2"},
+					Deployed: []string{"img2:2", "img1:1"},
+				},
+			},
+		},
+		{
+			description: "stop deploying removed artifact",
+			artifacts: []*latest.Artifact{
+				{ImageName: "img1"},
+			},
+			expectedActions: []Actions{
+				{
+					Built:    []string{"img1:1", "img2:1"},
+					Tested:   []string{"img1:1", "img2:1"},
+					Deployed: []string{"img1:1", "img2:1"},
+				},
+				{},
+			},
+		},
+		{
+			description: "run the modified tests",
+			artifacts: []*latest.Artifact{
+				{ImageName: "img1"},
+				{ImageName: "img2"},
+			},
+			tests: []*latest.TestCase{
+				{ImageName: "img2", StructureTests: []string{"test/*"}},
+			},
+			verify: []*latest.VerifyTestCase{
+				{Name: "integration", Image: "img1"},
+			},
+			expectedActions: []Actions{
+				{
+					Built:    []string{"img1:1", "img2:1"},
+					Tested:   []string{"img1:1", "img2:1"},
+					Deployed: []string{"img1:1", "img2:1"},
+				},
+				{
+					Tested:   []string{"img2:1"},
+					Verified: []string{"img1:1", "img2:1"},
+				},
+			},
+		},
+		{
+			description: "invalid configuration is ignored",
+			loadErr:     errors.New("invalid"),
+			expectedActions: []Actions{
+				{
+					Built:    []string{"img1:1", "img2:1"},
+					Tested:   []string{"img1:1", "img2:1"},
+					Deployed: []string{"img1:1", "img2:1"},
+				},
+				{},
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&reconfigure, func(r *SkaffoldRunner, runCtx *runcontext.RunContext) error {
+				r.runCtx = runCtx
+				return nil
+			})
+
+			testBench := &TestBench{}
+			runner := createRunner(t.T, testBench)
+			runner.Watcher = &TestWatcher{
+				events:    []watch.Events{{Modified: []string{"skaffold.yaml"}}},
+				testBench: testBench,
+			}
+			artifacts := []*latest.Artifact{
+				{ImageName: "img1"},
+				{ImageName: "img2"},
+			}
+			runner.runCtx.Cfg.Build.Artifacts = artifacts
+
+			runner.SetConfigLoader(func() (*latest.SkaffoldConfig, error) {
+				if test.loadErr != nil {
+					return nil, test.loadErr
+				}
+
+				pipeline := *runner.runCtx.Cfg
+				pipeline.Build.Artifacts = test.artifacts
+				pipeline.Test = test.tests
+				pipeline.Verify = test.verify
+				return &latest.SkaffoldConfig{Pipeline: pipeline}, nil
+			})
+
+			err := runner.Dev(context.Background(), ioutil.Discard, artifacts)
+
+			t.CheckErrorAndDeepEqual(false, err, test.expectedActions, testBench.Actions())
+		})
+	}
+}
+
+func TestDevReloadWithoutLoader(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	testBench := &TestBench{}
+	runner := createRunner(t, testBench)
+	runner.Watcher = &TestWatcher{
+		events:    []watch.Events{{Modified: []string{"skaffold.yaml"}}},
+		testBench: testBench,
+	}
+
+	err := runner.Dev(context.Background(), ioutil.Discard, []*latest.Artifact{
+		{ImageName: "img1"},
+		{ImageName: "img2"},
+	})
+
+	testutil.CheckDeepEqual(t, true, err == ErrorConfigurationChanged)
+}
+
+type namedBench struct {
+	*TestBench
+	name string
+}
+
+func (n namedBench) Labels() map[string]string {
+	return map[string]string{constants.Labels.Deployer: n.name}
+}
+
+func TestApplyChangesRedeploysThroughWrappers(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	testutil.Run(t, "", func(t *testutil.T) {
+		testBench := &TestBench{}
+		runner := createRunner(t.T, testBench)
+		runner.deployers = deploy.DeployerMux{namedBench{TestBench: testBench, name: "kubectl"}}
+		runner.builds = []build.Artifact{{ImageName: "img1", Tag: "img1:1"}}
+
+		var out bytes.Buffer
+		err := runner.applyChanges(context.Background(), &out, configChanges{redeploy: []string{"kubectl"}})
+
+		t.CheckError(false, err)
+		t.CheckDeepEqual([]string{"img1:1"}, testBench.Actions()[0].Deployed)
+		t.CheckContains("Starting deploy...", out.String())
+	})
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	// For testing
+	sessionsDir = defaultSessionsDir
+	imageExists = imageExistsLocallyOrRemotely
+)
+
+// sessionState is what's persisted of a dev session to resume it later.
+type sessionState struct {
+	Artifacts map[string]artifactState `yaml:"artifacts"`
+}
+
+// artifactState is the last build of an artifact, along with the
+// configuration and the state of the files it was built from.
+type artifactState struct {
+	Tag    string        `yaml:"tag"`
+	Config string        `yaml:"config"`
+	Files  watch.FileMap `yaml:"files"`
+}
+
+func defaultSessionsDir() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", errors.Wrap(err, "retrieving home directory")
+	}
+	return filepath.Join(home, constants.DefaultSkaffoldDir, "sessions"), nil
+}
+
+// sessionFile is where the dev session of the current project is persisted.
+// A project is identified by its configuration file and its kube-context.
+func (r *SkaffoldRunner) sessionFile() (string, error) {
+	dir, err := sessionsDir()
+	if err != nil {
+		return "", err
+	}
+
+	configFile, err := filepath.Abs(r.runCtx.Opts.ConfigurationFile)
+	if err != nil {
+		return "", errors.Wrap(err, "resolving configuration file")
+	}
+
+	key, err := util.SHA256(strings.NewReader(configFile + "\n" + r.runCtx.KubeContext))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, key[:16]+".yaml"), nil
+}
+
+// resume reuses the images of the previous dev session for the artifacts
+// whose configuration and files didn't change. It returns the artifacts that
+// still need to be built.
+func (r *SkaffoldRunner) resume(ctx context.Context, out io.Writer, artifacts []*latest.Artifact) []*latest.Artifact {
+	state, err := r.loadSession()
+	if err != nil {
+		logrus.Warnln("Unable to resume the previous dev session:", err)
+		return artifacts
+	}
+	r.session = state
+	if len(state.Artifacts) == 0 {
+		return artifacts
+	}
+
+	color.Default.Fprintln(out, "Resuming the previous dev session...")
+
+	var needToBuild []*latest.Artifact
+	for _, artifact := range artifacts {
+		color.Default.Fprintf(out, " - %s: ", artifact.ImageName)
+
+		previous, found := state.Artifacts[artifact.ImageName]
+		reason := "Not built previously"
+		if found {
+			reason = r.changedSince(ctx, artifact, previous)
+		}
+		if reason != "" {
+			color.Red.Fprintf(out, "%s. Rebuilding.\n", reason)
+			needToBuild = append(needToBuild, artifact)
+			continue
+		}
+
+		color.Green.Fprintf(out, "Resumed %s\n", previous.Tag)
+		r.imageList.Add(previous.Tag)
+		r.builds = append(r.builds, build.Artifact{
+			ImageName: artifact.ImageName,
+			Tag:       previous.Tag,
+		})
+	}
+
+	return needToBuild
+}
+
+// changedSince tells why an artifact can't be resumed, or returns
+// an empty string if its previous image can be reused.
+func (r *SkaffoldRunner) changedSince(ctx context.Context, artifact *latest.Artifact, previous artifactState) string {
+	config, err := r.artifactConfig(artifact)
+	if err != nil {
+		logrus.Debugf("Unable to hash the configuration of %s: %s", artifact.ImageName, err)
+		return "Configuration unknown"
+	}
+	if config != previous.Config {
+		return "Configuration changed"
+	}
+
+	files, err := r.artifactFiles(ctx, artifact)
+	if err != nil {
+		logrus.Debugf("Unable to list the files of %s: %s", artifact.ImageName, err)
+		return "Files unknown"
+	}
+	if !sameFiles(previous.Files, files) {
+		return "Files changed"
+	}
+
+	if !imageExists(ctx, previous.Tag, r.runCtx.InsecureRegistries) {
+		return "Image not found"
+	}
+
+	return ""
+}
+
+// recordBuilds persists the state of the artifacts that were just built.
+// The state of the other artifacts is kept as is, since synced files are
+// not part of their last image.
+func (r *SkaffoldRunner) recordBuilds(ctx context.Context, artifacts []*latest.Artifact) {
+	if r.session == nil {
+		r.session = &sessionState{}
+	}
+	if r.session.Artifacts == nil {
+		r.session.Artifacts = map[string]artifactState{}
+	}
+
+	for _, artifact := range artifacts {
+		state, err := r.artifactState(ctx, artifact)
+		if err != nil {
+			logrus.Warnf("Unable to record the build of %s, it won't be resumed: %s", artifact.ImageName, err)
+			delete(r.session.Artifacts, artifact.ImageName)
+			continue
+		}
+		r.session.Artifacts[artifact.ImageName] = *state
+	}
+
+	// Forget the artifacts that are no longer built.
+	for imageName := range r.session.Artifacts {
+		if findBuild(r.builds, imageName) == nil {
+			delete(r.session.Artifacts, imageName)
+		}
+	}
+
+	if err := r.saveSession(); err != nil {
+		logrus.Warnln("Unable to save the dev session:", err)
+	}
+}
+
+func (r *SkaffoldRunner) artifactState(ctx context.Context, artifact *latest.Artifact) (*artifactState, error) {
+	b := findBuild(r.builds, artifact.ImageName)
+	if b == nil {
+		return nil, errors.New("no build found")
+	}
+
+	config, err := r.artifactConfig(artifact)
+	if err != nil {
+		return nil, errors.Wrap(err, "hashing configuration")
+	}
+
+	files, err := r.artifactFiles(ctx, artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	return &artifactState{
+		Tag:    b.Tag,
+		Config: config,
+		Files:  files,
+	}, nil
+}
+
+// artifactConfig hashes everything in the configuration that affects an artifact's image.
+func (r *SkaffoldRunner) artifactConfig(artifact *latest.Artifact) (string, error) {
+	buf, err := yaml.Marshal(struct {
+		Artifact *latest.Artifact
+		Build    latest.BuildConfig
+	}{artifact, buildEnv(r.runCtx.Cfg.Build)})
+	if err != nil {
+		return "", err
+	}
+	return util.SHA256(bytes.NewReader(buf))
+}
+
+// artifactFiles lists the modification times of an artifact's files, like the watcher does.
+func (r *SkaffoldRunner) artifactFiles(ctx context.Context, artifact *latest.Artifact) (watch.FileMap, error) {
+	return watch.Stat(func() ([]string, error) {
+		return r.Builder.DependenciesForArtifact(ctx, artifact)
+	})
+}
+
+func (r *SkaffoldRunner) loadSession() (*sessionState, error) {
+	file, err := r.sessionFile()
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return &sessionState{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading dev session")
+	}
+
+	var state sessionState
+	if err := yaml.Unmarshal(buf, &state); err != nil {
+		return nil, errors.Wrapf(err, "parsing dev session %s", file)
+	}
+	return &state, nil
+}
+
+func (r *SkaffoldRunner) saveSession() error {
+	file, err := r.sessionFile()
+	if err != nil {
+		return err
+	}
+
+	buf, err := yaml.Marshal(r.session)
+	if err != nil {
+		return errors.Wrap(err, "marshalling dev session")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return errors.Wrap(err, "creating sessions directory")
+	}
+	return ioutil.WriteFile(file, buf, 0644)
+}
+
+// sameFiles compares two file states. Modification times are compared
+// with Equal since they don't have the same location once persisted.
+func sameFiles(previous, current watch.FileMap) bool {
+	if len(previous) != len(current) {
+		return false
+	}
+	for path, modTime := range previous {
+		t, found := current[path]
+		if !found || !t.Equal(modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+func findBuild(builds []build.Artifact, imageName string) *build.Artifact {
+	for i := range builds {
+		if builds[i].ImageName == imageName {
+			return &builds[i]
+		}
+	}
+	return nil
+}
+
+func imageExistsLocallyOrRemotely(ctx context.Context, tag string, insecureRegistries map[string]bool) bool {
+	client, err := docker.NewAPIClient(false, insecureRegistries)
+	if err == nil && client.ImageExists(ctx, tag) {
+		return true
+	}
+
+	_, err = docker.RemoteDigest(tag, insecureRegistries)
+	return err == nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestDevResume(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	var tests = []struct {
+		description     string
+		imageExists     bool
+		artifacts       []*latest.Artifact
+		expectedActions Actions
+		expectedOutput  string
+	}{
+		{
+			description: "nothing changed",
+			imageExists: true,
+			artifacts: []*latest.Artifact{
+				{ImageName: "img1"},
+				{ImageName: "img2"},
+			},
+			expectedActions: Actions{
+				Deployed: []string{"img1:1", "img2:1"},
+			},
+			expectedOutput: "img2: Resumed img2:1",
+		},
+		{
+			description: "modified artifact",
+			imageExists: true,
+			artifacts: []*latest.Artifact{
+				{ImageName: "img1"},
+				{ImageName: "img2", Workspace: "other"},
+			},
+			expectedActions: Actions{
+				Built:    []string{"img2:1"},
+				Tested:   []string{"img2:1"},
+				Deployed: []string{"img2:1", "img1:1"},
+			},
+			expectedOutput: "img2: Configuration changed. Rebuilding.",
+		},
+		{
+			description: "new artifact",
+			imageExists: true,
+			artifacts: []*latest.Artifact{
+				{ImageName: "img1"},
+				{ImageName: "img2"},
+				{ImageName: "img3"},
+			},
+			expectedActions: Actions{
+				Built:    []string{"img3:1"},
+				Tested:   []string{"img3:1"},
+				Deployed: []string{"img3:1", "img1:1", "img2:1"},
+			},
+			expectedOutput: "img3: Not built previously. Rebuilding.",
+		},
+		{
+			description: "images were deleted",
+			imageExists: false,
+			artifacts: []*latest.Artifact{
+				{ImageName: "img1"},
+				{ImageName: "img2"},
+			},
+			expectedActions: Actions{
+				Built:    []string{"img1:1", "img2:1"},
+				Tested:   []string{"img1:1", "img2:1"},
+				Deployed: []string{"img1:1", "img2:1"},
+			},
+			expectedOutput: "img1: Image not found. Rebuilding.",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir()
+			t.Override(&sessionsDir, func() (string, error) { return tmpDir.Root(), nil })
+			t.Override(&imageExists, func(context.Context, string, map[string]bool) bool { return test.imageExists })
+
+			// Previous session
+			previous := createRunner(t.T, &TestBench{})
+			previous.runCtx.Opts.Resume = true
+			previous.Watcher = &TestWatcher{}
+			err := previous.Dev(context.Background(), ioutil.Discard, []*latest.Artifact{
+				{ImageName: "img1"},
+				{ImageName: "img2"},
+			})
+			t.CheckError(false, err)
+
+			// New session
+			testBench := &TestBench{}
+			runner := createRunner(t.T, testBench)
+			runner.runCtx.Opts.Resume = true
+			runner.Watcher = &TestWatcher{}
+
+			var out bytes.Buffer
+			err = runner.Dev(context.Background(), &out, test.artifacts)
+
+			t.CheckErrorAndDeepEqual(false, err, []Actions{test.expectedActions}, testBench.Actions())
+			t.CheckContains(test.expectedOutput, out.String())
+		})
+	}
+}
+
+func TestDevResumeAfterRebuild(t *testing.T) {
+	restore := testutil.SetupFakeKubernetesContext(t, api.Config{CurrentContext: "cluster1"})
+	defer restore()
+
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir()
+		t.Override(&sessionsDir, func() (string, error) { return tmpDir.Root(), nil })
+		t.Override(&imageExists, func(context.Context, string, map[string]bool) bool { return true })
+
+		artifacts := []*latest.Artifact{
+			{ImageName: "img1"},
+			{ImageName: "img2"},
+		}
+
+		// Previous session, where img1 is rebuilt
+		testBench := &TestBench{}
+		previous := createRunner(t.T, testBench)
+		previous.runCtx.Opts.Resume = true
+		previous.Watcher = &TestWatcher{
+			events:    []watch.Events{{Modified: []string{"file1"}}},
+			testBench: testBench,
+		}
+		err := previous.Dev(context.Background(), ioutil.Discard, artifacts)
+		t.CheckError(false, err)
+
+		// New session
+		testBench = &TestBench{}
+		runner := createRunner(t.T, testBench)
+		runner.runCtx.Opts.Resume = true
+		runner.Watcher = &TestWatcher{}
+		err = runner.Dev(context.Background(), ioutil.Discard, artifacts)
+
+		t.CheckErrorAndDeepEqual(false, err, []Actions{{
+			Deployed: []string{"img1:2", "img2:1"},
+		}}, testBench.Actions())
+	})
+}
+
+func TestSameFiles(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Second)
+
+	testutil.CheckDeepEqual(t, true, sameFiles(watch.FileMap{"a": now}, watch.FileMap{"a": now.UTC()}))
+	testutil.CheckDeepEqual(t, false, sameFiles(watch.FileMap{"a": now}, watch.FileMap{"a": later}))
+	testutil.CheckDeepEqual(t, false, sameFiles(watch.FileMap{"a": now}, watch.FileMap{"b": now}))
+	testutil.CheckDeepEqual(t, false, sameFiles(watch.FileMap{"a": now}, watch.FileMap{"a": now, "b": now}))
+}
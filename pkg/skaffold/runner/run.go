@@ -21,13 +21,20 @@ import (
 	"io"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
 )
 
-// Run builds artifacts, runs tests on built artifacts, and then deploys them.
+// Run builds artifacts, runs tests on built artifacts, deploys them
+// and then runs the verification tests against the deployed services.
 func (r *SkaffoldRunner) Run(ctx context.Context, out io.Writer, artifacts []*latest.Artifact) error {
 	if err := r.buildTestDeploy(ctx, out, artifacts); err != nil {
 		return err
 	}
+	if !r.runCtx.Opts.SkipTests {
+		if err := r.Verify(ctx, out, r.builds, r.labellers); err != nil {
+			return errors.Wrap(err, "verification failed")
+		}
+	}
 	if r.runCtx.Opts.Tail {
 		logger := r.newLogger(out, artifacts)
 		return r.TailLogs(ctx, out, logger)
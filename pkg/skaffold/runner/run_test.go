@@ -44,6 +44,7 @@ func TestRun(t *testing.T) {
 				Built:    []string{"img:1"},
 				Tested:   []string{"img:1"},
 				Deployed: []string{"img:1"},
+				Verified: []string{"img:1"},
 			}},
 		},
 		{
@@ -69,6 +70,16 @@ func TestRun(t *testing.T) {
 				Tested: []string{"img:1"},
 			}},
 		},
+		{
+			description: "run verify error",
+			testBench:   &TestBench{verifyErrors: []error{errors.New("")}},
+			shouldErr:   true,
+			expectedActions: []Actions{{
+				Built:    []string{"img:1"},
+				Tested:   []string{"img:1"},
+				Deployed: []string{"img:1"},
+			}},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
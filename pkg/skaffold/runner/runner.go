@@ -30,6 +30,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
@@ -39,6 +40,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/verify"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/watch"
 	"github.com/pkg/errors"
@@ -53,76 +55,126 @@ type SkaffoldRunner struct {
 	tag.Tagger
 	sync.Syncer
 	watch.Watcher
+	verify.Verifier
 
 	cache             *cache.Cache
 	runCtx            *runcontext.RunContext
 	labellers         []deploy.Labeller
+	deployers         deploy.DeployerMux
 	builds            []build.Artifact
 	hasBuilt          bool
 	hasDeployed       bool
 	imageList         *kubernetes.ImageList
+	namespace         *ephemeralNamespace
+	loadConfig        ConfigLoader
+	session           *sessionState
 	RPCServerShutdown func() error
 }
 
 // NewForConfig returns a new SkaffoldRunner for a SkaffoldConfig
 func NewForConfig(opts *config.SkaffoldOptions, cfg *latest.SkaffoldConfig) (*SkaffoldRunner, error) {
+	var namespace *ephemeralNamespace
+	if opts.EphemeralNamespace {
+		var err error
+		if namespace, err = getEphemeralNamespace(opts.EphemeralNamespaceTTL); err != nil {
+			return nil, err
+		}
+		opts.Namespace = namespace.name
+	}
+
 	runCtx, err := runcontext.GetRunContext(opts, &cfg.Pipeline)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting run context")
 	}
 
-	tagger, err := getTagger(cfg.Build.TagPolicy, opts.CustomTag)
+	r := &SkaffoldRunner{
+		Syncer:    kubectl.NewSyncer(runCtx.Namespaces),
+		imageList: kubernetes.NewImageList(),
+		namespace: namespace,
+	}
+	if err := r.configure(runCtx); err != nil {
+		return nil, err
+	}
+
+	trigger, err := watch.NewTrigger(runCtx)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing tag config")
+		return nil, errors.Wrap(err, "creating watch trigger")
+	}
+	r.Watcher = watch.NewWatcher(trigger)
+
+	shutdown, err := server.Initialize(runCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing skaffold server")
+	}
+	r.RPCServerShutdown = shutdown
+	event.InitializeState(runCtx)
+
+	event.LogSkaffoldMetadata(version.Get())
+
+	return r, nil
+}
+
+// configure creates the components of the pipeline described by a run context.
+func (r *SkaffoldRunner) configure(runCtx *runcontext.RunContext) error {
+	opts := runCtx.Opts
+
+	tagger, err := getTagger(runCtx.Cfg.Build.TagPolicy, opts.CustomTag)
+	if err != nil {
+		return errors.Wrap(err, "parsing tag config")
 	}
 
 	builder, err := getBuilder(runCtx)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing build config")
+		return errors.Wrap(err, "parsing build config")
 	}
 	artifactCache := cache.NewCache(builder, runCtx)
 
 	tester := getTester(runCtx)
 
-	deployer, err := getDeployer(runCtx)
+	// Deployers are kept when the kubernetes context doesn't change,
+	// so that they remember what they deployed.
+	var previous deploy.DeployerMux
+	if r.runCtx != nil && r.runCtx.KubeContext == runCtx.KubeContext {
+		previous = r.deployers
+	}
+	deployers := newDeployers(runCtx, previous)
+
+	deployer, err := getDeployer(runCtx, deployers)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing deploy config")
+		return errors.Wrap(err, "parsing deploy config")
 	}
 
 	defaultLabeller := NewLabeller("")
 	labellers := []deploy.Labeller{opts, builder, deployer, tagger, defaultLabeller}
 
-	builder, tester, deployer = WithTimings(builder, tester, deployer, opts.CacheArtifacts)
-	if opts.Notification {
-		deployer = WithNotification(deployer)
-	}
+	builder, tester, _ = WithTimings(builder, tester, deployer, opts.CacheArtifacts)
+
+	r.Builder = builder
+	r.Tester = tester
+	r.deployers = deployers
+	r.Tagger = tagger
+	r.Verifier = verify.NewVerifier(runCtx)
+	r.labellers = labellers
+	r.cache = artifactCache
+	r.runCtx = runCtx
+	r.Deployer = r.wrapDeployer(deployer)
+	return nil
+}
 
-	trigger, err := watch.NewTrigger(runCtx)
-	if err != nil {
-		return nil, errors.Wrap(err, "creating watch trigger")
+// wrapDeployer adds what every deployment gets: the ephemeral namespace,
+// timings and notifications. Deployers that are run on their own, after
+// a configuration change, are wrapped the same way.
+func (r *SkaffoldRunner) wrapDeployer(deployer deploy.Deployer) deploy.Deployer {
+	if r.namespace != nil {
+		deployer = WithEphemeralNamespace(deployer, r.namespace)
 	}
 
-	shutdown, err := server.Initialize(runCtx)
-	if err != nil {
-		return nil, errors.Wrap(err, "initializing skaffold server")
+	_, _, deployer = WithTimings(r.Builder, r.Tester, deployer, r.runCtx.Opts.CacheArtifacts)
+	if r.runCtx.Opts.Notification {
+		deployer = WithNotification(deployer)
 	}
-	event.InitializeState(runCtx)
-
-	event.LogSkaffoldMetadata(version.Get())
 
-	return &SkaffoldRunner{
-		Builder:           builder,
-		Tester:            tester,
-		Deployer:          deployer,
-		Tagger:            tagger,
-		Syncer:            kubectl.NewSyncer(runCtx.Namespaces),
-		Watcher:           watch.NewWatcher(trigger),
-		labellers:         labellers,
-		imageList:         kubernetes.NewImageList(),
-		cache:             artifactCache,
-		runCtx:            runCtx,
-		RPCServerShutdown: shutdown,
-	}, nil
+	return deployer
 }
 
 func getBuilder(runCtx *runcontext.RunContext) (build.Builder, error) {
@@ -148,20 +200,71 @@ func getTester(runCtx *runcontext.RunContext) test.Tester {
 	return test.NewTester(runCtx)
 }
 
-func getDeployer(runCtx *runcontext.RunContext) (deploy.Deployer, error) {
-	switch {
-	case runCtx.Cfg.Deploy.HelmDeploy != nil:
-		return deploy.NewHelmDeployer(runCtx), nil
+func getDeployer(runCtx *runcontext.RunContext, deployers deploy.DeployerMux) (deploy.Deployer, error) {
+	if len(deployers) == 0 {
+		return nil, fmt.Errorf("unknown deployer for config %+v", runCtx.Cfg.Deploy)
+	}
 
-	case runCtx.Cfg.Deploy.KubectlDeploy != nil:
-		return deploy.NewKubectlDeployer(runCtx), nil
+	// Even a single deployer runs through the mux, which sends the deploy events.
+	return deployers, nil
+}
 
-	case runCtx.Cfg.Deploy.KustomizeDeploy != nil:
-		return deploy.NewKustomizeDeployer(runCtx), nil
+// reconfigurable is implemented by the deployers that can take
+// a new configuration without forgetting what they deployed.
+type reconfigurable interface {
+	deploy.Deployer
+	Reconfigure(*runcontext.RunContext)
+}
 
-	default:
-		return nil, fmt.Errorf("unknown deployer for config %+v", runCtx.Cfg.Deploy)
+// newDeployers creates the deployers configured in a run context, in the order they run.
+// The previous deployers are reconfigured instead of being created again.
+func newDeployers(runCtx *runcontext.RunContext, previous deploy.DeployerMux) deploy.DeployerMux {
+	var deployers deploy.DeployerMux
+	add := func(name string, create func() deploy.Deployer) {
+		if d, found := deployerNamed(previous, name).(reconfigurable); found {
+			d.Reconfigure(runCtx)
+			deployers = append(deployers, d)
+			return
+		}
+		deployers = append(deployers, create())
+	}
+
+	if runCtx.Cfg.Deploy.HelmDeploy != nil {
+		add("helm", func() deploy.Deployer { return deploy.NewHelmDeployer(runCtx) })
 	}
+
+	if runCtx.Cfg.Deploy.KubectlDeploy != nil {
+		add("kubectl", func() deploy.Deployer { return deploy.NewKubectlDeployer(runCtx) })
+	}
+
+	if runCtx.Cfg.Deploy.KustomizeDeploy != nil {
+		add("kustomize", func() deploy.Deployer { return deploy.NewKustomizeDeployer(runCtx) })
+	}
+
+	return deployers
+}
+
+// deployerNamed finds a deployer by name.
+func deployerNamed(deployers deploy.DeployerMux, name string) deploy.Deployer {
+	for _, d := range deployers {
+		if d.Labels()[constants.Labels.Deployer] == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// selectDeployers returns the deployers with the given names, in the order they run.
+func selectDeployers(deployers deploy.DeployerMux, names []string) deploy.DeployerMux {
+	keep := toSet(names)
+
+	var selected deploy.DeployerMux
+	for _, d := range deployers {
+		if keep[d.Labels()[constants.Labels.Deployer]] {
+			selected = append(selected, d)
+		}
+	}
+	return selected
 }
 
 func getTagger(t latest.TagPolicy, customTag string) (tag.Tagger, error) {
@@ -261,6 +364,10 @@ func (r *SkaffoldRunner) buildTestDeploy(ctx context.Context, out io.Writer, art
 	// Make sure all artifacts are redeployed. Not only those that were just built.
 	r.builds = build.MergeWithPreviousBuilds(bRes, r.builds)
 
+	if r.runCtx.Opts.Resume {
+		r.recordBuilds(ctx, artifacts)
+	}
+
 	if err := r.deploy(ctx, out, r.builds); err != nil {
 		return errors.Wrap(err, "deploy failed")
 	}
@@ -29,6 +29,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/defaults"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
@@ -41,6 +42,7 @@ type Actions struct {
 	Synced   []string
 	Tested   []string
 	Deployed []string
+	Verified []string
 }
 
 type TestBench struct {
@@ -48,6 +50,7 @@ type TestBench struct {
 	syncErrors   []error
 	testErrors   []error
 	deployErrors []error
+	verifyErrors []error
 
 	currentActions Actions
 	actions        []Actions
@@ -130,6 +133,19 @@ func (t *TestBench) Deploy(ctx context.Context, out io.Writer, artifacts []build
 	return nil
 }
 
+func (t *TestBench) Verify(ctx context.Context, out io.Writer, artifacts []build.Artifact, labellers []deploy.Labeller) error {
+	if len(t.verifyErrors) > 0 {
+		err := t.verifyErrors[0]
+		t.verifyErrors = t.verifyErrors[1:]
+		if err != nil {
+			return err
+		}
+	}
+
+	t.currentActions.Verified = findTags(artifacts)
+	return nil
+}
+
 func (t *TestBench) Actions() []Actions {
 	return append(t.actions, t.currentActions)
 }
@@ -160,6 +176,7 @@ func createRunner(t *testing.T, testBench *TestBench) *SkaffoldRunner {
 	runner.Syncer = testBench
 	runner.Tester = testBench
 	runner.Deployer = testBench
+	runner.Verifier = testBench
 
 	return runner
 }
@@ -299,3 +316,30 @@ func TestNewForConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestNewDeployersKeepsPreviousDeployers(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		runCtx := func(deployType latest.DeployType) *runcontext.RunContext {
+			return &runcontext.RunContext{
+				Cfg:  &latest.Pipeline{Deploy: latest.DeployConfig{DeployType: deployType}},
+				Opts: &config.SkaffoldOptions{},
+			}
+		}
+
+		previous := newDeployers(runCtx(latest.DeployType{
+			KubectlDeploy:   &latest.KubectlDeploy{Manifests: []string{"k8s/*.yaml"}},
+			KustomizeDeploy: &latest.KustomizeDeploy{},
+		}), nil)
+
+		updated := &latest.KubectlDeploy{Manifests: []string{"k8s/dev/*.yaml"}}
+		deployers := newDeployers(runCtx(latest.DeployType{
+			HelmDeploy:    &latest.HelmDeploy{},
+			KubectlDeploy: updated,
+		}), previous)
+
+		t.CheckDeepEqual(2, len(deployers))
+		testutil.CheckErrorAndTypeEquality(t.T, false, nil, &deploy.HelmDeployer{}, deployers[0])
+		t.CheckDeepEqual(true, deployers[1] == previous[0])
+		t.CheckDeepEqual(updated, deployers[1].(*deploy.KubectlDeployer).KubectlDeploy)
+	})
+}
@@ -0,0 +1,53 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// singleKeyMode lets the terminal send each key as soon as it's pressed,
+// without echoing it. Unlike raw mode, output processing and signals,
+// like Ctrl-C, keep working. It returns a function that restores the
+// terminal, or false if stdin is not a terminal.
+func singleKeyMode(in io.Reader) (func(), bool) {
+	f, ok := in.(*os.File)
+	if !ok {
+		return nil, false
+	}
+
+	fd := int(f.Fd())
+	original, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+	if err != nil {
+		return nil, false
+	}
+
+	termios := *original
+	termios.Lflag &^= unix.ICANON | unix.ECHO
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlWriteTermios, &termios); err != nil {
+		return nil, false
+	}
+
+	return func() { unix.IoctlSetTermios(fd, ioctlWriteTermios, original) }, true
+}
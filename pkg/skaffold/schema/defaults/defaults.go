@@ -33,7 +33,7 @@ func Set(c *latest.SkaffoldConfig) error {
 	defaultToLocalBuild(c)
 	defaultToKubectlDeploy(c)
 	setDefaultTagger(c)
-	setDefaultKustomizePath(c)
+	setDefaultKustomizePaths(c)
 	setDefaultKubectlManifests(c)
 
 	withCloudBuildConfig(c,
@@ -117,13 +117,15 @@ func setDefaultTagger(c *latest.SkaffoldConfig) {
 	c.Build.TagPolicy = latest.TagPolicy{GitTagger: &latest.GitTagger{}}
 }
 
-func setDefaultKustomizePath(c *latest.SkaffoldConfig) {
+func setDefaultKustomizePaths(c *latest.SkaffoldConfig) {
 	kustomize := c.Deploy.KustomizeDeploy
 	if kustomize == nil {
 		return
 	}
 
-	kustomize.KustomizePath = valueOrDefault(kustomize.KustomizePath, constants.DefaultKustomizationPath)
+	if len(kustomize.KustomizePaths) == 0 {
+		kustomize.KustomizePaths = []string{constants.DefaultKustomizationPath}
+	}
 }
 
 func setDefaultKubectlManifests(c *latest.SkaffoldConfig) {
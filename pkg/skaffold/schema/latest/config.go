@@ -20,7 +20,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
 )
 
-const Version string = "skaffold/v1beta11"
+const Version string = "skaffold/v1beta12"
 
 // NewSkaffoldConfig creates a SkaffoldConfig
 func NewSkaffoldConfig() util.VersionedConfig {
@@ -52,6 +52,40 @@ type Pipeline struct {
 
 	// Deploy describes how images are deployed.
 	Deploy DeployConfig `yaml:"deploy,omitempty"`
+
+	// Verify *alpha* describes the integration tests run in the cluster once images are deployed.
+	Verify []*VerifyTestCase `yaml:"verify,omitempty"`
+
+	// Watch *alpha* describes which files are ignored by the file watcher in dev mode.
+	Watch WatchConfig `yaml:"watch,omitempty"`
+}
+
+// WatchConfig *alpha* describes which files are ignored by the file watcher in dev mode.
+type WatchConfig struct {
+	// Ignore lists patterns of files that never trigger a rebuild, a redeploy or a sync.
+	// Patterns use the `.dockerignore` syntax and are relative to the current directory,
+	// or to the artifact's context for an artifact's `watch` section.
+	// For example: `["**/node_modules", "target", "build"]`.
+	Ignore []string `yaml:"ignore,omitempty"`
+
+	// GitIgnore also ignores the files listed in `.gitignore`, at the root
+	// of the current directory and of the artifacts' contexts.
+	GitIgnore bool `yaml:"gitignore,omitempty"`
+
+	// QuietPeriod is how long to wait without file changes before starting an iteration.
+	// Only used in the pipeline's `watch` section. Defaults to the watch poll interval.
+	// For example: `500ms`.
+	QuietPeriod string `yaml:"quietPeriod,omitempty"`
+
+	// MaxWait is the maximum time to wait for file changes to settle before forcing an iteration.
+	// Only used in the pipeline's `watch` section. Defaults to no maximum.
+	// For example: `5s`.
+	MaxWait string `yaml:"maxWait,omitempty"`
+
+	// MinInterval is the minimum time between the start of two iterations.
+	// Only used in the pipeline's `watch` section. Defaults to no minimum.
+	// For example: `10s`.
+	MinInterval string `yaml:"minInterval,omitempty"`
 }
 
 func (c *SkaffoldConfig) GetVersion() string {
@@ -290,6 +324,73 @@ type TestCase struct {
 	// to run on that artifact.
 	// For example: `["./test/*"]`.
 	StructureTests []string `yaml:"structureTests,omitempty"`
+
+	// CustomTests *alpha* lists the commands to run against that artifact.
+	CustomTests []*CustomTest `yaml:"custom,omitempty"`
+}
+
+// CustomTest *alpha* describes a user defined command that tests an artifact once it's built.
+// The fully qualified image name is passed to the command in the `IMAGE` environment variable.
+type CustomTest struct {
+	// Command is the command to run. A non-zero exit code fails the test.
+	// For example: `./test/contract.sh`.
+	Command string `yaml:"command" yamltags:"required"`
+
+	// TimeoutSeconds is the amount of time (in seconds) that the command is allowed to run.
+	// Defaults to no timeout.
+	TimeoutSeconds int `yaml:"timeoutSeconds,omitempty"`
+
+	// Env lists additional `key=value` environment variables passed to the command.
+	// For example: `["NAMESPACE=test"]`.
+	Env []string `yaml:"env,omitempty"`
+
+	// Dependencies are the file dependencies that skaffold should watch to know when to rerun the test.
+	Dependencies *CustomTestDependencies `yaml:"dependencies,omitempty"`
+}
+
+// CustomTestDependencies *alpha* is used to specify dependencies for a custom test.
+type CustomTestDependencies struct {
+	// Command represents a custom command that skaffold executes to obtain dependencies. The output of this command *must* be a valid JSON array.
+	Command string `yaml:"command,omitempty" yamltags:"oneOf=dependency"`
+
+	// Paths should be set to the file dependencies for this test.
+	Paths []string `yaml:"paths,omitempty" yamltags:"oneOf=dependency"`
+
+	// Ignore specifies the paths that should be ignored by skaffold's file watcher.
+	// Will only work in conjunction with `paths`.
+	Ignore []string `yaml:"ignore,omitempty"`
+}
+
+// VerifyTestCase *alpha* describes an integration test that runs as a Kubernetes Job
+// once the deploy phase is complete. A failed Job fails the pipeline.
+type VerifyTestCase struct {
+	// Name is a unique name for the test. The Job's name is derived from it.
+	// For example: `integration`.
+	Name string `yaml:"name" yamltags:"required"`
+
+	// Image is the image that runs the test. If it's one of the artifacts,
+	// it's replaced with the image that was just built.
+	// For example: `gcr.io/k8s-skaffold/integration-tests`.
+	Image string `yaml:"image" yamltags:"required"`
+
+	// Command overrides the image's entrypoint.
+	// For example: `["go", "test", "./integration/..."]`.
+	Command []string `yaml:"command,omitempty"`
+
+	// Args are the arguments passed to the command.
+	Args []string `yaml:"args,omitempty"`
+
+	// Env lists `key=value` environment variables set in the test container.
+	// For example: `["API_URL=http://api:8080"]`.
+	Env []string `yaml:"env,omitempty"`
+
+	// Namespace is the Kubernetes namespace in which the Job is created.
+	// Defaults to the namespace passed with `--namespace` or the current namespace in Kubernetes configuration.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// TimeoutSeconds is the amount of time (in seconds) that the Job is allowed to run.
+	// Defaults to 10 minutes.
+	TimeoutSeconds int `yaml:"timeoutSeconds,omitempty"`
 }
 
 // DeployConfig contains all the configuration needed by the deploy steps.
@@ -298,17 +399,18 @@ type DeployConfig struct {
 }
 
 // DeployType contains the specific implementation and parameters needed
-// for the deploy step. Only one field should be populated.
+// for the deploy step. Several deployers can be set: they run in the
+// order helm, kubectl then kustomize.
 type DeployType struct {
 	// HelmDeploy *beta* uses the `helm` CLI to apply the charts to the cluster.
-	HelmDeploy *HelmDeploy `yaml:"helm,omitempty" yamltags:"oneOf=deploy"`
+	HelmDeploy *HelmDeploy `yaml:"helm,omitempty"`
 
 	// KubectlDeploy *beta* uses a client side `kubectl apply` to deploy manifests.
 	// You'll need a `kubectl` CLI version installed that's compatible with your cluster.
-	KubectlDeploy *KubectlDeploy `yaml:"kubectl,omitempty" yamltags:"oneOf=deploy"`
+	KubectlDeploy *KubectlDeploy `yaml:"kubectl,omitempty"`
 
 	// KustomizeDeploy *beta* uses the `kustomize` CLI to "patch" a deployment for a target environment.
-	KustomizeDeploy *KustomizeDeploy `yaml:"kustomize,omitempty" yamltags:"oneOf=deploy"`
+	KustomizeDeploy *KustomizeDeploy `yaml:"kustomize,omitempty"`
 }
 
 // KubectlDeploy *beta* uses a client side `kubectl apply` to deploy manifests.
@@ -347,6 +449,38 @@ type HelmDeploy struct {
 	// Flags are additional option flags that are passed on the command
 	// line to `helm`.
 	Flags HelmDeployFlags `yaml:"flags,omitempty"`
+
+	// Repositories are chart repositories that Skaffold adds and updates
+	// before deploying the releases.
+	Repositories []HelmRepository `yaml:"repositories,omitempty"`
+
+	// RenderTemplates if `true`, Skaffold renders the charts with `helm template`
+	// and applies the manifests with `kubectl`, instead of installing the releases.
+	// Manifests are then labelled and transformed like with the other deployers.
+	// Defaults to `false`.
+	RenderTemplates bool `yaml:"renderTemplates,omitempty"`
+}
+
+// HelmRepository is a chart repository that Skaffold adds with `helm repo add`.
+type HelmRepository struct {
+	// Name is the name of the repository that chart paths refer to.
+	// For example: `stable`.
+	Name string `yaml:"name,omitempty" yamltags:"required"`
+
+	// URL is the URL of the repository.
+	// For example: `https://kubernetes-charts.storage.googleapis.com`.
+	URL string `yaml:"url,omitempty" yamltags:"required"`
+
+	// Username is the username used to authenticate to the repository.
+	// It can be a template using environment variables.
+	// For example: `{{.HELM_REPO_USERNAME}}`.
+	Username string `yaml:"username,omitempty"`
+
+	// Password is the password used to authenticate to the repository.
+	// It's passed to helm on stdin, which requires helm 3.2 or later.
+	// It can be a template using environment variables.
+	// For example: `{{.HELM_REPO_PASSWORD}}`.
+	Password string `yaml:"password,omitempty"`
 }
 
 // HelmDeployFlags are additional option flags that are passed on the command
@@ -364,9 +498,14 @@ type HelmDeployFlags struct {
 
 // KustomizeDeploy *beta* uses the `kustomize` CLI to "patch" a deployment for a target environment.
 type KustomizeDeploy struct {
-	// KustomizePath is the path to Kustomization files.
-	// Defaults to `.`.
-	KustomizePath string `yaml:"path,omitempty"`
+	// KustomizePaths are the paths to the directories holding Kustomization files.
+	// They are built and deployed together.
+	// Defaults to `["."]`.
+	KustomizePaths []string `yaml:"paths,omitempty"`
+
+	// BuildArgs are additional args passed to `kustomize build`.
+	// For example: `["--enable_alpha_plugins"]`.
+	BuildArgs []string `yaml:"buildArgs,omitempty"`
 
 	// Flags are additional flags passed to `kubectl`.
 	Flags KubectlFlags `yaml:"flags,omitempty"`
@@ -380,6 +519,12 @@ type HelmRelease struct {
 	// ChartPath is the path to the Helm chart.
 	ChartPath string `yaml:"chartPath,omitempty" yamltags:"required"`
 
+	// DependsOn lists the names of the releases that must be deployed before this one.
+	// Releases that don't depend on each other are deployed in parallel.
+	// Set `wait: true` on a release for its dependents to be deployed only once it's ready.
+	// If no release declares dependencies, releases are deployed one at a time, in order.
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+
 	// ValuesFiles are the paths to the Helm `values` files.
 	ValuesFiles []string `yaml:"valuesFiles,omitempty"`
 
@@ -390,6 +535,7 @@ type HelmRelease struct {
 	Namespace string `yaml:"namespace,omitempty"`
 
 	// Version is the version of the chart.
+	// Remote charts with an exact version are downloaded once and cached.
 	Version string `yaml:"version,omitempty"`
 
 	// SetValues are key-value pairs.
@@ -407,6 +553,7 @@ type HelmRelease struct {
 	Wait bool `yaml:"wait,omitempty"`
 
 	// RecreatePods if `true`, Skaffold will send `--recreate-pods` flag to Helm CLI.
+	// Ignored with Helm 3, which doesn't support it.
 	// Defaults to `false`.
 	RecreatePods bool `yaml:"recreatePods,omitempty"`
 
@@ -453,6 +600,9 @@ type HelmImageConfig struct {
 
 	// HelmConventionConfig is the image configuration uses the syntax `IMAGE-NAME.repository=IMAGE-REPOSITORY, IMAGE-NAME.tag=IMAGE-TAG`.
 	HelmConventionConfig *HelmConventionConfig `yaml:"helm,omitempty" yamltags:"oneOf=helmImageStrategy"`
+
+	// HelmTemplatedConfig is the image configuration where each value is a template evaluated against the built image.
+	HelmTemplatedConfig *HelmTemplatedConfig `yaml:"templated,omitempty" yamltags:"oneOf=helmImageStrategy"`
 }
 
 // HelmFQNConfig is the image config to use the FullyQualifiedImageName as param to set.
@@ -465,6 +615,15 @@ type HelmFQNConfig struct {
 type HelmConventionConfig struct {
 }
 
+// HelmTemplatedConfig is the image config where each value is a Go template.
+type HelmTemplatedConfig struct {
+	// Values are templates for values under the key of each image in the release's `values`.
+	// Keys can be nested paths, such as `image.digest`, and list items, such as `containers[0].image`.
+	// Templates can use `{{.Image}}` (full reference), `{{.Registry}}`, `{{.Repository}}`, `{{.Tag}}` and `{{.Digest}}`.
+	// For example: `{"registry": "{{.Registry}}", "repository": "{{.Repository}}", "digest": "{{.Digest}}"}`.
+	Values map[string]string `yaml:"values,omitempty" yamltags:"required"`
+}
+
 // Artifact are the items that need to be built, along with the context in which
 // they should be built.
 type Artifact struct {
@@ -483,6 +642,10 @@ type Artifact struct {
 	// ArtifactType describes how to build an artifact.
 	ArtifactType `yaml:",inline"`
 
+	// Watch *alpha* describes which files of this artifact are ignored by the file watcher,
+	// on top of the pipeline's `watch` section.
+	Watch *WatchConfig `yaml:"watch,omitempty"`
+
 	WorkspaceHash string `yaml:"-,omitempty"`
 }
 
@@ -524,8 +687,12 @@ type Profile struct {
 
 	// Activation criteria by which a profile can be auto-activated.
 	// The profile is auto-activated if any one of the activations are triggered.
-	// An activation is triggered if all of the criteria (env, kubeContext, command) are triggered.
+	// An activation is triggered if all of its criteria are triggered.
 	Activation []Activation `yaml:"activation,omitempty"`
+
+	// Activates lists other profiles that are activated whenever this profile is.
+	// For example: `[local-registry]`.
+	Activates []string `yaml:"activates,omitempty"`
 }
 
 // JSONPatch patch to be applied by a profile.
@@ -560,6 +727,28 @@ type Activation struct {
 	// Command is a Skaffold command for which the profile is auto-activated.
 	// For example: `dev`.
 	Command string `yaml:"command,omitempty"`
+
+	// Namespace is the target Kubernetes namespace for which the profile is auto-activated.
+	// The target namespace is the one given with `--namespace`, or else the namespace
+	// of the current Kubernetes context.
+	// For example: `staging`.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Path is a file or directory whose existence auto-activates the profile.
+	// It's relative to the directory of the configuration file.
+	// For example: `.local-registry`.
+	Path string `yaml:"path,omitempty"`
+
+	// GitBranch is a git branch for which the profile is auto-activated,
+	// when the directory of the configuration file is a git checkout.
+	// For example: `main`.
+	GitBranch string `yaml:"gitBranch,omitempty"`
+
+	// AllOf lists activations that all need to be triggered.
+	AllOf []Activation `yaml:"allOf,omitempty"`
+
+	// AnyOf lists activations of which at least one needs to be triggered.
+	AnyOf []Activation `yaml:"anyOf,omitempty"`
 }
 
 // ArtifactType describes how to build an artifact.
@@ -593,6 +782,11 @@ type CustomArtifact struct {
 	BuildCommand string `yaml:"buildCommand,omitempty"`
 	// Dependencies are the file dependencies that skaffold should watch for both rebuilding and file syncing for this artifact.
 	Dependencies *CustomDependencies `yaml:"dependencies,omitempty"`
+
+	// Platforms are the target platforms, like `linux/amd64` or `linux/arm64`.
+	// The build command is run once per platform, with the `PLATFORM` environment variable.
+	// With several platforms, the images are combined into an image index, which requires pushing the images.
+	Platforms []string `yaml:"platforms,omitempty"`
 }
 
 // CustomDependencies *alpha* is used to specify dependencies for an artifact built by a custom build script.
@@ -650,6 +844,11 @@ type KanikoArtifact struct {
 	// Cache configures Kaniko caching. If a cache is specified, Kaniko will
 	// use a remote cache which will speed up builds.
 	Cache *KanikoCache `yaml:"cache,omitempty"`
+
+	// Platforms are the target platforms, like `linux/amd64` or `linux/arm64`.
+	// Each platform is built by a separate kaniko pod, scheduled on a node of that platform.
+	// With several platforms, the images are combined into an image index.
+	Platforms []string `yaml:"platforms,omitempty"`
 }
 
 // DockerArtifact *beta* describes an artifact built from a Dockerfile,
@@ -680,6 +879,36 @@ type DockerArtifact struct {
 
 	// NoCache used to pass in --no-cache to docker build to prevent caching.
 	NoCache bool `yaml:"noCache,omitempty"`
+
+	// Platforms are the target platforms, like `linux/amd64` or `linux/arm64`.
+	// Building for another platform than the docker daemon's requires BuildKit or an experimental daemon.
+	// With several platforms, the images are combined into an image index, which requires pushing the images.
+	Platforms []string `yaml:"platforms,omitempty"`
+
+	// Secrets are the files exposed to `RUN --mount=type=secret` instructions.
+	// Using secrets builds the image with the docker CLI and BuildKit.
+	Secrets []*DockerSecret `yaml:"secrets,omitempty"`
+
+	// SSH lists the SSH agent sockets or keys exposed to `RUN --mount=type=ssh` instructions.
+	// For example: `["default"]` or `["github=/path/to/key"]`.
+	// Using ssh builds the image with the docker CLI and BuildKit.
+	SSH []string `yaml:"ssh,omitempty"`
+
+	// CacheTo lists the cache exports, passed to `--cache-to`.
+	// For example: `["type=registry,ref=gcr.io/k8s-skaffold/example:cache,mode=max"]` or `["type=inline"]`.
+	// Using cacheTo builds the image with `docker buildx`.
+	CacheTo []string `yaml:"cacheTo,omitempty"`
+}
+
+// DockerSecret is a file exposed to `RUN --mount=type=secret` instructions.
+type DockerSecret struct {
+	// ID is the id of the secret, used in `RUN --mount=type=secret,id=<id>`.
+	ID string `yaml:"id,omitempty" yamltags:"required"`
+
+	// Source is the path to the file holding the secret, relative to the workspace.
+	// It also accepts environment variables via the go template syntax.
+	// For example: `{{.HOME}}/.npmrc`.
+	Source string `yaml:"src,omitempty" yamltags:"required"`
 }
 
 // BazelArtifact *beta* describes an artifact built with [Bazel](https://bazel.build/).
@@ -19,6 +19,8 @@ package schema
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
 	"strings"
 
@@ -26,61 +28,267 @@ import (
 	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
+	pkgutil "github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	yamlpatch "github.com/krishicks/yaml-patch"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	yaml "gopkg.in/yaml.v2"
 )
 
+// ActivatedProfile is a profile activated for the current run.
+type ActivatedProfile struct {
+	// Name is the name of the profile.
+	Name string
+
+	// Reason tells why the profile was activated.
+	Reason string
+
+	// Patches lists the outcome of each of the profile's patches.
+	Patches []PatchResult
+}
+
+// PatchResult is the outcome of a profile's JSON patch.
+type PatchResult struct {
+	Op   string
+	Path string
+
+	// Err is nil when the patch could be applied.
+	Err error
+}
+
 // ApplyProfiles returns configuration modified by the application
 // of a list of profiles.
 func ApplyProfiles(c *latest.SkaffoldConfig, opts *cfg.SkaffoldOptions) error {
+	_, err := ApplyProfilesWithReport(c, opts)
+	return err
+}
+
+// ApplyProfilesWithReport applies profiles like ApplyProfiles does and reports
+// the profiles that were applied, in order. If a profile can't be applied,
+// the report ends with that profile.
+func ApplyProfilesWithReport(c *latest.SkaffoldConfig, opts *cfg.SkaffoldOptions) ([]ActivatedProfile, error) {
 	byName := profilesByName(c.Profiles)
 
 	profiles, err := activatedProfiles(c.Profiles, opts)
 	if err != nil {
-		return errors.Wrap(err, "finding auto-activated profiles")
+		return nil, errors.Wrap(err, "finding auto-activated profiles")
 	}
 
-	for _, name := range profiles {
-		profile, present := byName[name]
+	var applied []ActivatedProfile
+	for _, activated := range profiles {
+		profile, present := byName[activated.Name]
 		if !present {
-			return fmt.Errorf("couldn't find profile %s", name)
+			return applied, fmt.Errorf("couldn't find profile %s", activated.Name)
 		}
 
-		if err := applyProfile(c, profile); err != nil {
-			return errors.Wrapf(err, "applying profile %s", name)
+		activated.Patches, err = applyProfile(c, profile)
+		applied = append(applied, activated)
+		if err != nil {
+			return applied, errors.Wrapf(err, "applying profile %s", activated.Name)
 		}
 	}
 
-	return nil
+	return applied, nil
 }
 
-func activatedProfiles(profiles []latest.Profile, opts *cfg.SkaffoldOptions) ([]string, error) {
-	activated := opts.Profiles
+func activatedProfiles(profiles []latest.Profile, opts *cfg.SkaffoldOptions) ([]ActivatedProfile, error) {
+	var activated []ActivatedProfile
+	for _, name := range opts.Profiles {
+		activated = append(activated, ActivatedProfile{
+			Name:   name,
+			Reason: "selected on the command line",
+		})
+	}
 
 	// Auto-activated profiles
+	ctx := newActivationContext(opts)
 	for _, profile := range profiles {
 		for _, cond := range profile.Activation {
-			command := isCommand(cond.Command, opts)
-
-			env, err := isEnv(cond.Env)
+			triggered, err := isTriggered(cond, ctx)
 			if err != nil {
 				return nil, err
 			}
 
-			kubeContext, err := isKubeContext(cond.KubeContext)
-			if err != nil {
-				return nil, err
+			if triggered {
+				activated = append(activated, ActivatedProfile{
+					Name:   profile.Name,
+					Reason: fmt.Sprintf("activation triggered: %s", describeActivation(cond)),
+				})
+				break
 			}
+		}
+	}
 
-			if command && env && kubeContext {
-				activated = append(activated, profile.Name)
-			}
+	return withActivatedProfiles(activated, profilesByName(profiles)), nil
+}
+
+// withActivatedProfiles adds the profiles activated by other profiles,
+// right after the profile that activates them. Each profile is listed only once.
+func withActivatedProfiles(activated []ActivatedProfile, byName map[string]latest.Profile) []ActivatedProfile {
+	var profiles []ActivatedProfile
+	seen := map[string]bool{}
+
+	var add func(profile ActivatedProfile)
+	add = func(profile ActivatedProfile) {
+		if seen[profile.Name] {
+			return
+		}
+		seen[profile.Name] = true
+		profiles = append(profiles, profile)
+
+		for _, name := range byName[profile.Name].Activates {
+			add(ActivatedProfile{
+				Name:   name,
+				Reason: fmt.Sprintf("activated by profile %s", profile.Name),
+			})
 		}
 	}
 
-	return activated, nil
+	for _, profile := range activated {
+		add(profile)
+	}
+
+	return profiles
+}
+
+// describeActivation lists the criteria of an activation.
+func describeActivation(cond latest.Activation) string {
+	var criteria []string
+
+	for _, criterion := range []struct{ name, value string }{
+		{"env", cond.Env},
+		{"kubeContext", cond.KubeContext},
+		{"command", cond.Command},
+		{"namespace", cond.Namespace},
+		{"path", cond.Path},
+		{"gitBranch", cond.GitBranch},
+	} {
+		if criterion.value != "" {
+			criteria = append(criteria, fmt.Sprintf("%s=%s", criterion.name, criterion.value))
+		}
+	}
+
+	for _, group := range []struct {
+		name  string
+		conds []latest.Activation
+	}{
+		{"allOf", cond.AllOf},
+		{"anyOf", cond.AnyOf},
+	} {
+		if len(group.conds) == 0 {
+			continue
+		}
+
+		var descriptions []string
+		for _, c := range group.conds {
+			descriptions = append(descriptions, "{"+describeActivation(c)+"}")
+		}
+		criteria = append(criteria, fmt.Sprintf("%s=[%s]", group.name, strings.Join(descriptions, ", ")))
+	}
+
+	if len(criteria) == 0 {
+		return "always"
+	}
+
+	return strings.Join(criteria, ", ")
+}
+
+// activationContext is what profile activations are checked against.
+type activationContext struct {
+	opts *cfg.SkaffoldOptions
+
+	// configDir is the directory of the configuration file,
+	// which activation paths are relative to.
+	configDir string
+
+	// gitBranch is the current git branch, looked up once, when needed.
+	gitBranch       string
+	gitBranchFound  bool
+	gitBranchLoaded bool
+}
+
+func newActivationContext(opts *cfg.SkaffoldOptions) *activationContext {
+	var configDir string
+	if opts.ConfigurationFile != "" && !pkgutil.IsURL(opts.ConfigurationFile) {
+		configDir = filepath.Dir(opts.ConfigurationFile)
+	}
+
+	return &activationContext{
+		opts:      opts,
+		configDir: configDir,
+	}
+}
+
+// currentGitBranch returns the git branch checked out in the directory of the
+// configuration file. It returns false when that's not a git repository.
+func (c *activationContext) currentGitBranch() (string, bool) {
+	if !c.gitBranchLoaded {
+		cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+		cmd.Dir = c.configDir
+
+		out, err := pkgutil.RunCmdOut(cmd)
+		if err != nil {
+			logrus.Debugln("Unable to get the current git branch:", err)
+		} else {
+			c.gitBranch = strings.TrimSpace(string(out))
+			c.gitBranchFound = true
+		}
+		c.gitBranchLoaded = true
+	}
+
+	return c.gitBranch, c.gitBranchFound
+}
+
+// isTriggered checks that all the criteria of an activation are met.
+func isTriggered(cond latest.Activation, ctx *activationContext) (bool, error) {
+	criteria := []func() (bool, error){
+		func() (bool, error) { return isCommand(cond.Command, ctx.opts), nil },
+		func() (bool, error) { return isEnv(cond.Env) },
+		func() (bool, error) { return isPath(cond.Path, ctx.configDir), nil },
+		func() (bool, error) { return isKubeContext(cond.KubeContext) },
+		func() (bool, error) { return isNamespace(cond.Namespace, ctx.opts) },
+		func() (bool, error) { return isGitBranch(cond.GitBranch, ctx), nil },
+		func() (bool, error) { return isAllOf(cond.AllOf, ctx) },
+		func() (bool, error) { return isAnyOf(cond.AnyOf, ctx) },
+	}
+
+	for _, criterion := range criteria {
+		triggered, err := criterion()
+		if err != nil || !triggered {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func isAllOf(conds []latest.Activation, ctx *activationContext) (bool, error) {
+	for _, cond := range conds {
+		triggered, err := isTriggered(cond, ctx)
+		if err != nil || !triggered {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func isAnyOf(conds []latest.Activation, ctx *activationContext) (bool, error) {
+	if len(conds) == 0 {
+		return true, nil
+	}
+
+	for _, cond := range conds {
+		triggered, err := isTriggered(cond, ctx)
+		if err != nil {
+			return false, err
+		}
+		if triggered {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
 func isEnv(env string) (bool, error) {
@@ -120,6 +328,61 @@ func isKubeContext(kubeContext string) (bool, error) {
 	return satisfies(kubeContext, currentKubeContext), nil
 }
 
+func isNamespace(namespace string, opts *cfg.SkaffoldOptions) (bool, error) {
+	if namespace == "" {
+		return true, nil
+	}
+
+	targetNamespace := opts.Namespace
+	if targetNamespace == "" {
+		kubeConfig, err := kubectx.CurrentConfig()
+		if err != nil {
+			return false, errors.Wrap(err, "getting current cluster context")
+		}
+
+		if current, found := kubeConfig.Contexts[kubeConfig.CurrentContext]; found {
+			targetNamespace = current.Namespace
+		}
+		if targetNamespace == "" {
+			targetNamespace = "default"
+		}
+	}
+
+	return satisfies(namespace, targetNamespace), nil
+}
+
+// isPath checks that a file exists, or doesn't if the path starts with `!`.
+// Relative paths are relative to the directory of the configuration file.
+func isPath(path string, configDir string) bool {
+	if path == "" {
+		return true
+	}
+
+	negated := strings.HasPrefix(path, "!")
+	path = strings.TrimPrefix(path, "!")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(configDir, path)
+	}
+	_, err := os.Stat(path)
+
+	return (err == nil) != negated
+}
+
+// isGitBranch checks the current git branch. Outside of a git repository,
+// no branch condition is met.
+func isGitBranch(branch string, ctx *activationContext) bool {
+	if branch == "" {
+		return true
+	}
+
+	current, found := ctx.currentGitBranch()
+	if !found {
+		return false
+	}
+
+	return satisfies(branch, current)
+}
+
 func satisfies(expected, actual string) bool {
 	if strings.HasPrefix(expected, "!") {
 		return actual != expected[1:]
@@ -127,7 +390,7 @@ func satisfies(expected, actual string) bool {
 	return actual == expected
 }
 
-func applyProfile(config *latest.SkaffoldConfig, profile latest.Profile) error {
+func applyProfile(config *latest.SkaffoldConfig, profile latest.Profile) ([]PatchResult, error) {
 	logrus.Infof("applying profile: %s", profile.Name)
 
 	// this intentionally removes the Profiles field from the returned config
@@ -138,20 +401,24 @@ func applyProfile(config *latest.SkaffoldConfig, profile latest.Profile) error {
 			Build:  overlayProfileField(config.Build, profile.Build).(latest.BuildConfig),
 			Deploy: overlayProfileField(config.Deploy, profile.Deploy).(latest.DeployConfig),
 			Test:   overlayProfileField(config.Test, profile.Test).([]*latest.TestCase),
+			Verify: overlayProfileField(config.Verify, profile.Verify).([]*latest.VerifyTestCase),
+			Watch:  overlayProfileField(config.Watch, profile.Watch).(latest.WatchConfig),
 		},
 	}
 
 	if len(profile.Patches) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Apply profile patches
 	buf, err := yaml.Marshal(*config)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var patches []yamlpatch.Operation
+	var results []PatchResult
+	var invalid error
 	for _, patch := range profile.Patches {
 		// Default patch operation to `replace`
 		op := patch.Op
@@ -171,19 +438,28 @@ func applyProfile(config *latest.SkaffoldConfig, profile latest.Profile) error {
 			Value: value,
 		}
 
+		result := PatchResult{Op: op, Path: string(patch.Path)}
 		if !tryPatch(patch, buf) {
-			return fmt.Errorf("invalid path: %s", patch.Path)
+			result.Err = fmt.Errorf("invalid path: %s", patch.Path)
+			if invalid == nil {
+				invalid = result.Err
+			}
 		}
 
+		results = append(results, result)
 		patches = append(patches, patch)
 	}
 
+	if invalid != nil {
+		return results, invalid
+	}
+
 	buf, err = yamlpatch.Patch(patches).Apply(buf)
 	if err != nil {
-		return err
+		return results, err
 	}
 
-	return yaml.Unmarshal(buf, config)
+	return results, yaml.Unmarshal(buf, config)
 }
 
 // tryPatch is here to verify patches one by one before we
@@ -254,6 +530,20 @@ func overlayProfileField(config interface{}, profile interface{}) interface{} {
 		if util.IsOneOfField(t.Field(0)) {
 			return overlayOneOfField(config, profile)
 		}
+		// deployers set in a profile replace all the deployers of the config.
+		if deployType, ok := profile.(latest.DeployType); ok {
+			if deployType == (latest.DeployType{}) {
+				return config
+			}
+			return deployType
+		}
+		// a watch section set in a profile replaces the config's watch section.
+		if watch, ok := profile.(latest.WatchConfig); ok {
+			if reflect.DeepEqual(watch, latest.WatchConfig{}) {
+				return config
+			}
+			return watch
+		}
 		return overlayStructField(config, profile)
 	case reflect.Slice:
 		// either return the values provided in the profile, or the original values if none were provided.
@@ -24,6 +24,7 @@ import (
 	cfg "github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
+	pkgutil "github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 	yamlpatch "github.com/krishicks/yaml-patch"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -235,6 +236,61 @@ func TestApplyProfiles(t *testing.T) {
 				withHelmDeploy(),
 			),
 		},
+		{
+			description: "profile deployers replace all deployers",
+			profile:     "profile",
+			config: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withHelmDeploy(),
+				withKubectlDeploy("k8s/*.yaml"),
+				withProfiles(latest.Profile{
+					Name: "profile",
+					Pipeline: latest.Pipeline{
+						Deploy: latest.DeployConfig{
+							DeployType: latest.DeployType{
+								KubectlDeploy: &latest.KubectlDeploy{
+									Manifests: []string{"k8s/prod/*.yaml"},
+								},
+							},
+						},
+					},
+				}),
+			),
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/prod/*.yaml"),
+			),
+		},
+		{
+			description: "profile watch section replaces the watch section",
+			profile:     "profile",
+			config: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withWatchIgnore("target"),
+				withProfiles(latest.Profile{
+					Name: "profile",
+					Pipeline: latest.Pipeline{
+						Watch: latest.WatchConfig{
+							Ignore: []string{"**/node_modules"},
+						},
+					},
+				}),
+			),
+			expected: config(
+				withLocalBuild(
+					withGitTagger(),
+				),
+				withKubectlDeploy("k8s/*.yaml"),
+				withWatchIgnore("**/node_modules"),
+			),
+		},
 		{
 			description: "patch Dockerfile",
 			profile:     "profile",
@@ -415,6 +471,81 @@ func TestActivatedProfiles(t *testing.T) {
 				},
 			},
 			expected: []string{"activated"},
+		}, {
+			description: "Auto-activated by namespace flag",
+			opts: &cfg.SkaffoldOptions{
+				Namespace: "staging",
+			},
+			profiles: []latest.Profile{
+				{Name: "activated", Activation: []latest.Activation{{Namespace: "staging"}}},
+				{Name: "not-activated", Activation: []latest.Activation{{Namespace: "prod-namespace"}}},
+			},
+			expected: []string{"activated"},
+		}, {
+			description: "Auto-activated by default namespace",
+			opts:        &cfg.SkaffoldOptions{},
+			profiles: []latest.Profile{
+				{Name: "activated", Activation: []latest.Activation{{Namespace: "default"}}},
+				{Name: "not-activated", Activation: []latest.Activation{{Namespace: "staging"}}},
+				{Name: "also-activated", Activation: []latest.Activation{{Namespace: "!staging"}}},
+			},
+			expected: []string{"activated", "also-activated"},
+		}, {
+			description: "Auto-activated by file existence",
+			opts:        &cfg.SkaffoldOptions{},
+			profiles: []latest.Profile{
+				{Name: "activated", Activation: []latest.Activation{{Path: "profiles.go"}}},
+				{Name: "not-activated", Activation: []latest.Activation{{Path: "missing.yaml"}}},
+				{Name: "also-activated", Activation: []latest.Activation{{Path: "!missing.yaml"}}},
+			},
+			expected: []string{"activated", "also-activated"},
+		}, {
+			description: "allOf and anyOf",
+			opts: &cfg.SkaffoldOptions{
+				Command: "dev",
+			},
+			profiles: []latest.Profile{
+				{
+					Name: "activated", Activation: []latest.Activation{{
+						AllOf: []latest.Activation{{Command: "dev"}, {Env: "KEY=VALUE"}},
+						AnyOf: []latest.Activation{{KubeContext: "dev-context"}, {KubeContext: "prod-context"}},
+					}},
+				},
+				{
+					Name: "not-activated-all", Activation: []latest.Activation{{
+						AllOf: []latest.Activation{{Command: "dev"}, {Env: "KEY=OTHER"}},
+					}},
+				},
+				{
+					Name: "not-activated-any", Activation: []latest.Activation{{
+						AnyOf: []latest.Activation{{Command: "run"}, {Command: "build"}},
+					}},
+				},
+			},
+			expected: []string{"activated"},
+		}, {
+			description: "Profiles activating other profiles",
+			opts: &cfg.SkaffoldOptions{
+				Command:  "dev",
+				Profiles: []string{"explicit"},
+			},
+			profiles: []latest.Profile{
+				{Name: "explicit", Activates: []string{"local-registry"}},
+				{Name: "dev-on-minikube", Activation: []latest.Activation{{Command: "dev"}}, Activates: []string{"local-registry", "debug"}},
+				{Name: "local-registry", Activates: []string{"explicit"}},
+				{Name: "debug"},
+				{Name: "not-activated"},
+			},
+			expected: []string{"explicit", "local-registry", "dev-on-minikube", "debug"},
+		}, {
+			description: "Activated once when several activations are triggered",
+			opts: &cfg.SkaffoldOptions{
+				Command: "dev",
+			},
+			profiles: []latest.Profile{
+				{Name: "activated", Activation: []latest.Activation{{Command: "dev"}, {Env: "KEY=VALUE"}}},
+			},
+			expected: []string{"activated"},
 		},
 	}
 
@@ -427,12 +558,101 @@ func TestActivatedProfiles(t *testing.T) {
 
 			activated, err := activatedProfiles(test.profiles, test.opts)
 
-			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, activated)
+			testutil.CheckErrorAndDeepEqual(t, test.shouldErr, err, test.expected, profileNames(activated))
 		})
 	}
 
 }
 
+func TestActivatedProfilesByGitBranch(t *testing.T) {
+	profiles := []latest.Profile{
+		{Name: "activated", Activation: []latest.Activation{{GitBranch: "main"}}},
+		{Name: "not-activated", Activation: []latest.Activation{{GitBranch: "feature"}}},
+		{Name: "also-activated", Activation: []latest.Activation{{GitBranch: "!feature"}}},
+	}
+
+	testutil.Run(t, "branch is looked up once", func(t *testutil.T) {
+		t.Override(&pkgutil.DefaultExecCommand, testutil.NewFakeCmd(t.T).
+			WithRunOut("git rev-parse --abbrev-ref HEAD", "main\n"))
+
+		activated, err := activatedProfiles(profiles, &cfg.SkaffoldOptions{})
+
+		t.CheckErrorAndDeepEqual(false, err, []string{"activated", "also-activated"}, profileNames(activated))
+	})
+
+	testutil.Run(t, "not a git repository", func(t *testutil.T) {
+		t.Override(&pkgutil.DefaultExecCommand, testutil.NewFakeCmd(t.T).
+			WithRunOutErr("git rev-parse --abbrev-ref HEAD", "", fmt.Errorf("not a git repository")))
+
+		activated, err := activatedProfiles(profiles, &cfg.SkaffoldOptions{})
+
+		t.CheckErrorAndDeepEqual(false, err, []string(nil), profileNames(activated))
+	})
+}
+
+func TestActivatedProfilesByPathRelativeToConfig(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().Write("project/local.yaml", "")
+
+		profiles := []latest.Profile{
+			{Name: "activated", Activation: []latest.Activation{{Path: "local.yaml"}}},
+			{Name: "not-activated", Activation: []latest.Activation{{Path: "!local.yaml"}}},
+		}
+		activated, err := activatedProfiles(profiles, &cfg.SkaffoldOptions{
+			ConfigurationFile: tmpDir.Path("project/skaffold.yaml"),
+		})
+
+		t.CheckErrorAndDeepEqual(false, err, []string{"activated"}, profileNames(activated))
+	})
+}
+
+func TestApplyProfilesWithReport(t *testing.T) {
+	config := &latest.SkaffoldConfig{
+		Pipeline: latest.Pipeline{
+			Build: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{ImageName: "image"}},
+			},
+		},
+		Profiles: []latest.Profile{
+			{
+				Name:       "dev-on-minikube",
+				Activation: []latest.Activation{{Command: "dev", AnyOf: []latest.Activation{{Env: "KEY=VALUE"}}}},
+				Activates:  []string{"local-registry"},
+			},
+			{
+				Name: "local-registry",
+				Patches: []latest.JSONPatch{
+					{Path: "/build/artifacts/0/image", Value: &util.YamlpatchNode{Node: *yamlpatch.NewNode(str("local/image"))}},
+					{Op: "remove", Path: "/build/artifacts/1"},
+				},
+			},
+		},
+	}
+
+	os.Setenv("KEY", "VALUE")
+	profiles, err := ApplyProfilesWithReport(config, &cfg.SkaffoldOptions{Command: "dev"})
+
+	testutil.CheckErrorContains(t, "applying profile local-registry", err)
+	testutil.CheckDeepEqual(t, []string{"dev-on-minikube", "local-registry"}, profileNames(profiles))
+	testutil.CheckDeepEqual(t, "activation triggered: command=dev, anyOf=[{env=KEY=VALUE}]", profiles[0].Reason)
+	testutil.CheckDeepEqual(t, "activated by profile dev-on-minikube", profiles[1].Reason)
+
+	patches := profiles[1].Patches
+	testutil.CheckDeepEqual(t, 2, len(patches))
+	testutil.CheckDeepEqual(t, "replace", patches[0].Op)
+	testutil.CheckError(t, false, patches[0].Err)
+	testutil.CheckDeepEqual(t, "remove", patches[1].Op)
+	testutil.CheckErrorContains(t, "invalid path: /build/artifacts/1", patches[1].Err)
+}
+
+func profileNames(profiles []ActivatedProfile) []string {
+	var names []string
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+	return names
+}
+
 func str(value string) *interface{} {
 	var v interface{} = value
 	return &v
@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta11
+
+import (
+	next "github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
+	pkgutil "github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+)
+
+// Upgrade upgrades a configuration to the next version.
+// Config changes from v1beta11 to v1beta12
+// 1. Additions:
+//    - Custom test runner `custom` in TestCase
+//    - Post-deploy `verify` phase
+//    - Profile activation by `namespace`, `path`, `gitBranch`, with `allOf` and `anyOf`
+//    - Profiles activating other profiles with `activates`
+//    - `renderTemplates` in HelmDeploy to render charts client-side
+//    - Chart `repositories` in HelmDeploy
+//    - `dependsOn` in HelmRelease to order releases
+//    - `templated` image strategy in HelmRelease
+//    - `buildArgs` in KustomizeDeploy
+//    - Global and per-artifact `watch` section to ignore files in dev mode
+//    - `quietPeriod`, `maxWait` and `minInterval` in the `watch` section
+//    - `platforms` in DockerArtifact, KanikoArtifact and CustomArtifact
+//    - `secrets`, `ssh` and `cacheTo` in DockerArtifact
+// 2. No removals
+// 3. Updates:
+//    - KustomizeDeploy `path` is replaced by a list of `paths`
+//    - `deploy` can contain several deployers
+func (config *SkaffoldConfig) Upgrade() (util.VersionedConfig, error) {
+	var newConfig next.SkaffoldConfig
+
+	if err := pkgutil.CloneThroughJSON(config, &newConfig); err != nil {
+		return nil, err
+	}
+	newConfig.APIVersion = next.Version
+
+	upgradeKustomizePath(&config.Deploy, &newConfig.Deploy)
+	for i, p := range config.Profiles {
+		upgradeKustomizePath(&p.Pipeline.Deploy, &newConfig.Profiles[i].Pipeline.Deploy)
+	}
+
+	return &newConfig, nil
+}
+
+func upgradeKustomizePath(oldDeploy *DeployConfig, newDeploy *next.DeployConfig) {
+	if oldDeploy.KustomizeDeploy == nil || oldDeploy.KustomizeDeploy.KustomizePath == "" {
+		return
+	}
+
+	newDeploy.KustomizeDeploy.KustomizePaths = []string{oldDeploy.KustomizeDeploy.KustomizePath}
+}
@@ -21,7 +21,10 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/yamltags"
 )
 
@@ -35,7 +38,12 @@ func Process(config *latest.SkaffoldConfig) error {
 	errs := visitStructs(config, validateYamltags)
 	errs = append(errs, validateDockerNetworkMode(config.Build.Artifacts)...)
 	errs = append(errs, validateCustomDependencies(config.Build.Artifacts)...)
+	errs = append(errs, validatePlatforms(config.Build)...)
+	errs = append(errs, validateBuildKitFeatures(config.Build)...)
 	errs = append(errs, validateSyncRules(config.Build.Artifacts)...)
+	errs = append(errs, validateVerifyTestCases(config.Verify)...)
+	errs = append(errs, validateHelmDependencies(config.Deploy.HelmDeploy)...)
+	errs = append(errs, validateHelmImageTemplates(config.Deploy.HelmDeploy)...)
 
 	if len(errs) == 0 {
 		return nil
@@ -79,6 +87,49 @@ func validateCustomDependencies(artifacts []*latest.Artifact) (errs []error) {
 	return
 }
 
+// validatePlatforms makes sure that platforms are in the `os/arch[/variant]` format
+// and that they are only used with builders that support them.
+func validatePlatforms(buildConfig latest.BuildConfig) (errs []error) {
+	for _, a := range buildConfig.Artifacts {
+		platforms := build.Platforms(a)
+		if len(platforms) == 0 {
+			continue
+		}
+		if buildConfig.GoogleCloudBuild != nil {
+			errs = append(errs, fmt.Errorf("artifact %s has platforms, which are not supported by Google Cloud Build", a.ImageName))
+			continue
+		}
+		for _, platform := range platforms {
+			if _, err := docker.ParsePlatform(platform); err != nil {
+				errs = append(errs, fmt.Errorf("artifact %s has %s", a.ImageName, err))
+			}
+		}
+	}
+	return
+}
+
+// validateBuildKitFeatures makes sure that `secrets`, `ssh` and `cacheTo`,
+// which require BuildKit, are not used with Google Cloud Build or with
+// in-cluster builds, where they would be ignored.
+func validateBuildKitFeatures(buildConfig latest.BuildConfig) (errs []error) {
+	var builder string
+	switch {
+	case buildConfig.GoogleCloudBuild != nil:
+		builder = "Google Cloud Build"
+	case buildConfig.Cluster != nil:
+		builder = "in-cluster builds"
+	default:
+		return
+	}
+
+	for _, a := range buildConfig.Artifacts {
+		if a.DockerArtifact != nil && docker.RequiresBuildKit(a.DockerArtifact) {
+			errs = append(errs, fmt.Errorf("artifact %s uses secrets, ssh or cacheTo, which are not supported by %s", a.ImageName, builder))
+		}
+	}
+	return
+}
+
 // visitStructs recursively visits all fields in the config and collects errors found by the visitor
 func visitStructs(s interface{}, visitor func(interface{}) error) []error {
 	v := reflect.ValueOf(s)
@@ -141,3 +192,77 @@ func validateSyncRules(artifacts []*latest.Artifact) []error {
 	}
 	return errs
 }
+
+// validateVerifyTestCases makes sure that verification tests have unique names.
+func validateVerifyTestCases(testCases []*latest.VerifyTestCase) (errs []error) {
+	seen := map[string]bool{}
+	for _, tc := range testCases {
+		if seen[tc.Name] {
+			errs = append(errs, fmt.Errorf("found duplicate verification test name %q", tc.Name))
+		}
+		seen[tc.Name] = true
+	}
+	return
+}
+
+// validateHelmDependencies makes sure that helm releases only depend on other declared releases.
+func validateHelmDependencies(helm *latest.HelmDeploy) (errs []error) {
+	if helm == nil {
+		return
+	}
+
+	names := map[string]bool{}
+	for _, r := range helm.Releases {
+		if names[r.Name] && hasHelmDependencies(helm.Releases) {
+			errs = append(errs, fmt.Errorf("found duplicate helm release name %q", r.Name))
+		}
+		names[r.Name] = true
+	}
+
+	for _, r := range helm.Releases {
+		for _, dependency := range r.DependsOn {
+			switch {
+			case dependency == r.Name:
+				errs = append(errs, fmt.Errorf("helm release %q depends on itself", r.Name))
+			case !names[dependency]:
+				errs = append(errs, fmt.Errorf("helm release %q depends on unknown release %q", r.Name, dependency))
+			}
+		}
+	}
+	return
+}
+
+// validateHelmImageTemplates makes sure that templated image values are valid templates
+// that only use known fields.
+func validateHelmImageTemplates(helm *latest.HelmDeploy) (errs []error) {
+	if helm == nil {
+		return
+	}
+
+	for _, r := range helm.Releases {
+		templated := r.ImageStrategy.HelmTemplatedConfig
+		if templated == nil {
+			continue
+		}
+
+		for path, value := range templated.Values {
+			if path == "" {
+				errs = append(errs, fmt.Errorf("helm release %q has a templated image value with an empty path", r.Name))
+				continue
+			}
+			if _, err := util.ParseImageTemplate(value); err != nil {
+				errs = append(errs, fmt.Errorf("helm release %q has an invalid template for image value %q: %s", r.Name, path, err))
+			}
+		}
+	}
+	return
+}
+
+func hasHelmDependencies(releases []latest.HelmRelease) bool {
+	for _, r := range releases {
+		if len(r.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -518,3 +518,240 @@ func TestValidateCustomDependencies(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePlatforms(t *testing.T) {
+	tests := []struct {
+		description    string
+		buildConfig    latest.BuildConfig
+		expectedErrors int
+	}{
+		{
+			description: "valid platforms",
+			buildConfig: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{
+					ArtifactType: latest.ArtifactType{
+						DockerArtifact: &latest.DockerArtifact{Platforms: []string{"linux/amd64", "linux/arm/v7"}},
+					},
+				}},
+			},
+		}, {
+			description: "invalid platforms",
+			buildConfig: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{
+					ArtifactType: latest.ArtifactType{
+						KanikoArtifact: &latest.KanikoArtifact{Platforms: []string{"amd64", "linux/"}},
+					},
+				}},
+			},
+			expectedErrors: 2,
+		}, {
+			description: "platforms with Google Cloud Build",
+			buildConfig: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{
+					ArtifactType: latest.ArtifactType{
+						DockerArtifact: &latest.DockerArtifact{Platforms: []string{"linux/amd64"}},
+					},
+				}},
+				BuildType: latest.BuildType{
+					GoogleCloudBuild: &latest.GoogleCloudBuild{},
+				},
+			},
+			expectedErrors: 1,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			errs := validatePlatforms(test.buildConfig)
+
+			t.CheckDeepEqual(test.expectedErrors, len(errs))
+		})
+	}
+}
+
+func TestValidateBuildKitFeatures(t *testing.T) {
+	tests := []struct {
+		description    string
+		buildConfig    latest.BuildConfig
+		expectedErrors int
+	}{
+		{
+			description: "local build",
+			buildConfig: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{
+					ArtifactType: latest.ArtifactType{
+						DockerArtifact: &latest.DockerArtifact{SSH: []string{"default"}},
+					},
+				}},
+			},
+		}, {
+			description: "Google Cloud Build without BuildKit features",
+			buildConfig: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{
+					ArtifactType: latest.ArtifactType{
+						DockerArtifact: &latest.DockerArtifact{CacheFrom: []string{"gcr.io/project/app"}},
+					},
+				}},
+				BuildType: latest.BuildType{
+					GoogleCloudBuild: &latest.GoogleCloudBuild{},
+				},
+			},
+		}, {
+			description: "Google Cloud Build with secrets",
+			buildConfig: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{
+					ArtifactType: latest.ArtifactType{
+						DockerArtifact: &latest.DockerArtifact{Secrets: []*latest.DockerSecret{{ID: "npmrc", Source: ".npmrc"}}},
+					},
+				}},
+				BuildType: latest.BuildType{
+					GoogleCloudBuild: &latest.GoogleCloudBuild{},
+				},
+			},
+			expectedErrors: 1,
+		}, {
+			description: "in-cluster build with cacheTo",
+			buildConfig: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{
+					ArtifactType: latest.ArtifactType{
+						DockerArtifact: &latest.DockerArtifact{CacheTo: []string{"type=inline"}},
+					},
+				}},
+				BuildType: latest.BuildType{
+					Cluster: &latest.ClusterDetails{},
+				},
+			},
+			expectedErrors: 1,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			errs := validateBuildKitFeatures(test.buildConfig)
+
+			t.CheckDeepEqual(test.expectedErrors, len(errs))
+		})
+	}
+}
+
+func TestValidateVerifyTestCases(t *testing.T) {
+	tests := []struct {
+		description    string
+		testCases      []*latest.VerifyTestCase
+		expectedErrors int
+	}{
+		{
+			description: "no verification tests",
+		}, {
+			description: "unique names",
+			testCases: []*latest.VerifyTestCase{
+				{Name: "integration", Image: "tests"},
+				{Name: "smoke", Image: "tests"},
+			},
+		}, {
+			description: "duplicate names",
+			testCases: []*latest.VerifyTestCase{
+				{Name: "integration", Image: "tests"},
+				{Name: "integration", Image: "other-tests"},
+			},
+			expectedErrors: 1,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			errs := validateVerifyTestCases(test.testCases)
+
+			t.CheckDeepEqual(test.expectedErrors, len(errs))
+		})
+	}
+}
+
+func TestValidateHelmDependencies(t *testing.T) {
+	tests := []struct {
+		description    string
+		helm           *latest.HelmDeploy
+		expectedErrors int
+	}{
+		{
+			description: "no helm deployer",
+		}, {
+			description: "known dependencies",
+			helm: &latest.HelmDeploy{
+				Releases: []latest.HelmRelease{
+					{Name: "db"},
+					{Name: "app", DependsOn: []string{"db"}},
+				},
+			},
+		}, {
+			description: "unknown dependency and self dependency",
+			helm: &latest.HelmDeploy{
+				Releases: []latest.HelmRelease{
+					{Name: "db", DependsOn: []string{"db"}},
+					{Name: "app", DependsOn: []string{"cache"}},
+				},
+			},
+			expectedErrors: 2,
+		}, {
+			description: "duplicate names with dependencies",
+			helm: &latest.HelmDeploy{
+				Releases: []latest.HelmRelease{
+					{Name: "db"},
+					{Name: "db"},
+					{Name: "app", DependsOn: []string{"db"}},
+				},
+			},
+			expectedErrors: 1,
+		}, {
+			description: "duplicate names without dependencies",
+			helm: &latest.HelmDeploy{
+				Releases: []latest.HelmRelease{{Name: "app"}, {Name: "app"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			errs := validateHelmDependencies(test.helm)
+
+			t.CheckDeepEqual(test.expectedErrors, len(errs))
+		})
+	}
+}
+
+func TestValidateHelmImageTemplates(t *testing.T) {
+	tests := []struct {
+		description    string
+		values         map[string]string
+		expectedErrors int
+	}{
+		{
+			description: "valid templates",
+			values: map[string]string{
+				"registry":   "{{.Registry}}",
+				"repository": "{{.Repository}}",
+				"digest":     "{{.Digest}}",
+			},
+		}, {
+			description:    "unknown field",
+			values:         map[string]string{"name": "{{.Name}}"},
+			expectedErrors: 1,
+		}, {
+			description:    "invalid template and empty path",
+			values:         map[string]string{"tag": "{{.Tag", "": "{{.Image}}"},
+			expectedErrors: 2,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			errs := validateHelmImageTemplates(&latest.HelmDeploy{
+				Releases: []latest.HelmRelease{{
+					Name: "release",
+					ImageStrategy: latest.HelmImageStrategy{
+						HelmImageConfig: latest.HelmImageConfig{
+							HelmTemplatedConfig: &latest.HelmTemplatedConfig{Values: test.values},
+						},
+					},
+				}},
+			})
+
+			t.CheckDeepEqual(test.expectedErrors, len(errs))
+		})
+	}
+}
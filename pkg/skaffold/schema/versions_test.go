@@ -286,23 +286,15 @@ func withDockerConfig(secretName string, path string) func(*latest.BuildConfig)
 
 func withKubectlDeploy(manifests ...string) func(*latest.SkaffoldConfig) {
 	return func(cfg *latest.SkaffoldConfig) {
-		cfg.Deploy = latest.DeployConfig{
-			DeployType: latest.DeployType{
-				KubectlDeploy: &latest.KubectlDeploy{
-					Manifests: manifests,
-				},
-			},
+		cfg.Deploy.KubectlDeploy = &latest.KubectlDeploy{
+			Manifests: manifests,
 		}
 	}
 }
 
 func withHelmDeploy() func(*latest.SkaffoldConfig) {
 	return func(cfg *latest.SkaffoldConfig) {
-		cfg.Deploy = latest.DeployConfig{
-			DeployType: latest.DeployType{
-				HelmDeploy: &latest.HelmDeploy{},
-			},
-		}
+		cfg.Deploy.HelmDeploy = &latest.HelmDeploy{}
 	}
 }
 
@@ -371,6 +363,12 @@ func withShaTagger() func(*latest.BuildConfig) {
 	return withTagPolicy(latest.TagPolicy{ShaTagger: &latest.ShaTagger{}})
 }
 
+func withWatchIgnore(patterns ...string) func(*latest.SkaffoldConfig) {
+	return func(cfg *latest.SkaffoldConfig) {
+		cfg.Watch.Ignore = patterns
+	}
+}
+
 func withProfiles(profiles ...latest.Profile) func(*latest.SkaffoldConfig) {
 	return func(cfg *latest.SkaffoldConfig) {
 		cfg.Profiles = profiles
@@ -148,6 +148,7 @@ type State struct {
 	BuildState           *BuildState           `protobuf:"bytes,1,opt,name=buildState,proto3" json:"buildState,omitempty"`
 	DeployState          *DeployState          `protobuf:"bytes,2,opt,name=deployState,proto3" json:"deployState,omitempty"`
 	ForwardedPorts       map[string]*PortEvent `protobuf:"bytes,3,rep,name=forwardedPorts,proto3" json:"forwardedPorts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	VerifyState          *VerifyState          `protobuf:"bytes,4,opt,name=verifyState,proto3" json:"verifyState,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
 	XXX_unrecognized     []byte                `json:"-"`
 	XXX_sizecache        int32                 `json:"-"`
@@ -199,6 +200,13 @@ func (m *State) GetForwardedPorts() map[string]*PortEvent {
 	return nil
 }
 
+func (m *State) GetVerifyState() *VerifyState {
+	if m != nil {
+		return m.VerifyState
+	}
+	return nil
+}
+
 // BuildState contains a map of all skaffold artifacts to their current build
 // states
 type BuildState struct {
@@ -280,12 +288,55 @@ func (m *DeployState) GetStatus() string {
 	return ""
 }
 
+// VerifyState contains a map of all verification tests to their current
+// states
+type VerifyState struct {
+	Tests                map[string]string `protobuf:"bytes,1,rep,name=tests,proto3" json:"tests,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *VerifyState) Reset()         { *m = VerifyState{} }
+func (m *VerifyState) String() string { return proto.CompactTextString(m) }
+func (*VerifyState) ProtoMessage()    {}
+func (*VerifyState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{6}
+}
+
+func (m *VerifyState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyState.Unmarshal(m, b)
+}
+func (m *VerifyState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyState.Marshal(b, m, deterministic)
+}
+func (m *VerifyState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyState.Merge(m, src)
+}
+func (m *VerifyState) XXX_Size() int {
+	return xxx_messageInfo_VerifyState.Size(m)
+}
+func (m *VerifyState) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyState proto.InternalMessageInfo
+
+func (m *VerifyState) GetTests() map[string]string {
+	if m != nil {
+		return m.Tests
+	}
+	return nil
+}
+
 type Event struct {
 	// Types that are valid to be assigned to EventType:
 	//	*Event_MetaEvent
 	//	*Event_BuildEvent
 	//	*Event_DeployEvent
 	//	*Event_PortEvent
+	//	*Event_VerifyEvent
+	//	*Event_TestEvent
 	EventType            isEvent_EventType `protobuf_oneof:"event_type"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
@@ -296,7 +347,7 @@ func (m *Event) Reset()         { *m = Event{} }
 func (m *Event) String() string { return proto.CompactTextString(m) }
 func (*Event) ProtoMessage()    {}
 func (*Event) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{6}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{7}
 }
 
 func (m *Event) XXX_Unmarshal(b []byte) error {
@@ -337,6 +388,14 @@ type Event_PortEvent struct {
 	PortEvent *PortEvent `protobuf:"bytes,4,opt,name=portEvent,proto3,oneof"`
 }
 
+type Event_VerifyEvent struct {
+	VerifyEvent *VerifyEvent `protobuf:"bytes,5,opt,name=verifyEvent,proto3,oneof"`
+}
+
+type Event_TestEvent struct {
+	TestEvent *TestEvent `protobuf:"bytes,6,opt,name=testEvent,proto3,oneof"`
+}
+
 func (*Event_MetaEvent) isEvent_EventType() {}
 
 func (*Event_BuildEvent) isEvent_EventType() {}
@@ -345,6 +404,10 @@ func (*Event_DeployEvent) isEvent_EventType() {}
 
 func (*Event_PortEvent) isEvent_EventType() {}
 
+func (*Event_VerifyEvent) isEvent_EventType() {}
+
+func (*Event_TestEvent) isEvent_EventType() {}
+
 func (m *Event) GetEventType() isEvent_EventType {
 	if m != nil {
 		return m.EventType
@@ -380,6 +443,20 @@ func (m *Event) GetPortEvent() *PortEvent {
 	return nil
 }
 
+func (m *Event) GetVerifyEvent() *VerifyEvent {
+	if x, ok := m.GetEventType().(*Event_VerifyEvent); ok {
+		return x.VerifyEvent
+	}
+	return nil
+}
+
+func (m *Event) GetTestEvent() *TestEvent {
+	if x, ok := m.GetEventType().(*Event_TestEvent); ok {
+		return x.TestEvent
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Event) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -387,6 +464,8 @@ func (*Event) XXX_OneofWrappers() []interface{} {
 		(*Event_BuildEvent)(nil),
 		(*Event_DeployEvent)(nil),
 		(*Event_PortEvent)(nil),
+		(*Event_VerifyEvent)(nil),
+		(*Event_TestEvent)(nil),
 	}
 }
 
@@ -401,7 +480,7 @@ func (m *MetaEvent) Reset()         { *m = MetaEvent{} }
 func (m *MetaEvent) String() string { return proto.CompactTextString(m) }
 func (*MetaEvent) ProtoMessage()    {}
 func (*MetaEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{7}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{8}
 }
 
 func (m *MetaEvent) XXX_Unmarshal(b []byte) error {
@@ -442,7 +521,7 @@ func (m *BuildEvent) Reset()         { *m = BuildEvent{} }
 func (m *BuildEvent) String() string { return proto.CompactTextString(m) }
 func (*BuildEvent) ProtoMessage()    {}
 func (*BuildEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{8}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{9}
 }
 
 func (m *BuildEvent) XXX_Unmarshal(b []byte) error {
@@ -487,6 +566,7 @@ func (m *BuildEvent) GetErr() string {
 type DeployEvent struct {
 	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
 	Err                  string   `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	Deployer             string   `protobuf:"bytes,3,opt,name=deployer,proto3" json:"deployer,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -496,7 +576,7 @@ func (m *DeployEvent) Reset()         { *m = DeployEvent{} }
 func (m *DeployEvent) String() string { return proto.CompactTextString(m) }
 func (*DeployEvent) ProtoMessage()    {}
 func (*DeployEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{9}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{10}
 }
 
 func (m *DeployEvent) XXX_Unmarshal(b []byte) error {
@@ -531,6 +611,13 @@ func (m *DeployEvent) GetErr() string {
 	return ""
 }
 
+func (m *DeployEvent) GetDeployer() string {
+	if m != nil {
+		return m.Deployer
+	}
+	return ""
+}
+
 type PortEvent struct {
 	LocalPort            int32    `protobuf:"varint,1,opt,name=localPort,proto3" json:"localPort,omitempty"`
 	RemotePort           int32    `protobuf:"varint,2,opt,name=remotePort,proto3" json:"remotePort,omitempty"`
@@ -547,7 +634,7 @@ func (m *PortEvent) Reset()         { *m = PortEvent{} }
 func (m *PortEvent) String() string { return proto.CompactTextString(m) }
 func (*PortEvent) ProtoMessage()    {}
 func (*PortEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{10}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{11}
 }
 
 func (m *PortEvent) XXX_Unmarshal(b []byte) error {
@@ -610,6 +697,133 @@ func (m *PortEvent) GetPortName() string {
 	return ""
 }
 
+type VerifyEvent struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status               string   `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Err                  string   `protobuf:"bytes,3,opt,name=err,proto3" json:"err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyEvent) Reset()         { *m = VerifyEvent{} }
+func (m *VerifyEvent) String() string { return proto.CompactTextString(m) }
+func (*VerifyEvent) ProtoMessage()    {}
+func (*VerifyEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{12}
+}
+
+func (m *VerifyEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyEvent.Unmarshal(m, b)
+}
+func (m *VerifyEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyEvent.Marshal(b, m, deterministic)
+}
+func (m *VerifyEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyEvent.Merge(m, src)
+}
+func (m *VerifyEvent) XXX_Size() int {
+	return xxx_messageInfo_VerifyEvent.Size(m)
+}
+func (m *VerifyEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyEvent proto.InternalMessageInfo
+
+func (m *VerifyEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *VerifyEvent) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *VerifyEvent) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
+// TestEvent describes the result of a single test run on an artifact
+type TestEvent struct {
+	Artifact             string   `protobuf:"bytes,1,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status               string   `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Err                  string   `protobuf:"bytes,4,opt,name=err,proto3" json:"err,omitempty"`
+	DurationMs           int64    `protobuf:"varint,5,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TestEvent) Reset()         { *m = TestEvent{} }
+func (m *TestEvent) String() string { return proto.CompactTextString(m) }
+func (*TestEvent) ProtoMessage()    {}
+func (*TestEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4f2d38e344f9dbf5, []int{13}
+}
+
+func (m *TestEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TestEvent.Unmarshal(m, b)
+}
+func (m *TestEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TestEvent.Marshal(b, m, deterministic)
+}
+func (m *TestEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TestEvent.Merge(m, src)
+}
+func (m *TestEvent) XXX_Size() int {
+	return xxx_messageInfo_TestEvent.Size(m)
+}
+func (m *TestEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_TestEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TestEvent proto.InternalMessageInfo
+
+func (m *TestEvent) GetArtifact() string {
+	if m != nil {
+		return m.Artifact
+	}
+	return ""
+}
+
+func (m *TestEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TestEvent) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *TestEvent) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
+func (m *TestEvent) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
+	}
+	return 0
+}
+
 type LogEntry struct {
 	Timestamp            *timestamp.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	Event                *Event               `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
@@ -623,7 +837,7 @@ func (m *LogEntry) Reset()         { *m = LogEntry{} }
 func (m *LogEntry) String() string { return proto.CompactTextString(m) }
 func (*LogEntry) ProtoMessage()    {}
 func (*LogEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4f2d38e344f9dbf5, []int{11}
+	return fileDescriptor_4f2d38e344f9dbf5, []int{14}
 }
 
 func (m *LogEntry) XXX_Unmarshal(b []byte) error {
@@ -674,66 +888,78 @@ func init() {
 	proto.RegisterType((*BuildState)(nil), "proto.BuildState")
 	proto.RegisterMapType((map[string]string)(nil), "proto.BuildState.ArtifactsEntry")
 	proto.RegisterType((*DeployState)(nil), "proto.DeployState")
+	proto.RegisterType((*VerifyState)(nil), "proto.VerifyState")
+	proto.RegisterMapType((map[string]string)(nil), "proto.VerifyState.TestsEntry")
 	proto.RegisterType((*Event)(nil), "proto.Event")
 	proto.RegisterType((*MetaEvent)(nil), "proto.MetaEvent")
 	proto.RegisterType((*BuildEvent)(nil), "proto.BuildEvent")
 	proto.RegisterType((*DeployEvent)(nil), "proto.DeployEvent")
 	proto.RegisterType((*PortEvent)(nil), "proto.PortEvent")
+	proto.RegisterType((*VerifyEvent)(nil), "proto.VerifyEvent")
+	proto.RegisterType((*TestEvent)(nil), "proto.TestEvent")
 	proto.RegisterType((*LogEntry)(nil), "proto.LogEntry")
 }
 
 func init() { proto.RegisterFile("skaffold.proto", fileDescriptor_4f2d38e344f9dbf5) }
 
 var fileDescriptor_4f2d38e344f9dbf5 = []byte{
-	// 754 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0xcf, 0x6e, 0xd3, 0x4e,
-	0x10, 0xfe, 0xd9, 0xa9, 0xd3, 0x78, 0xd2, 0xa6, 0xed, 0xfe, 0xaa, 0x2a, 0x32, 0x01, 0xca, 0x0a,
-	0x50, 0xd5, 0x43, 0xd2, 0x3f, 0x08, 0xaa, 0x0a, 0x21, 0x51, 0x5a, 0xe8, 0xa1, 0x45, 0xc8, 0xe1,
-	0x8e, 0xb6, 0xc9, 0x24, 0x44, 0x75, 0xbc, 0xc6, 0xde, 0x04, 0xe5, 0xc2, 0x81, 0x23, 0x17, 0x0e,
-	0x3c, 0x0d, 0x67, 0x1e, 0x81, 0x57, 0x40, 0x3c, 0x07, 0xda, 0x7f, 0xb1, 0xd3, 0x26, 0x27, 0x7b,
-	0x66, 0xbe, 0x6f, 0x76, 0x66, 0xbe, 0xdd, 0x81, 0x5a, 0x76, 0xcd, 0x7a, 0x3d, 0x1e, 0x75, 0x9b,
-	0x49, 0xca, 0x05, 0x27, 0x9e, 0xfa, 0x04, 0x8d, 0x3e, 0xe7, 0xfd, 0x08, 0x5b, 0x2c, 0x19, 0xb4,
-	0x58, 0x1c, 0x73, 0xc1, 0xc4, 0x80, 0xc7, 0x99, 0x06, 0x05, 0xf7, 0x4d, 0x54, 0x59, 0x57, 0xa3,
-	0x5e, 0x4b, 0x0c, 0x86, 0x98, 0x09, 0x36, 0x4c, 0x0c, 0xe0, 0xce, 0x4d, 0x00, 0x0e, 0x13, 0x31,
-	0xd1, 0x41, 0x7a, 0x08, 0xab, 0x6d, 0xc1, 0x04, 0x86, 0x98, 0x25, 0x3c, 0xce, 0x90, 0x50, 0xf0,
-	0x32, 0xe9, 0xa8, 0x3b, 0xdb, 0xce, 0x4e, 0xf5, 0x60, 0x45, 0xe3, 0x9a, 0x1a, 0xa4, 0x43, 0xb4,
-	0x01, 0x95, 0x29, 0x7e, 0x1d, 0x4a, 0xc3, 0xac, 0xaf, 0xd0, 0x7e, 0x28, 0x7f, 0xe9, 0x5d, 0x58,
-	0x0e, 0xf1, 0xd3, 0x08, 0x33, 0x41, 0x08, 0x2c, 0xc5, 0x6c, 0x88, 0x26, 0xaa, 0xfe, 0xe9, 0x77,
-	0x17, 0x3c, 0x95, 0x8d, 0xec, 0x03, 0x5c, 0x8d, 0x06, 0x51, 0xb7, 0x5d, 0x38, 0x6f, 0xc3, 0x9c,
-	0x77, 0x32, 0x0d, 0x84, 0x05, 0x10, 0x79, 0x02, 0xd5, 0x2e, 0x26, 0x11, 0x9f, 0x68, 0x8e, 0xab,
-	0x38, 0xc4, 0x70, 0x4e, 0xf3, 0x48, 0x58, 0x84, 0x91, 0x73, 0xa8, 0xf5, 0x78, 0xfa, 0x99, 0xa5,
-	0x5d, 0xec, 0xbe, 0xe3, 0xa9, 0xc8, 0xea, 0xa5, 0xed, 0xd2, 0x4e, 0xf5, 0x60, 0xbb, 0xd8, 0x5c,
-	0xf3, 0xf5, 0x0c, 0xe4, 0x2c, 0x16, 0xe9, 0x24, 0xbc, 0xc1, 0x0b, 0xda, 0xf0, 0xff, 0x1c, 0x98,
-	0x1c, 0xc2, 0x35, 0x4e, 0xec, 0x10, 0xae, 0x71, 0x42, 0x1e, 0x83, 0x37, 0x66, 0xd1, 0xc8, 0x96,
-	0xb8, 0x6e, 0x4e, 0x92, 0x9c, 0xb3, 0x31, 0xc6, 0x22, 0xd4, 0xe1, 0x63, 0xf7, 0xc8, 0xa1, 0xdf,
-	0x1c, 0x80, 0xbc, 0x5f, 0xf2, 0x02, 0x7c, 0x96, 0x8a, 0x41, 0x8f, 0x75, 0x44, 0x56, 0x77, 0x66,
-	0x0a, 0xcd, 0x51, 0xcd, 0x97, 0x16, 0xa2, 0x0b, 0xcd, 0x29, 0xc1, 0x73, 0xa8, 0xcd, 0x06, 0xe7,
-	0x94, 0xb7, 0x59, 0x2c, 0xcf, 0x2f, 0x16, 0xf3, 0x08, 0xaa, 0x85, 0x39, 0x92, 0x2d, 0x28, 0x4b,
-	0xcd, 0x47, 0x99, 0x61, 0x1b, 0x8b, 0xfe, 0x75, 0xc0, 0x53, 0x8d, 0x90, 0x3d, 0xf0, 0x87, 0x28,
-	0x98, 0x32, 0x8c, 0x88, 0xb6, 0xdb, 0x4b, 0xeb, 0x3f, 0xff, 0x2f, 0xcc, 0x41, 0xe4, 0xd0, 0xe8,
-	0xae, 0x29, 0xee, 0x6d, 0xdd, 0x2d, 0xa7, 0x00, 0x23, 0x4f, 0xad, 0xf2, 0x9a, 0x55, 0x9a, 0xa3,
-	0xbc, 0xa5, 0x15, 0x81, 0xb2, 0xbc, 0xc4, 0x0e, 0xbd, 0xbe, 0x34, 0x5f, 0x0c, 0x59, 0xde, 0x14,
-	0x74, 0xb2, 0x02, 0x80, 0xf2, 0xe7, 0x83, 0x98, 0x24, 0x48, 0x1f, 0x80, 0x3f, 0x6d, 0x43, 0x8e,
-	0x0d, 0xe5, 0x44, 0xcd, 0x30, 0xb4, 0x41, 0x43, 0x23, 0x9f, 0xc6, 0x04, 0x50, 0xb1, 0x5a, 0x18,
-	0xd8, 0xd4, 0x2e, 0x4c, 0xd3, 0x2d, 0x4e, 0x53, 0x0a, 0x84, 0x69, 0xaa, 0x9a, 0xf2, 0x43, 0xf9,
-	0x4b, 0x9f, 0x59, 0x19, 0x74, 0xd2, 0x05, 0x32, 0x58, 0xa2, 0x9b, 0x13, 0x7f, 0x39, 0xe0, 0x4f,
-	0x1b, 0x23, 0x0d, 0xf0, 0x23, 0xde, 0x61, 0x91, 0xf4, 0x28, 0xaa, 0x17, 0xe6, 0x0e, 0x72, 0x0f,
-	0x20, 0xc5, 0x21, 0x17, 0xa8, 0xc2, 0xae, 0x0a, 0x17, 0x3c, 0xa4, 0x0e, 0xcb, 0x09, 0xef, 0xbe,
-	0x95, 0x2f, 0x58, 0x97, 0x66, 0x4d, 0xf2, 0x10, 0x56, 0x3b, 0x3c, 0x16, 0x6c, 0x10, 0x63, 0xaa,
-	0xe2, 0x4b, 0x2a, 0x3e, 0xeb, 0x94, 0xa7, 0xcb, 0x27, 0x9f, 0x25, 0xac, 0x83, 0x75, 0x4f, 0x21,
-	0x72, 0x87, 0x1c, 0x94, 0x1c, 0xba, 0xa2, 0x97, 0xf5, 0xa0, 0xac, 0x4d, 0xbf, 0x40, 0xe5, 0x82,
-	0xf7, 0xf5, 0xed, 0x3d, 0x02, 0x7f, 0xba, 0xd2, 0xcc, 0x05, 0x0b, 0x9a, 0x7a, 0xa7, 0x35, 0xed,
-	0x4e, 0x6b, 0xbe, 0xb7, 0x88, 0x30, 0x07, 0xcb, 0x5d, 0x86, 0x85, 0x3b, 0x66, 0x77, 0x99, 0x79,
-	0x80, 0x38, 0x2b, 0x69, 0xa9, 0x20, 0xe9, 0xc1, 0x4f, 0x17, 0xd6, 0xda, 0x66, 0x19, 0xb7, 0x31,
-	0x1d, 0x0f, 0x3a, 0x48, 0x5e, 0x41, 0xe5, 0x0d, 0x0a, 0xf3, 0x2c, 0x6e, 0x15, 0x70, 0x26, 0x97,
-	0x6a, 0x30, 0xb3, 0x2e, 0xe9, 0xc6, 0xd7, 0xdf, 0x7f, 0x7e, 0xb8, 0x55, 0xe2, 0xb7, 0xc6, 0xfb,
-	0x2d, 0xb5, 0x3a, 0xc9, 0x29, 0x54, 0xd4, 0xf1, 0x17, 0xbc, 0x4f, 0xd6, 0x0c, 0xd8, 0x76, 0x1a,
-	0xdc, 0x74, 0x50, 0xa2, 0x12, 0xac, 0x10, 0x90, 0x09, 0x54, 0xbd, 0xd9, 0x8e, 0xb3, 0xe7, 0x90,
-	0x0b, 0x28, 0x9f, 0xb3, 0xb8, 0x1b, 0x21, 0x99, 0xe9, 0x29, 0x58, 0x50, 0x16, 0x6d, 0xa8, 0x3c,
-	0x5b, 0x74, 0x23, 0xcf, 0xd3, 0xfa, 0xa8, 0x12, 0x1c, 0x3b, 0xbb, 0xe4, 0x12, 0x3c, 0x75, 0x7f,
-	0x17, 0x76, 0xb5, 0x28, 0xed, 0xa6, 0x4a, 0x5b, 0xa3, 0xaa, 0x3f, 0xf5, 0x56, 0x8f, 0x9d, 0xdd,
-	0xab, 0xb2, 0x42, 0x1d, 0xfe, 0x0b, 0x00, 0x00, 0xff, 0xff, 0xa9, 0x56, 0xe0, 0xb5, 0xce, 0x06,
-	0x00, 0x00,
+	// 887 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x4f, 0x6f, 0xe3, 0x44,
+	0x14, 0xc7, 0x4e, 0x9c, 0x8d, 0x5f, 0xba, 0xdd, 0xed, 0xb0, 0x5a, 0x45, 0xa6, 0xbb, 0x94, 0x11,
+	0xa0, 0x6a, 0x0f, 0xc9, 0x6e, 0x8b, 0x50, 0x55, 0x21, 0x24, 0x96, 0x2d, 0x54, 0xa2, 0x45, 0xc8,
+	0xa9, 0xb8, 0xa2, 0x69, 0xf2, 0x12, 0xa2, 0x3a, 0x1e, 0xe3, 0x99, 0x04, 0x45, 0x42, 0x1c, 0x90,
+	0xb8, 0x70, 0xe5, 0xd3, 0x70, 0x46, 0xdc, 0x91, 0xf8, 0x0a, 0x7c, 0x10, 0x34, 0xff, 0xec, 0x71,
+	0x9b, 0x20, 0xf5, 0x64, 0xcf, 0x7b, 0xbf, 0xf7, 0xff, 0x37, 0xf3, 0x60, 0x57, 0xdc, 0xb0, 0xe9,
+	0x94, 0x67, 0x93, 0x41, 0x51, 0x72, 0xc9, 0x49, 0xa4, 0x3f, 0xc9, 0xfe, 0x8c, 0xf3, 0x59, 0x86,
+	0x43, 0x56, 0xcc, 0x87, 0x2c, 0xcf, 0xb9, 0x64, 0x72, 0xce, 0x73, 0x61, 0x40, 0xc9, 0xbb, 0x56,
+	0xab, 0x4f, 0xd7, 0xcb, 0xe9, 0x50, 0xce, 0x17, 0x28, 0x24, 0x5b, 0x14, 0x16, 0xf0, 0xce, 0x6d,
+	0x00, 0x2e, 0x0a, 0xb9, 0x36, 0x4a, 0x7a, 0x0c, 0x0f, 0x47, 0x92, 0x49, 0x4c, 0x51, 0x14, 0x3c,
+	0x17, 0x48, 0x28, 0x44, 0x42, 0x09, 0xfa, 0xc1, 0x41, 0x70, 0xd8, 0x3b, 0xda, 0x31, 0xb8, 0x81,
+	0x01, 0x19, 0x15, 0xdd, 0x87, 0x6e, 0x85, 0x7f, 0x0c, 0xad, 0x85, 0x98, 0x69, 0x74, 0x9c, 0xaa,
+	0x5f, 0xfa, 0x0c, 0x1e, 0xa4, 0xf8, 0xc3, 0x12, 0x85, 0x24, 0x04, 0xda, 0x39, 0x5b, 0xa0, 0xd5,
+	0xea, 0x7f, 0xfa, 0x57, 0x08, 0x91, 0xf6, 0x46, 0x5e, 0x01, 0x5c, 0x2f, 0xe7, 0xd9, 0x64, 0xe4,
+	0xc5, 0xdb, 0xb3, 0xf1, 0x5e, 0x57, 0x8a, 0xd4, 0x03, 0x91, 0x8f, 0xa0, 0x37, 0xc1, 0x22, 0xe3,
+	0x6b, 0x63, 0x13, 0x6a, 0x1b, 0x62, 0x6d, 0xde, 0xd4, 0x9a, 0xd4, 0x87, 0x91, 0x73, 0xd8, 0x9d,
+	0xf2, 0xf2, 0x47, 0x56, 0x4e, 0x70, 0xf2, 0x0d, 0x2f, 0xa5, 0xe8, 0xb7, 0x0e, 0x5a, 0x87, 0xbd,
+	0xa3, 0x03, 0xbf, 0xb8, 0xc1, 0x17, 0x0d, 0xc8, 0x59, 0x2e, 0xcb, 0x75, 0x7a, 0xcb, 0x4e, 0xc5,
+	0x5f, 0x61, 0x39, 0x9f, 0xda, 0xf8, 0xed, 0x46, 0xfc, 0x6f, 0x6b, 0x4d, 0xea, 0xc3, 0x92, 0x11,
+	0xbc, 0xbd, 0xc1, 0xb9, 0x6a, 0xdd, 0x0d, 0xae, 0x5d, 0xeb, 0x6e, 0x70, 0x4d, 0x3e, 0x84, 0x68,
+	0xc5, 0xb2, 0xa5, 0x2b, 0xec, 0xb1, 0x75, 0xac, 0x6c, 0xce, 0x56, 0x98, 0xcb, 0xd4, 0xa8, 0x4f,
+	0xc3, 0x93, 0x80, 0xfe, 0x16, 0x00, 0xd4, 0x5d, 0x22, 0x9f, 0x42, 0xcc, 0x4a, 0x39, 0x9f, 0xb2,
+	0xb1, 0x14, 0xfd, 0xa0, 0x51, 0x5e, 0x8d, 0x1a, 0x7c, 0xe6, 0x20, 0xa6, 0xbc, 0xda, 0x24, 0xf9,
+	0x04, 0x76, 0x9b, 0xca, 0x0d, 0xe9, 0x3d, 0xf1, 0xd3, 0x8b, 0xfd, 0x64, 0x3e, 0x80, 0x9e, 0xd7,
+	0x7d, 0xf2, 0x14, 0x3a, 0x8a, 0x29, 0x4b, 0x61, 0xad, 0xed, 0x89, 0xfe, 0x04, 0x3d, 0xaf, 0x49,
+	0xe4, 0x18, 0x22, 0x89, 0xa2, 0xca, 0xf7, 0xd9, 0xdd, 0x3e, 0x0e, 0xae, 0x50, 0xd8, 0x7c, 0x52,
+	0x83, 0x4d, 0x4e, 0x00, 0x6a, 0xe1, 0xbd, 0x92, 0xfc, 0x3b, 0x84, 0x48, 0xb7, 0x91, 0xbc, 0x84,
+	0x78, 0x81, 0x92, 0xe9, 0x43, 0x3f, 0x68, 0xf4, 0xfa, 0xd2, 0xc9, 0xcf, 0xdf, 0x4a, 0x6b, 0x10,
+	0x39, 0xb6, 0x5c, 0x35, 0x26, 0xe1, 0x5d, 0xae, 0x3a, 0x1b, 0x0f, 0x46, 0x3e, 0x76, 0x6c, 0x35,
+	0x56, 0xad, 0x0d, 0x6c, 0x75, 0x66, 0x3e, 0x50, 0xa5, 0x57, 0xb8, 0x91, 0xf7, 0xdb, 0x8d, 0xf4,
+	0x2a, 0x2a, 0xa8, 0xf4, 0x2a, 0x90, 0x8a, 0x64, 0x08, 0x67, 0x6c, 0xa2, 0x0d, 0xbc, 0xac, 0x22,
+	0x79, 0x40, 0x15, 0x49, 0x75, 0xd5, 0x58, 0x75, 0x1a, 0x91, 0xae, 0x50, 0xd4, 0x91, 0x2a, 0xd0,
+	0xeb, 0x1d, 0x00, 0x54, 0x3f, 0xdf, 0xc9, 0x75, 0x81, 0xf4, 0x3d, 0x88, 0xab, 0x86, 0xa9, 0xce,
+	0xa3, 0x1a, 0x8a, 0x9d, 0x86, 0x39, 0xd0, 0xd4, 0xd2, 0xd4, 0x60, 0x12, 0xe8, 0x3a, 0xce, 0x59,
+	0x58, 0x75, 0xf6, 0x58, 0x13, 0xfa, 0xac, 0x51, 0x33, 0xc6, 0xb2, 0xd4, 0xed, 0x8b, 0x53, 0xf5,
+	0x4b, 0x47, 0x8e, 0x6e, 0xc6, 0xe9, 0x16, 0xba, 0x39, 0xc3, 0xb0, 0x32, 0x54, 0xe1, 0x4d, 0xa3,
+	0xd1, 0xf9, 0xab, 0xce, 0xf4, 0xcf, 0x00, 0xe2, 0xaa, 0xbd, 0x64, 0x1f, 0xe2, 0x8c, 0x8f, 0x59,
+	0xa6, 0x24, 0xda, 0x6d, 0x94, 0xd6, 0x02, 0xf2, 0x1c, 0xa0, 0xc4, 0x05, 0x97, 0xa8, 0xd5, 0xa1,
+	0x56, 0x7b, 0x12, 0xd2, 0x87, 0x07, 0x05, 0x9f, 0x7c, 0xad, 0xde, 0x3e, 0x13, 0xc6, 0x1d, 0xc9,
+	0xfb, 0xf0, 0x70, 0xcc, 0x73, 0xc9, 0xe6, 0x39, 0x96, 0x5a, 0xdf, 0xd6, 0xfa, 0xa6, 0x50, 0x45,
+	0x57, 0x8f, 0xa5, 0x28, 0xd8, 0x18, 0xf5, 0x34, 0xe3, 0xb4, 0x16, 0xa8, 0x2a, 0xd4, 0xe8, 0xb5,
+	0x79, 0xc7, 0x54, 0xe1, 0xce, 0xf4, 0x2b, 0x77, 0xc5, 0x4c, 0x19, 0x1b, 0x5e, 0xe0, 0x7b, 0xf4,
+	0xf9, 0xd7, 0x00, 0xe2, 0x8a, 0x07, 0xff, 0x3b, 0x3b, 0x17, 0x27, 0xdc, 0x18, 0xa7, 0xb5, 0x29,
+	0x4e, 0xbb, 0x1e, 0xcb, 0x73, 0x80, 0xc9, 0xb2, 0xd4, 0x6b, 0xed, 0x52, 0xe8, 0x7a, 0x5b, 0xa9,
+	0x27, 0xa1, 0x3f, 0x43, 0xf7, 0x82, 0xcf, 0xcc, 0x8d, 0x3f, 0x81, 0xb8, 0xda, 0x70, 0xf6, 0xee,
+	0x26, 0x03, 0xb3, 0xe2, 0x06, 0x6e, 0xc5, 0x0d, 0xae, 0x1c, 0x22, 0xad, 0xc1, 0x6a, 0xb5, 0xa1,
+	0x77, 0x7d, 0xdd, 0x6a, 0xb3, 0x2f, 0x2b, 0x36, 0x39, 0xdc, 0xf2, 0x38, 0x7c, 0xf4, 0x47, 0x08,
+	0x8f, 0x46, 0x76, 0x37, 0x8f, 0xb0, 0x5c, 0xcd, 0xc7, 0x48, 0x3e, 0x87, 0xee, 0x97, 0x28, 0xed,
+	0x7b, 0x77, 0x27, 0x81, 0x33, 0xb5, 0x63, 0x93, 0xc6, 0xf6, 0xa4, 0x7b, 0xbf, 0xfc, 0xf3, 0xef,
+	0xef, 0x61, 0x8f, 0xc4, 0xc3, 0xd5, 0xab, 0xa1, 0xde, 0xa4, 0xe4, 0x0d, 0x74, 0x75, 0xf8, 0x0b,
+	0x3e, 0x23, 0x8f, 0x2c, 0xd8, 0x55, 0x9a, 0xdc, 0x16, 0x50, 0xa2, 0x1d, 0xec, 0x10, 0x50, 0x0e,
+	0x74, 0xbe, 0xe2, 0x30, 0x78, 0x19, 0x90, 0x0b, 0xe8, 0x9c, 0xb3, 0x7c, 0x92, 0x21, 0x69, 0xd4,
+	0x94, 0x6c, 0x49, 0x8b, 0xee, 0x6b, 0x3f, 0x4f, 0xe9, 0x5e, 0xed, 0x67, 0xf8, 0xbd, 0x76, 0x70,
+	0x1a, 0xbc, 0x20, 0x97, 0x10, 0xe9, 0x0b, 0xbb, 0xb5, 0xaa, 0x6d, 0x6e, 0x9f, 0x68, 0xb7, 0xbb,
+	0x54, 0xd7, 0xa7, 0x9f, 0xc1, 0xd3, 0xe0, 0xc5, 0x75, 0x47, 0xa3, 0x8e, 0xff, 0x1b, 0x00, 0x15,
+	0x4c, 0xce, 0xc6, 0xdd, 0x08, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -91,6 +91,51 @@ func NewItem(a *latest.Artifact, e watch.Events, builds []build.Artifact, insecu
 	}, nil
 }
 
+// NewFullItem creates an Item that copies all the given files that match the
+// sync rules of an artifact. Files that don't match any rule are skipped.
+func NewFullItem(a *latest.Artifact, files []string, builds []build.Artifact, insecureRegistries map[string]bool) (*Item, error) {
+	if a.Sync == nil || len(a.Sync.Manual) == 0 {
+		return nil, nil
+	}
+
+	tag := latestTag(a.ImageName, builds)
+	if tag == "" {
+		return nil, fmt.Errorf("could not find latest tag for image %s in builds: %v", a.ImageName, builds)
+	}
+
+	containerWd, err := WorkingDir(tag, insecureRegistries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "retrieving working dir for %s", tag)
+	}
+
+	toCopy := make(map[string][]string)
+	for _, f := range files {
+		relPath, err := filepath.Rel(a.Workspace, f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "file %s can't be found relative to context %s", f, a.Workspace)
+		}
+
+		dsts, err := matchSyncRules(a.Sync.Manual, relPath, containerWd)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(dsts) > 0 {
+			toCopy[f] = dsts
+		}
+	}
+
+	if len(toCopy) == 0 {
+		return nil, nil
+	}
+
+	return &Item{
+		Image:  tag,
+		Copy:   toCopy,
+		Delete: map[string][]string{},
+	}, nil
+}
+
 func retrieveWorkingDir(tagged string, insecureRegistries map[string]bool) (string, error) {
 	var cf *registry_v1.ConfigFile
 	var err error
@@ -397,6 +397,79 @@ func TestNewSyncItem(t *testing.T) {
 	}
 }
 
+func TestNewFullSyncItem(t *testing.T) {
+	var tests = []struct {
+		description string
+		artifact    *latest.Artifact
+		files       []string
+		builds      []build.Artifact
+		shouldErr   bool
+		expected    *Item
+	}{
+		{
+			description: "copy matching files only",
+			artifact: &latest.Artifact{
+				ImageName: "test",
+				Sync: &latest.Sync{
+					Manual: []*latest.SyncRule{{Src: "*.html", Dest: "."}},
+				},
+				Workspace: ".",
+			},
+			files:  []string{"index.html", "main.go", "about.html"},
+			builds: []build.Artifact{{ImageName: "test", Tag: "test:123"}},
+			expected: &Item{
+				Image: "test:123",
+				Copy: map[string][]string{
+					"index.html": {"/app/index.html"},
+					"about.html": {"/app/about.html"},
+				},
+				Delete: map[string][]string{},
+			},
+		},
+		{
+			description: "no matching file",
+			artifact: &latest.Artifact{
+				ImageName: "test",
+				Sync: &latest.Sync{
+					Manual: []*latest.SyncRule{{Src: "*.html", Dest: "."}},
+				},
+				Workspace: ".",
+			},
+			files:  []string{"main.go"},
+			builds: []build.Artifact{{ImageName: "test", Tag: "test:123"}},
+		},
+		{
+			description: "no sync rules",
+			artifact:    &latest.Artifact{ImageName: "test", Workspace: "."},
+			files:       []string{"index.html"},
+			builds:      []build.Artifact{{ImageName: "test", Tag: "test:123"}},
+		},
+		{
+			description: "not built yet",
+			artifact: &latest.Artifact{
+				ImageName: "test",
+				Sync: &latest.Sync{
+					Manual: []*latest.SyncRule{{Src: "*.html", Dest: "."}},
+				},
+				Workspace: ".",
+			},
+			files:     []string{"index.html"},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&WorkingDir, func(string, map[string]bool) (string, error) {
+				return "/app", nil
+			})
+
+			actual, err := NewFullItem(test.artifact, test.files, test.builds, map[string]bool{})
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, actual)
+		})
+	}
+}
+
 func TestIntersect(t *testing.T) {
 	var tests = []struct {
 		description string
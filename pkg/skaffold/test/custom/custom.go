@@ -0,0 +1,124 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Test is the entrypoint for running custom tests
+func (tr *Runner) Test(ctx context.Context, out io.Writer, image string) error {
+	logrus.Infof("Running custom test command %q", tr.customTest.Command)
+
+	if tr.customTest.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(tr.customTest.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	cmd := shellCommand(ctx, tr.customTest.Command)
+	cmd.Env = tr.retrieveEnv(image)
+	cmd.Dir = tr.workingDir
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	if err := util.RunCmd(cmd); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("custom test command %q timed out after %ds", tr.customTest.Command, tr.customTest.TimeoutSeconds)
+		}
+		return errors.Wrapf(err, "running custom test command %q", tr.customTest.Command)
+	}
+
+	return nil
+}
+
+// TestDependencies returns the files that custom test depends on.
+func (tr *Runner) TestDependencies() ([]string, error) {
+	deps := tr.customTest.Dependencies
+	if deps == nil {
+		return nil, nil
+	}
+
+	if deps.Command != "" {
+		cmd := shellCommand(context.Background(), deps.Command)
+		cmd.Dir = tr.workingDir
+		output, err := util.RunCmdOut(cmd)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting dependencies from command: %s", deps.Command)
+		}
+
+		var files []string
+		if err := json.Unmarshal(output, &files); err != nil {
+			return nil, errors.Wrap(err, "unmarshalling dependency output into string array")
+		}
+
+		return tr.absolute(files), nil
+	}
+
+	files, err := docker.WalkWorkspace(tr.workingDir, deps.Ignore, deps.Paths)
+	if err != nil {
+		return nil, errors.Wrapf(err, "walking workspace %s", tr.workingDir)
+	}
+
+	var dependencies []string
+	for file := range files {
+		dependencies = append(dependencies, file)
+	}
+	sort.Strings(dependencies)
+
+	return tr.absolute(dependencies), nil
+}
+
+func (tr *Runner) retrieveEnv(image string) []string {
+	// Later values take precedence, so the user defined env comes last.
+	envs := util.OSEnviron()
+	envs = append(envs, fmt.Sprintf("%s=%s", constants.Image, image))
+	envs = append(envs, tr.customTest.Env...)
+	return envs
+}
+
+func (tr *Runner) absolute(files []string) []string {
+	var abs []string
+	for _, file := range files {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(tr.workingDir, file)
+		}
+		abs = append(abs, file)
+	}
+	return abs
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd.exe", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
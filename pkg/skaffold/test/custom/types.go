@@ -0,0 +1,32 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package custom
+
+import "github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+
+type Runner struct {
+	customTest *latest.CustomTest
+	workingDir string
+}
+
+// NewRunner creates a new custom.Runner.
+func NewRunner(customTest *latest.CustomTest, workingDir string) *Runner {
+	return &Runner{
+		customTest: customTest,
+		workingDir: workingDir,
+	}
+}
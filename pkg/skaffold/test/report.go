@@ -0,0 +1,170 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// JUnitReport is the name of the JUnit XML report written in the report directory.
+	JUnitReport = "junit.xml"
+
+	// JSONReport is the name of the JSON report written in the report directory.
+	JSONReport = "test-report.json"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     float64         `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+type jsonReport struct {
+	Passed int          `json:"passed"`
+	Failed int          `json:"failed"`
+	Tests  []jsonResult `json:"tests"`
+}
+
+type jsonResult struct {
+	Artifact        string  `json:"artifact"`
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Passed          bool    `json:"passed"`
+	Error           string  `json:"error,omitempty"`
+	Output          string  `json:"output,omitempty"`
+}
+
+// writeReports writes both the JUnit XML and the JSON reports into the given directory.
+func writeReports(dir string, results []Result) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "creating report directory %s", dir)
+	}
+
+	junit, err := xml.MarshalIndent(junitReport(results), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling junit report")
+	}
+	junit = append([]byte(xml.Header), junit...)
+	if err := ioutil.WriteFile(filepath.Join(dir, JUnitReport), junit, 0644); err != nil {
+		return errors.Wrap(err, "writing junit report")
+	}
+
+	report, err := json.MarshalIndent(newJSONReport(results), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling json report")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, JSONReport), report, 0644); err != nil {
+		return errors.Wrap(err, "writing json report")
+	}
+
+	return nil
+}
+
+// junitReport groups the results in one test suite per artifact,
+// keeping the order in which the artifacts were tested.
+func junitReport(results []Result) junitTestSuites {
+	var report junitTestSuites
+	suites := map[string]int{}
+
+	for _, result := range results {
+		i, found := suites[result.Artifact]
+		if !found {
+			i = len(report.Suites)
+			suites[result.Artifact] = i
+			report.Suites = append(report.Suites, junitTestSuite{Name: result.Artifact})
+		}
+
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: result.Artifact,
+			Time:      result.Duration.Seconds(),
+			SystemOut: result.Output,
+		}
+
+		suite := &report.Suites[i]
+		suite.Tests++
+		suite.Time += testCase.Time
+		report.Tests++
+		report.Time += testCase.Time
+
+		if result.Err != nil {
+			testCase.Failure = &junitFailure{Message: result.Err.Error()}
+			suite.Failures++
+			report.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	return report
+}
+
+func newJSONReport(results []Result) jsonReport {
+	report := jsonReport{
+		Tests: []jsonResult{},
+	}
+
+	for _, result := range results {
+		r := jsonResult{
+			Artifact:        result.Artifact,
+			Name:            result.Name,
+			DurationSeconds: result.Duration.Seconds(),
+			Passed:          result.Err == nil,
+			Output:          result.Output,
+		}
+
+		if result.Err != nil {
+			r.Error = result.Err.Error()
+			report.Failed++
+		} else {
+			report.Passed++
+		}
+
+		report.Tests = append(report.Tests, r)
+	}
+
+	return report
+}
@@ -17,12 +17,18 @@ limitations under the License.
 package test
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/custom"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/test/structure"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 
@@ -36,6 +42,7 @@ func NewTester(runCtx *runcontext.RunContext) Tester {
 	return FullTester{
 		testCases:  runCtx.Cfg.Test,
 		workingDir: runCtx.WorkingDir,
+		reportDir:  runCtx.Opts.TestReportDir,
 	}
 }
 
@@ -50,37 +57,116 @@ func (t FullTester) TestDependencies() ([]string, error) {
 		}
 
 		deps = append(deps, files...)
+
+		for _, customTest := range test.CustomTests {
+			files, err := custom.NewRunner(customTest, t.workingDir).TestDependencies()
+			if err != nil {
+				return nil, errors.Wrap(err, "getting custom test dependencies")
+			}
+
+			deps = append(deps, files...)
+		}
 	}
 
 	return deps, nil
 }
 
 // Test is the top level testing execution call. It serves as the
-// entrypoint to all individual tests.
+// entrypoint to all individual tests. Every test is run, even when
+// some of them fail, so that the reports cover the whole test phase.
 func (t FullTester) Test(ctx context.Context, out io.Writer, bRes []build.Artifact) error {
-	for _, test := range t.testCases {
-		if err := t.runStructureTests(ctx, out, bRes, test); err != nil {
-			return errors.Wrap(err, "running structure tests")
+	var results []Result
+
+	for _, testCase := range t.testCases {
+		// A test case that can't be set up counts as a failed test.
+		runners, err := t.runners(testCase)
+		if err != nil {
+			name := fmt.Sprintf("tests of %s", testCase.ImageName)
+			event.TestFailed(testCase.ImageName, name, 0, err)
+			results = append(results, Result{Artifact: testCase.ImageName, Name: name, Err: err})
+			continue
+		}
+
+		fqn := resolveArtifactImageTag(testCase.ImageName, bRes)
+		for _, r := range runners {
+			results = append(results, runTest(ctx, out, testCase.ImageName, fqn, r))
 		}
 	}
 
-	return nil
+	if t.reportDir != "" {
+		if err := writeReports(t.reportDir, results); err != nil {
+			return errors.Wrap(err, "writing test reports")
+		}
+	}
+
+	return failures(results)
 }
 
-func (t FullTester) runStructureTests(ctx context.Context, out io.Writer, bRes []build.Artifact, testCase *latest.TestCase) error {
-	if len(testCase.StructureTests) == 0 {
-		return nil
+// runners lists the test runners configured for a given test case.
+func (t FullTester) runners(testCase *latest.TestCase) ([]namedRunner, error) {
+	var runners []namedRunner
+
+	if len(testCase.StructureTests) > 0 {
+		files, err := util.ExpandPathsGlob(t.workingDir, testCase.StructureTests)
+		if err != nil {
+			return nil, errors.Wrap(err, "expanding test file paths")
+		}
+
+		runners = append(runners, namedRunner{
+			name:   fmt.Sprintf("structure tests %s", strings.Join(testCase.StructureTests, " ")),
+			runner: structure.NewRunner(files),
+		})
+	}
+
+	for _, customTest := range testCase.CustomTests {
+		runners = append(runners, namedRunner{
+			name:   customTest.Command,
+			runner: custom.NewRunner(customTest, t.workingDir),
+		})
 	}
 
-	files, err := util.ExpandPathsGlob(t.workingDir, testCase.StructureTests)
+	return runners, nil
+}
+
+// runTest runs a single test on an image and records its result.
+// The output of the test is both streamed to out and captured in the result.
+func runTest(ctx context.Context, out io.Writer, imageName, fqn string, r namedRunner) Result {
+	event.TestInProgress(imageName, r.name)
+
+	var output bytes.Buffer
+	start := time.Now()
+	err := r.runner.Test(ctx, io.MultiWriter(out, &output), fqn)
+	duration := time.Since(start)
+
 	if err != nil {
-		return errors.Wrap(err, "expanding test file paths")
+		event.TestFailed(imageName, r.name, duration, err)
+	} else {
+		event.TestComplete(imageName, r.name, duration)
 	}
 
-	fqn := resolveArtifactImageTag(testCase.ImageName, bRes)
+	return Result{
+		Artifact: imageName,
+		Name:     r.name,
+		Duration: duration,
+		Err:      err,
+		Output:   output.String(),
+	}
+}
+
+// failures returns an error summarizing all the failed tests, if any.
+func failures(results []Result) error {
+	var failed []string
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s): %s", result.Name, result.Artifact, result.Err))
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
 
-	runner := structure.NewRunner(files)
-	return runner.Test(ctx, out, fqn)
+	return fmt.Errorf("%d of %d tests failed:\n%s", len(failed), len(results), strings.Join(failed, "\n"))
 }
 
 func resolveArtifactImageTag(imageName string, bRes []build.Artifact) string {
@@ -18,11 +18,15 @@ package test
 
 import (
 	"context"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"io/ioutil"
 	"testing"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
@@ -31,7 +35,8 @@ import (
 
 func TestNoTestDependencies(t *testing.T) {
 	runCtx := &runcontext.RunContext{
-		Cfg: &latest.Pipeline{},
+		Opts: &config.SkaffoldOptions{},
+		Cfg:  &latest.Pipeline{},
 	}
 
 	deps, err := NewTester(runCtx).TestDependencies()
@@ -48,6 +53,7 @@ func TestTestDependencies(t *testing.T) {
 	tmpDir.Write("test3.yaml", "")
 
 	runCtx := &runcontext.RunContext{
+		Opts:       &config.SkaffoldOptions{},
 		WorkingDir: tmpDir.Root(),
 		Cfg: &latest.Pipeline{
 			Test: []*latest.TestCase{
@@ -66,9 +72,11 @@ func TestTestDependencies(t *testing.T) {
 
 func TestNoTest(t *testing.T) {
 	runCtx := &runcontext.RunContext{
-		Cfg: &latest.Pipeline{},
+		Opts: &config.SkaffoldOptions{},
+		Cfg:  &latest.Pipeline{},
 	}
 
+	event.InitializeState(runCtx)
 	err := NewTester(runCtx).Test(context.Background(), ioutil.Discard, nil)
 
 	testutil.CheckError(t, false, err)
@@ -89,6 +97,7 @@ func TestTestSuccess(t *testing.T) {
 		WithRun("container-structure-test test -v warn --image TAG --config " + tmpDir.Path("test3.yaml"))
 
 	runCtx := &runcontext.RunContext{
+		Opts:       &config.SkaffoldOptions{},
 		WorkingDir: tmpDir.Root(),
 		Cfg: &latest.Pipeline{
 			Test: []*latest.TestCase{
@@ -105,6 +114,7 @@ func TestTestSuccess(t *testing.T) {
 		},
 	}
 
+	event.InitializeState(runCtx)
 	err := NewTester(runCtx).Test(context.Background(), ioutil.Discard, []build.Artifact{{
 		ImageName: "image",
 		Tag:       "TAG",
@@ -125,6 +135,7 @@ func TestTestFailure(t *testing.T) {
 		WithRunErr("container-structure-test test -v warn --image broken-image --config "+tmpDir.Path("test.yaml"), errors.New("FAIL"))
 
 	runCtx := &runcontext.RunContext{
+		Opts:       &config.SkaffoldOptions{},
 		WorkingDir: tmpDir.Root(),
 		Cfg: &latest.Pipeline{
 			Test: []*latest.TestCase{
@@ -136,7 +147,200 @@ func TestTestFailure(t *testing.T) {
 		},
 	}
 
+	event.InitializeState(runCtx)
 	err := NewTester(runCtx).Test(context.Background(), ioutil.Discard, []build.Artifact{{}})
 
 	testutil.CheckError(t, true, err)
 }
+
+func TestCustomTestDependencies(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	tmpDir.Write("tests/contract.sh", "")
+	tmpDir.Write("tests/fixtures/data.json", "")
+	tmpDir.Write("tests/fixtures/ignored.tmp", "")
+
+	runCtx := &runcontext.RunContext{
+		Opts:       &config.SkaffoldOptions{},
+		WorkingDir: tmpDir.Root(),
+		Cfg: &latest.Pipeline{
+			Test: []*latest.TestCase{
+				{CustomTests: []*latest.CustomTest{{
+					Command: "./tests/contract.sh",
+					Dependencies: &latest.CustomTestDependencies{
+						Paths:  []string{"tests"},
+						Ignore: []string{"**/*.tmp"},
+					},
+				}}},
+				{CustomTests: []*latest.CustomTest{{Command: "true"}}},
+			},
+		},
+	}
+
+	deps, err := NewTester(runCtx).TestDependencies()
+
+	expectedDeps := tmpDir.Paths("tests/contract.sh", "tests/fixtures/data.json")
+	testutil.CheckErrorAndDeepEqual(t, false, err, expectedDeps, deps)
+}
+
+func TestCustomTestSuccess(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = testutil.
+		NewFakeCmd(t).
+		WithRun("sh -c ./unit-tests.sh").
+		WithRun("sh -c ./contract-tests.sh")
+
+	runCtx := &runcontext.RunContext{
+		Opts: &config.SkaffoldOptions{},
+		Cfg: &latest.Pipeline{
+			Test: []*latest.TestCase{
+				{
+					ImageName: "image",
+					CustomTests: []*latest.CustomTest{
+						{Command: "./unit-tests.sh"},
+						{Command: "./contract-tests.sh", TimeoutSeconds: 10},
+					},
+				},
+			},
+		},
+	}
+
+	event.InitializeState(runCtx)
+	err := NewTester(runCtx).Test(context.Background(), ioutil.Discard, []build.Artifact{{
+		ImageName: "image",
+		Tag:       "TAG",
+	}})
+
+	testutil.CheckError(t, false, err)
+}
+
+func TestCustomTestFailure(t *testing.T) {
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = testutil.FakeRunErr(t, "sh -c ./failing.sh", errors.New("FAIL"))
+
+	runCtx := &runcontext.RunContext{
+		Opts: &config.SkaffoldOptions{},
+		Cfg: &latest.Pipeline{
+			Test: []*latest.TestCase{
+				{
+					ImageName:   "image",
+					CustomTests: []*latest.CustomTest{{Command: "./failing.sh"}},
+				},
+			},
+		},
+	}
+
+	event.InitializeState(runCtx)
+	err := NewTester(runCtx).Test(context.Background(), ioutil.Discard, []build.Artifact{{
+		ImageName: "image",
+		Tag:       "TAG",
+	}})
+
+	testutil.CheckError(t, true, err)
+}
+
+func TestTestContinuesAfterFailureAndWritesReports(t *testing.T) {
+	tmpDir, cleanup := testutil.NewTempDir(t)
+	defer cleanup()
+
+	defer func(c util.Command) { util.DefaultExecCommand = c }(util.DefaultExecCommand)
+	util.DefaultExecCommand = testutil.
+		NewFakeCmd(t).
+		WithRunErr("sh -c ./failing.sh", errors.New("FAIL")).
+		WithRun("sh -c ./passing.sh").
+		WithRun("sh -c ./other.sh")
+
+	runCtx := &runcontext.RunContext{
+		Opts: &config.SkaffoldOptions{
+			TestReportDir: tmpDir.Path("reports"),
+		},
+		Cfg: &latest.Pipeline{
+			Test: []*latest.TestCase{
+				{
+					ImageName: "image1",
+					CustomTests: []*latest.CustomTest{
+						{Command: "./failing.sh"},
+						{Command: "./passing.sh"},
+					},
+				},
+				{
+					ImageName:   "image2",
+					CustomTests: []*latest.CustomTest{{Command: "./other.sh"}},
+				},
+			},
+		},
+	}
+
+	event.InitializeState(runCtx)
+	err := NewTester(runCtx).Test(context.Background(), ioutil.Discard, []build.Artifact{
+		{ImageName: "image1", Tag: "TAG1"},
+		{ImageName: "image2", Tag: "TAG2"},
+	})
+
+	testutil.CheckError(t, true, err)
+
+	var junit junitTestSuites
+	content, err := ioutil.ReadFile(tmpDir.Path("reports/" + JUnitReport))
+	testutil.CheckError(t, false, err)
+	testutil.CheckError(t, false, xml.Unmarshal(content, &junit))
+	testutil.CheckDeepEqual(t, 3, junit.Tests)
+	testutil.CheckDeepEqual(t, 1, junit.Failures)
+	testutil.CheckDeepEqual(t, 2, len(junit.Suites))
+	testutil.CheckDeepEqual(t, "image1", junit.Suites[0].Name)
+	testutil.CheckDeepEqual(t, "./failing.sh", junit.Suites[0].Cases[0].Name)
+	testutil.CheckDeepEqual(t, `running custom test command "./failing.sh": FAIL`, junit.Suites[0].Cases[0].Failure.Message)
+	testutil.CheckDeepEqual(t, (*junitFailure)(nil), junit.Suites[0].Cases[1].Failure)
+	testutil.CheckDeepEqual(t, "image2", junit.Suites[1].Name)
+
+	var report jsonReport
+	content, err = ioutil.ReadFile(tmpDir.Path("reports/" + JSONReport))
+	testutil.CheckError(t, false, err)
+	testutil.CheckError(t, false, json.Unmarshal(content, &report))
+	testutil.CheckDeepEqual(t, 2, report.Passed)
+	testutil.CheckDeepEqual(t, 1, report.Failed)
+	testutil.CheckDeepEqual(t, []jsonResult{
+		{Artifact: "image1", Name: "./failing.sh", DurationSeconds: report.Tests[0].DurationSeconds, Error: `running custom test command "./failing.sh": FAIL`},
+		{Artifact: "image1", Name: "./passing.sh", DurationSeconds: report.Tests[1].DurationSeconds, Passed: true},
+		{Artifact: "image2", Name: "./other.sh", DurationSeconds: report.Tests[2].DurationSeconds, Passed: true},
+	}, report.Tests)
+}
+
+func TestTestWritesReportsWhenTestsCannotBeSetUp(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir()
+		t.Override(&util.DefaultExecCommand, testutil.NewFakeCmd(t.T).WithRun("sh -c ./passing.sh"))
+
+		runCtx := &runcontext.RunContext{
+			WorkingDir: tmpDir.Root(),
+			Opts: &config.SkaffoldOptions{
+				TestReportDir: tmpDir.Path("reports"),
+			},
+			Cfg: &latest.Pipeline{
+				Test: []*latest.TestCase{
+					{
+						ImageName:      "image1",
+						StructureTests: []string{"[invalid"},
+					},
+					{
+						ImageName:   "image2",
+						CustomTests: []*latest.CustomTest{{Command: "./passing.sh"}},
+					},
+				},
+			},
+		}
+
+		event.InitializeState(runCtx)
+		err := NewTester(runCtx).Test(context.Background(), ioutil.Discard, nil)
+		t.CheckErrorContains("1 of 2 tests failed", err)
+
+		var report jsonReport
+		content, err := ioutil.ReadFile(tmpDir.Path("reports/" + JSONReport))
+		t.CheckError(false, err)
+		t.CheckError(false, json.Unmarshal(content, &report))
+		t.CheckDeepEqual(1, report.Passed)
+		t.CheckDeepEqual(1, report.Failed)
+		t.CheckDeepEqual("tests of image1", report.Tests[0].Name)
+		t.CheckContains("expanding test file paths", report.Tests[0].Error)
+	})
+}
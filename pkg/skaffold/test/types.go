@@ -19,6 +19,7 @@ package test
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
@@ -44,6 +45,21 @@ type Tester interface {
 type FullTester struct {
 	testCases  []*latest.TestCase
 	workingDir string
+	reportDir  string
+}
+
+// Result is the outcome of a single test run on an artifact.
+type Result struct {
+	Artifact string
+	Name     string
+	Duration time.Duration
+	Err      error
+	Output   string
+}
+
+type namedRunner struct {
+	name   string
+	runner Runner
 }
 
 // Runner is the lowest-level test executor in Skaffold, responsible for
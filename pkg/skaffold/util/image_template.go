@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+)
+
+// ImageTemplateData is what image templates are evaluated against.
+type ImageTemplateData struct {
+	// Image is the full reference to the image. For example: `gcr.io/project/app:v1@sha256:...`
+	Image string
+
+	// Registry is the registry part of the reference. For example: `gcr.io`
+	Registry string
+
+	// Repository is the path of the image in the registry. For example: `project/app`
+	Repository string
+
+	// Tag is the tag of the image, if any. For example: `v1`
+	Tag string
+
+	// Digest is the digest of the image, if any. For example: `sha256:...`
+	Digest string
+}
+
+// NewImageTemplateData splits an image reference into the fields available to image templates.
+func NewImageTemplateData(image string) (ImageTemplateData, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ImageTemplateData{}, errors.Wrapf(err, "parsing image reference %s", image)
+	}
+
+	data := ImageTemplateData{
+		Image:      image,
+		Registry:   reference.Domain(named),
+		Repository: reference.Path(named),
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		data.Tag = tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		data.Digest = digested.Digest().String()
+	}
+
+	return data, nil
+}
+
+// ParseImageTemplate parses an image template and checks that it only uses known fields.
+func ParseImageTemplate(t string) (*template.Template, error) {
+	tmpl, err := template.New("imageTemplate").Parse(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := ExecuteImageTemplate(tmpl, ImageTemplateData{}); err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// ExecuteImageTemplate evaluates an image template for a given image.
+func ExecuteImageTemplate(imageTemplate *template.Template, data ImageTemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := imageTemplate.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "executing template")
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+const testDigest = "sha256:4bf0b5ebb2ba63d0e2c7b3e4ca5da30a4c2b9f0d2fca1a5ea1c5e5e3c2d4c1d3"
+
+func TestNewImageTemplateData(t *testing.T) {
+	tests := []struct {
+		description string
+		image       string
+		expected    ImageTemplateData
+		shouldErr   bool
+	}{
+		{
+			description: "tag and digest",
+			image:       "gcr.io/project/app:v1@" + testDigest,
+			expected: ImageTemplateData{
+				Image:      "gcr.io/project/app:v1@" + testDigest,
+				Registry:   "gcr.io",
+				Repository: "project/app",
+				Tag:        "v1",
+				Digest:     testDigest,
+			},
+		},
+		{
+			description: "registry with port and tag",
+			image:       "localhost:5000/app:v1",
+			expected: ImageTemplateData{
+				Image:      "localhost:5000/app:v1",
+				Registry:   "localhost:5000",
+				Repository: "app",
+				Tag:        "v1",
+			},
+		},
+		{
+			description: "docker hub image",
+			image:       "app:v1",
+			expected: ImageTemplateData{
+				Image:      "app:v1",
+				Registry:   "docker.io",
+				Repository: "library/app",
+				Tag:        "v1",
+			},
+		},
+		{
+			description: "invalid reference",
+			image:       "Invalid:Image",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			data, err := NewImageTemplateData(test.image)
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, data)
+		})
+	}
+}
+
+func TestImageTemplate(t *testing.T) {
+	tests := []struct {
+		description string
+		template    string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "known fields",
+			template:    "{{.Registry}}/{{.Repository}}@{{.Digest}}",
+			expected:    "gcr.io/project/app@" + testDigest,
+		},
+		{
+			description: "unknown field",
+			template:    "{{.Name}}",
+			shouldErr:   true,
+		},
+		{
+			description: "invalid template",
+			template:    "{{.Tag",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			data, err := NewImageTemplateData("gcr.io/project/app:v1@" + testDigest)
+			t.CheckError(false, err)
+
+			var result string
+			tmpl, err := ParseImageTemplate(test.template)
+			if err == nil {
+				result, err = ExecuteImageTemplate(tmpl, data)
+			}
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, result)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"context"
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// Verifier runs the post-deploy verification phase: integration tests
+// that run in the cluster against the deployed services.
+type Verifier interface {
+	Verify(context.Context, io.Writer, []build.Artifact, []deploy.Labeller) error
+}
+
+// JobVerifier runs each verification test as a Kubernetes Job,
+// streams its logs and waits for it to complete.
+type JobVerifier struct {
+	testCases []*latest.VerifyTestCase
+	namespace string
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/event"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	kubectx "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/context"
+	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultTimeout = 10 * time.Minute
+
+	// verifyLabel identifies the Jobs created by the verification phase.
+	verifyLabel = "skaffold.dev/verify"
+)
+
+// NewVerifier returns a Verifier that runs the verification tests
+// listed in the Skaffold config.
+func NewVerifier(runCtx *runcontext.RunContext) Verifier {
+	return &JobVerifier{
+		testCases: runCtx.Cfg.Verify,
+		namespace: runCtx.Opts.Namespace,
+	}
+}
+
+// Verify runs every verification test as a Kubernetes Job and
+// returns an error if any of them fails.
+func (v *JobVerifier) Verify(ctx context.Context, out io.Writer, builds []build.Artifact, labellers []deploy.Labeller) error {
+	if len(v.testCases) == 0 {
+		return nil
+	}
+
+	client, err := kubernetes.Client()
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+
+	start := time.Now()
+	color.Default.Fprintln(out, "Starting verification...")
+
+	var failed []string
+	for _, tc := range v.testCases {
+		event.VerifyInProgress(tc.Name)
+
+		if err := v.runTestCase(ctx, out, client, tc, builds, labellers); err != nil {
+			event.VerifyFailed(tc.Name, err)
+			color.Red.Fprintf(out, " - %s failed: %s\n", tc.Name, err)
+			failed = append(failed, tc.Name)
+			continue
+		}
+
+		event.VerifyComplete(tc.Name)
+		color.Green.Fprintf(out, " - %s succeeded\n", tc.Name)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("verification tests failed: %s", strings.Join(failed, ", "))
+	}
+
+	color.Default.Fprintln(out, "Verification complete in", time.Since(start))
+	return nil
+}
+
+func (v *JobVerifier) runTestCase(ctx context.Context, out io.Writer, client k8s.Interface, tc *latest.VerifyTestCase, builds []build.Artifact, labellers []deploy.Labeller) error {
+	ns, err := v.resolveNamespace(tc)
+	if err != nil {
+		return errors.Wrap(err, "resolving namespace")
+	}
+
+	jobs := client.BatchV1().Jobs(ns)
+	job, err := jobs.Create(jobSpec(tc, builds, labellers))
+	if err != nil {
+		return errors.Wrapf(err, "creating job for %s", tc.Name)
+	}
+	defer func() {
+		propagation := meta_v1.DeletePropagationBackground
+		if err := jobs.Delete(job.Name, &meta_v1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+			logrus.Warnf("deleting job %s: %s", job.Name, err)
+		}
+	}()
+
+	logger := kubernetes.NewLogAggregator(out, []string{tc.Image}, &jobPodSelector{jobName: job.Name}, []string{ns})
+	if err := logger.Start(ctx); err != nil {
+		return errors.Wrap(err, "starting logger")
+	}
+	defer logger.Stop()
+
+	timeout := defaultTimeout
+	if tc.TimeoutSeconds > 0 {
+		timeout = time.Duration(tc.TimeoutSeconds) * time.Second
+	}
+
+	return kubernetes.WaitForJobToComplete(ctx, client, ns, job.Name, timeout)
+}
+
+func (v *JobVerifier) resolveNamespace(tc *latest.VerifyTestCase) (string, error) {
+	if tc.Namespace != "" {
+		return tc.Namespace, nil
+	}
+	if v.namespace != "" {
+		return v.namespace, nil
+	}
+
+	cfg, err := kubectx.CurrentConfig()
+	if err != nil {
+		return "", errors.Wrap(err, "getting kubeconfig")
+	}
+
+	current, present := cfg.Contexts[cfg.CurrentContext]
+	if present && current.Namespace != "" {
+		return current.Namespace, nil
+	}
+	return "default", nil
+}
+
+// jobSpec creates the Job that runs a verification test, using the freshly
+// built image if the test's image is one of the artifacts.
+func jobSpec(tc *latest.VerifyTestCase, builds []build.Artifact, labellers []deploy.Labeller) *batchv1.Job {
+	jobLabels := map[string]string{}
+	for _, labeller := range labellers {
+		jobLabels = labels.Merge(jobLabels, labeller.Labels())
+	}
+	jobLabels[verifyLabel] = tc.Name
+
+	var env []v1.EnvVar
+	for _, kv := range tc.Env {
+		parts := strings.SplitN(kv, "=", 2)
+		envVar := v1.EnvVar{Name: parts[0]}
+		if len(parts) == 2 {
+			envVar.Value = parts[1]
+		}
+		env = append(env, envVar)
+	}
+
+	backoffLimit := int32(0)
+
+	return &batchv1.Job{
+		ObjectMeta: meta_v1.ObjectMeta{
+			GenerateName: tc.Name + "-",
+			Labels:       jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: v1.PodSpec{
+					RestartPolicy: v1.RestartPolicyNever,
+					Containers: []v1.Container{{
+						Name:    tc.Name,
+						Image:   resolveImage(tc.Image, builds),
+						Command: tc.Command,
+						Args:    tc.Args,
+						Env:     env,
+					}},
+				},
+			},
+		},
+	}
+}
+
+func resolveImage(image string, builds []build.Artifact) string {
+	for _, b := range builds {
+		if b.ImageName == image {
+			return b.Tag
+		}
+	}
+	return image
+}
+
+// jobPodSelector selects the pods created for a given Job.
+type jobPodSelector struct {
+	jobName string
+}
+
+func (s *jobPodSelector) Select(pod *v1.Pod) bool {
+	return pod.Labels["job-name"] == s.jobName
+}
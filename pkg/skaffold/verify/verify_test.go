@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+	v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeLabeller map[string]string
+
+func (l fakeLabeller) Labels() map[string]string { return l }
+
+func TestNoVerification(t *testing.T) {
+	verifier := &JobVerifier{}
+
+	err := verifier.Verify(context.Background(), ioutil.Discard, nil, nil)
+
+	testutil.CheckError(t, false, err)
+}
+
+func TestJobSpec(t *testing.T) {
+	tc := &latest.VerifyTestCase{
+		Name:    "integration",
+		Image:   "gcr.io/k8s-skaffold/tests",
+		Command: []string{"go", "test"},
+		Args:    []string{"./..."},
+		Env:     []string{"API_URL=http://api:8080", "DEBUG"},
+	}
+	builds := []build.Artifact{{
+		ImageName: "gcr.io/k8s-skaffold/tests",
+		Tag:       "gcr.io/k8s-skaffold/tests:TAG",
+	}}
+
+	job := jobSpec(tc, builds, []deploy.Labeller{fakeLabeller{"run-id": "abc"}})
+
+	testutil.CheckDeepEqual(t, "integration-", job.GenerateName)
+	testutil.CheckDeepEqual(t, map[string]string{"run-id": "abc", verifyLabel: "integration"}, job.Labels)
+	testutil.CheckDeepEqual(t, int32(0), *job.Spec.BackoffLimit)
+	testutil.CheckDeepEqual(t, v1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	testutil.CheckDeepEqual(t, []v1.Container{{
+		Name:    "integration",
+		Image:   "gcr.io/k8s-skaffold/tests:TAG",
+		Command: []string{"go", "test"},
+		Args:    []string{"./..."},
+		Env: []v1.EnvVar{
+			{Name: "API_URL", Value: "http://api:8080"},
+			{Name: "DEBUG"},
+		},
+	}}, job.Spec.Template.Spec.Containers)
+}
+
+func TestResolveImage(t *testing.T) {
+	builds := []build.Artifact{{ImageName: "image", Tag: "image:TAG"}}
+
+	testutil.CheckDeepEqual(t, "image:TAG", resolveImage("image", builds))
+	testutil.CheckDeepEqual(t, "busybox", resolveImage("busybox", builds))
+}
+
+func TestJobPodSelector(t *testing.T) {
+	selector := &jobPodSelector{jobName: "integration-x7k2p"}
+
+	testutil.CheckDeepEqual(t, true, selector.Select(&v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Labels: map[string]string{"job-name": "integration-x7k2p"}}}))
+	testutil.CheckDeepEqual(t, false, selector.Select(&v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Labels: map[string]string{"job-name": "other"}}}))
+	testutil.CheckDeepEqual(t, false, selector.Select(&v1.Pod{}))
+}
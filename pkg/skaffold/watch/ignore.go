@@ -0,0 +1,180 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/pkg/errors"
+)
+
+// Ignorer tells which files should not be watched.
+// Each rule is a set of patterns relative to a root directory.
+type Ignorer []ignoreRule
+
+type ignoreRule struct {
+	root    string
+	matcher *fileutils.PatternMatcher
+}
+
+// WithPatterns returns an Ignorer that also ignores the files matching the given
+// patterns. Patterns use the `.dockerignore` syntax and are relative to root.
+func (i Ignorer) WithPatterns(root string, patterns []string) (Ignorer, error) {
+	if len(patterns) == 0 {
+		return i, nil
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving %s", root)
+	}
+
+	matcher, err := fileutils.NewPatternMatcher(patterns)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ignore patterns")
+	}
+
+	// Never share the backing array between Ignorers.
+	return append(i[:len(i):len(i)], ignoreRule{root: absRoot, matcher: matcher}), nil
+}
+
+// WithGitIgnore returns an Ignorer that also ignores the files listed
+// in the `.gitignore` file of a directory, if there's one.
+func (i Ignorer) WithGitIgnore(dir string) (Ignorer, error) {
+	patterns, err := readGitIgnore(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil, err
+	}
+
+	return i.WithPatterns(dir, patterns)
+}
+
+// Ignored tells if a file, or one of its parent directories, is ignored.
+func (i Ignorer) Ignored(path string) bool {
+	if len(i) == 0 {
+		return false
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	for _, rule := range i {
+		if rule.ignores(absPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *ignoreRule) ignores(absPath string) bool {
+	rel, err := filepath.Rel(r.root, absPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+
+	// Check the file and all its parents, so that ignoring a directory ignores its content.
+	parts := strings.Split(rel, string(filepath.Separator))
+	for n := 1; n <= len(parts); n++ {
+		if matched, err := r.matcher.Matches(filepath.Join(parts[:n]...)); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter removes the ignored files from the files listed by deps.
+func (i Ignorer) Filter(deps func() ([]string, error)) func() ([]string, error) {
+	if len(i) == 0 {
+		return deps
+	}
+
+	return func() ([]string, error) {
+		paths, err := deps()
+		if err != nil {
+			return nil, err
+		}
+
+		var filtered []string
+		for _, path := range paths {
+			if !i.Ignored(path) {
+				filtered = append(filtered, path)
+			}
+		}
+		return filtered, nil
+	}
+}
+
+// readGitIgnore translates the patterns of a `.gitignore` file into `.dockerignore` patterns.
+func readGitIgnore(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if pattern := gitIgnorePattern(scanner.Text()); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+
+	return patterns, nil
+}
+
+// gitIgnorePattern translates a line of a `.gitignore` file.
+// A pattern without a slash matches at any depth and a leading
+// slash anchors a pattern to the directory of the `.gitignore`.
+func gitIgnorePattern(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ""
+	}
+
+	negation := ""
+	if strings.HasPrefix(line, "!") {
+		negation = "!"
+		line = line[1:]
+	}
+
+	line = strings.TrimSuffix(line, "/")
+	switch {
+	case line == "":
+		return ""
+	case strings.HasPrefix(line, "/"):
+		line = strings.TrimPrefix(line, "/")
+	case !strings.Contains(line, "/") && !strings.HasPrefix(line, "**"):
+		line = "**/" + line
+	}
+
+	return negation + line
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestIgnored(t *testing.T) {
+	var tests = []struct {
+		description string
+		patterns    []string
+		gitIgnore   string
+		path        string
+		expected    bool
+	}{
+		{
+			description: "no patterns",
+			path:        "src/main.go",
+		},
+		{
+			description: "ignored file",
+			patterns:    []string{"*.log"},
+			path:        "debug.log",
+			expected:    true,
+		},
+		{
+			description: "ignored directory",
+			patterns:    []string{"target"},
+			path:        "target/classes/Main.class",
+			expected:    true,
+		},
+		{
+			description: "ignored at any depth",
+			patterns:    []string{"**/node_modules"},
+			path:        "web/node_modules/lib/index.js",
+			expected:    true,
+		},
+		{
+			description: "anchored pattern",
+			patterns:    []string{"build"},
+			path:        "src/build/file",
+		},
+		{
+			description: "excluded pattern",
+			patterns:    []string{"*.log", "!keep.log"},
+			path:        "keep.log",
+		},
+		{
+			description: "outside of the root",
+			patterns:    []string{"**"},
+			path:        "../other/file",
+		},
+		{
+			description: "gitignore matches at any depth",
+			gitIgnore:   "# generated\nnode_modules/\n",
+			path:        "web/node_modules/lib/index.js",
+			expected:    true,
+		},
+		{
+			description: "anchored gitignore pattern",
+			gitIgnore:   "/build\n",
+			path:        "src/build/file",
+		},
+		{
+			description: "gitignore negation",
+			gitIgnore:   "*.log\n!keep.log\n",
+			path:        "logs/keep.log",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir()
+			if test.gitIgnore != "" {
+				tmpDir.Write(".gitignore", test.gitIgnore)
+			}
+
+			ignorer, err := Ignorer{}.WithPatterns(tmpDir.Root(), test.patterns)
+			t.CheckError(false, err)
+			ignorer, err = ignorer.WithGitIgnore(tmpDir.Root())
+			t.CheckError(false, err)
+
+			t.CheckDeepEqual(test.expected, ignorer.Ignored(tmpDir.Path(test.path)))
+		})
+	}
+}
+
+func TestIgnoredInvalidPattern(t *testing.T) {
+	_, err := Ignorer{}.WithPatterns(".", []string{"["})
+
+	testutil.CheckError(t, true, err)
+}
+
+func TestFilter(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir()
+
+		ignorer, err := Ignorer{}.WithPatterns(tmpDir.Root(), []string{"target"})
+		t.CheckError(false, err)
+
+		deps := ignorer.Filter(func() ([]string, error) {
+			return tmpDir.Paths("pom.xml", "src/Main.java", "target/Main.class"), nil
+		})
+		paths, err := deps()
+
+		t.CheckErrorAndDeepEqual(false, err, tmpDir.Paths("pom.xml", "src/Main.java"), paths)
+	})
+}
+
+func TestWithPatternsDoesntShareRules(t *testing.T) {
+	base, err := Ignorer{}.WithPatterns(".", []string{"a"})
+	testutil.CheckError(t, false, err)
+
+	first, _ := base.WithPatterns(".", []string{"b"})
+	second, _ := base.WithPatterns(".", []string{"c"})
+
+	testutil.CheckDeepEqual(t, true, first.Ignored("b"))
+	testutil.CheckDeepEqual(t, false, second.Ignored("b"))
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// buildFiles are the names of the files that describe how to build or deploy
+// and that can change the list of dependencies of a component.
+var buildFiles = map[string]bool{
+	".dockerignore":       true,
+	"BUILD":               true,
+	"BUILD.bazel":         true,
+	"Chart.lock":          true,
+	"Chart.yaml":          true,
+	"WORKSPACE":           true,
+	"build.gradle":        true,
+	"build.gradle.kts":    true,
+	"go.mod":              true,
+	"kustomization.yaml":  true,
+	"kustomization.yml":   true,
+	"Kustomization":       true,
+	"package.json":        true,
+	"pom.xml":             true,
+	"requirements.lock":   true,
+	"requirements.yaml":   true,
+	"settings.gradle":     true,
+	"settings.gradle.kts": true,
+	"skaffold.yaml":       true,
+}
+
+// isBuildFile tells if a change to a file can change the list of dependencies.
+func isBuildFile(path string) bool {
+	name := filepath.Base(path)
+	return buildFiles[name] || strings.HasPrefix(name, "Dockerfile") || strings.HasSuffix(name, ".dockerfile") || strings.HasSuffix(name, ".bzl")
+}
+
+// maxOthers is how many files that are not dependencies a component remembers.
+const maxOthers = 1000
+
+type component struct {
+	deps     func() ([]string, error)
+	ignorer  Ignorer
+	onChange func(Events)
+	state    FileMap
+	events   Events
+
+	// index maps the absolute path of each dependency to the path listed by deps.
+	index map[string]string
+	// dirs are the absolute paths of the directories that contain dependencies.
+	dirs map[string]bool
+	// others are the absolute paths, under directories that contain dependencies,
+	// that are known not to be dependencies.
+	others map[string]bool
+}
+
+// setState records the current state of the component and indexes its dependencies.
+func (c *component) setState(state FileMap) {
+	c.state = state
+	c.index = make(map[string]string, len(state))
+	c.dirs = map[string]bool{}
+
+	for dep := range state {
+		abs, err := filepath.Abs(dep)
+		if err != nil {
+			logrus.Debugf("unable to index %s: %v", dep, err)
+			continue
+		}
+
+		c.index[abs] = dep
+		c.dirs[filepath.Dir(abs)] = true
+	}
+}
+
+// update computes the state of the component after some files have changed.
+// Ignored files are skipped and only the changed files are stat'ed. Dependencies
+// are listed again only when a build file changes, or when an unknown file under
+// a directory that contains dependencies changes. Such a file is looked at once:
+// if it's not a dependency, it's remembered until a build file changes.
+func (c *component) update(paths []string) (FileMap, error) {
+	var changed, unknown []string
+
+	for _, path := range paths {
+		if c.ignorer.Ignored(path) {
+			continue
+		}
+
+		if dep, found := c.index[path]; found {
+			if isBuildFile(path) {
+				c.others = nil
+				return Stat(c.deps)
+			}
+			changed = append(changed, dep)
+			continue
+		}
+
+		if !c.others[path] && c.watchesDir(path) {
+			unknown = append(unknown, path)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return c.relist(unknown)
+	}
+
+	if len(changed) == 0 {
+		return c.state, nil
+	}
+
+	state := make(FileMap, len(c.state))
+	for dep, modTime := range c.state {
+		state[dep] = modTime
+	}
+
+	for _, dep := range changed {
+		stat, err := os.Stat(dep)
+		if err != nil {
+			if os.IsNotExist(err) {
+				delete(state, dep)
+				continue
+			}
+			return nil, errors.Wrapf(err, "unable to stat file %s", dep)
+		}
+		state[dep] = stat.ModTime()
+	}
+
+	return state, nil
+}
+
+// relist lists the dependencies again and remembers which of the unknown paths
+// are not dependencies.
+func (c *component) relist(unknown []string) (FileMap, error) {
+	state, err := Stat(c.deps)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := make(map[string]bool, len(state))
+	for dep := range state {
+		if abs, err := filepath.Abs(dep); err == nil {
+			deps[abs] = true
+		}
+	}
+
+	// Forget about the files that are not dependencies rather than growing forever.
+	if c.others == nil || len(c.others) >= maxOthers {
+		c.others = map[string]bool{}
+	}
+	for _, path := range unknown {
+		if !deps[path] {
+			c.others[path] = true
+		}
+	}
+
+	return state, nil
+}
+
+// watchesDir tells if a path is in, or is, a directory that contains dependencies.
+// New dependencies can be created in new subdirectories, so all the parents are looked at.
+func (c *component) watchesDir(path string) bool {
+	if c.dirs[path] {
+		return true
+	}
+
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if c.dirs[dir] {
+			return true
+		}
+		if dir == filepath.Dir(dir) {
+			return false
+		}
+	}
+}
+
+// pathSet collects the paths that changed between two triggers.
+type pathSet struct {
+	lock  sync.Mutex
+	paths map[string]bool
+}
+
+func (s *pathSet) add(path string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.paths == nil {
+		s.paths = map[string]bool{}
+	}
+	s.paths[filepath.Clean(path)] = true
+}
+
+// flush returns the paths collected so far and forgets about them.
+func (s *pathSet) flush() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var paths []string
+	for path := range s.paths {
+		paths = append(paths, path)
+	}
+	s.paths = nil
+
+	return paths
+}
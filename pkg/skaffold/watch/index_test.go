@@ -0,0 +1,261 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestIsBuildFile(t *testing.T) {
+	var tests = []struct {
+		path     string
+		expected bool
+	}{
+		{path: "Dockerfile", expected: true},
+		{path: "app/Dockerfile.dev", expected: true},
+		{path: "app/web.dockerfile", expected: true},
+		{path: "rules.bzl", expected: true},
+		{path: "project/pom.xml", expected: true},
+		{path: "skaffold.yaml", expected: true},
+		{path: "main.go", expected: false},
+		{path: "src/index.html", expected: false},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.path, func(t *testutil.T) {
+			t.CheckDeepEqual(test.expected, isBuildFile(test.path))
+		})
+	}
+}
+
+func TestComponentUpdate(t *testing.T) {
+	var tests = []struct {
+		description     string
+		update          func(*testutil.TempDir) []string
+		expectedDeps    []string
+		expectedListing int
+	}{
+		{
+			description: "no change",
+			update: func(*testutil.TempDir) []string {
+				return nil
+			},
+			expectedDeps: []string{"Dockerfile", "src/main.go"},
+		},
+		{
+			description: "modified dependency",
+			update: func(tmpDir *testutil.TempDir) []string {
+				tmpDir.Chtimes("src/main.go", today)
+				return []string{tmpDir.Path("src/main.go")}
+			},
+			expectedDeps: []string{"Dockerfile", "src/main.go"},
+		},
+		{
+			description: "deleted dependency",
+			update: func(tmpDir *testutil.TempDir) []string {
+				tmpDir.Remove("src/main.go")
+				return []string{tmpDir.Path("src/main.go")}
+			},
+			expectedDeps: []string{"Dockerfile"},
+		},
+		{
+			description: "modified build file",
+			update: func(tmpDir *testutil.TempDir) []string {
+				tmpDir.Chtimes("Dockerfile", today)
+				return []string{tmpDir.Path("Dockerfile")}
+			},
+			expectedDeps:    []string{"Dockerfile", "src/main.go"},
+			expectedListing: 1,
+		},
+		{
+			description: "file added next to dependencies",
+			update: func(tmpDir *testutil.TempDir) []string {
+				tmpDir.Write("src/other.go", "")
+				return []string{tmpDir.Path("src/other.go")}
+			},
+			expectedDeps:    []string{"Dockerfile", "src/main.go", "src/other.go"},
+			expectedListing: 1,
+		},
+		{
+			description: "file added under a parent of dependencies",
+			update: func(tmpDir *testutil.TempDir) []string {
+				tmpDir.Write("other/lib/file.go", "")
+				return []string{tmpDir.Path("other/lib/file.go")}
+			},
+			expectedDeps:    []string{"Dockerfile", "other/lib/file.go", "src/main.go"},
+			expectedListing: 1,
+		},
+		{
+			description: "unrelated file",
+			update: func(tmpDir *testutil.TempDir) []string {
+				return []string{"/elsewhere/file"}
+			},
+			expectedDeps: []string{"Dockerfile", "src/main.go"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir().
+				Write("Dockerfile", "").
+				Write("src/main.go", "").
+				Chtimes("Dockerfile", yesterday).
+				Chtimes("src/main.go", yesterday)
+
+			listing := 0
+			c := &component{
+				deps: func() ([]string, error) {
+					listing++
+					return listFiles(tmpDir)
+				},
+			}
+
+			state, err := Stat(c.deps)
+			t.CheckError(false, err)
+			c.setState(state)
+			listing = 0
+
+			state, err = c.update(test.update(tmpDir))
+
+			var deps []string
+			for dep := range state {
+				deps = append(deps, dep)
+			}
+			sort.Strings(deps)
+
+			t.CheckErrorAndDeepEqual(false, err, tmpDir.Paths(test.expectedDeps...), deps)
+			t.CheckDeepEqual(test.expectedListing, listing)
+		})
+	}
+}
+
+func TestComponentUpdateRemembersOtherFiles(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("Dockerfile", "").
+			Write("README.md", "")
+
+		listing := 0
+		c := &component{
+			deps: func() ([]string, error) {
+				listing++
+				return []string{tmpDir.Path("Dockerfile")}, nil
+			},
+		}
+
+		state, err := Stat(c.deps)
+		t.CheckError(false, err)
+		c.setState(state)
+		listing = 0
+
+		// The first change to a file next to dependencies lists them again.
+		_, err = c.update([]string{tmpDir.Path("README.md")})
+		t.CheckError(false, err)
+		t.CheckDeepEqual(1, listing)
+
+		// It's now known not to be a dependency.
+		_, err = c.update([]string{tmpDir.Path("README.md")})
+		t.CheckError(false, err)
+		t.CheckDeepEqual(1, listing)
+
+		// Until a build file changes.
+		_, err = c.update([]string{tmpDir.Path("Dockerfile")})
+		t.CheckError(false, err)
+		_, err = c.update([]string{tmpDir.Path("README.md")})
+		t.CheckError(false, err)
+		t.CheckDeepEqual(3, listing)
+	})
+}
+
+func TestComponentOthersAreBounded(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		c := &component{
+			deps:   func() ([]string, error) { return nil, nil },
+			others: map[string]bool{},
+		}
+		for i := 0; i < maxOthers; i++ {
+			c.others[fmt.Sprintf("/other/%d", i)] = true
+		}
+
+		_, err := c.relist([]string{"/other/new"})
+
+		t.CheckError(false, err)
+		t.CheckDeepEqual(map[string]bool{"/other/new": true}, c.others)
+	})
+}
+
+func TestComponentUpdateSkipsIgnoredFiles(t *testing.T) {
+	testutil.Run(t, "", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("Dockerfile", "").
+			Write("node_modules/lib/package.json", "")
+
+		ignorer, err := Ignorer{}.WithPatterns(tmpDir.Root(), []string{"node_modules"})
+		t.CheckError(false, err)
+
+		listing := 0
+		c := &component{
+			deps: ignorer.Filter(func() ([]string, error) {
+				listing++
+				return listFiles(tmpDir)
+			}),
+			ignorer: ignorer,
+		}
+
+		state, err := Stat(c.deps)
+		t.CheckError(false, err)
+		c.setState(state)
+		listing = 0
+
+		state, err = c.update([]string{tmpDir.Path("node_modules"), tmpDir.Path("node_modules/lib/package.json")})
+
+		t.CheckErrorAndDeepEqual(false, err, c.state, state)
+		t.CheckDeepEqual(0, listing)
+	})
+}
+
+// listFiles lists the regular files of a temp directory.
+func listFiles(tmpDir *testutil.TempDir) ([]string, error) {
+	paths, err := tmpDir.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+func TestPathSet(t *testing.T) {
+	var set pathSet
+	set.add("/a/../b")
+	set.add("/b")
+	set.add("/c/")
+
+	paths := set.flush()
+	sort.Strings(paths)
+
+	testutil.CheckDeepEqual(t, []string{"/b", "/c"}, paths)
+	testutil.CheckDeepEqual(t, 0, len(set.flush()))
+}
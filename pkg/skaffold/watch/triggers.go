@@ -28,6 +28,8 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/pkg/errors"
 	"github.com/rjeczalik/notify"
 	"github.com/sirupsen/logrus"
 )
@@ -47,9 +49,7 @@ func NewTrigger(runctx *runcontext.RunContext) (Trigger, error) {
 			Interval: time.Duration(runctx.Opts.WatchPollInterval) * time.Millisecond,
 		}, nil
 	case "notify":
-		return &fsNotifyTrigger{
-			Interval: time.Duration(runctx.Opts.WatchPollInterval) * time.Millisecond,
-		}, nil
+		return newFSNotifyTrigger(runctx)
 	case "manual":
 		return &manualTrigger{}, nil
 	case "api":
@@ -139,6 +139,60 @@ func (t *manualTrigger) Start(ctx context.Context) (<-chan bool, error) {
 // notifyTrigger watches for changes with fsnotify
 type fsNotifyTrigger struct {
 	Interval time.Duration
+	// QuietPeriod is how long to wait without changes before triggering.
+	QuietPeriod time.Duration
+	// MaxWait, if set, forces a trigger when changes keep coming for that long.
+	MaxWait time.Duration
+	// MinInterval, if set, is the minimum time between two triggers.
+	MinInterval time.Duration
+
+	changes *pathSet
+}
+
+func newFSNotifyTrigger(runctx *runcontext.RunContext) (Trigger, error) {
+	interval := time.Duration(runctx.Opts.WatchPollInterval) * time.Millisecond
+
+	var cfg latest.WatchConfig
+	if runctx.Cfg != nil {
+		cfg = runctx.Cfg.Watch
+	}
+
+	quietPeriod, err := watchDuration("quiet period", runctx.Opts.WatchQuietPeriod, cfg.QuietPeriod, interval)
+	if err != nil {
+		return nil, err
+	}
+	maxWait, err := watchDuration("max wait", runctx.Opts.WatchMaxWait, cfg.MaxWait, 0)
+	if err != nil {
+		return nil, err
+	}
+	minInterval, err := watchDuration("min interval", runctx.Opts.WatchMinInterval, cfg.MinInterval, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fsNotifyTrigger{
+		Interval:    interval,
+		QuietPeriod: quietPeriod,
+		MaxWait:     maxWait,
+		MinInterval: minInterval,
+	}, nil
+}
+
+// watchDuration parses a duration set with a flag, or else in the configuration.
+func watchDuration(name, flag, config string, defaultValue time.Duration) (time.Duration, error) {
+	value := flag
+	if value == "" {
+		value = config
+	}
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing watch %s", name)
+	}
+	return duration, nil
 }
 
 // Debounce tells the watcher to not debounce rapid sequence of changes.
@@ -160,21 +214,32 @@ func (t *fsNotifyTrigger) Start(ctx context.Context) (<-chan bool, error) {
 		return nil, err
 	}
 
+	t.changes = &pathSet{}
+
 	trigger := make(chan bool)
 	go func() {
 		timer := time.NewTimer(1<<63 - 1) // Forever
+		var firstChange, lastTrigger time.Time
 
 		for {
 			select {
 			case e := <-c:
 				logrus.Debugln("Change detected", e)
+				t.changes.add(e.Path())
 
-				// Wait t.interval before triggering.
+				// Wait before triggering.
 				// This way, rapid stream of events will be grouped.
-				timer.Reset(t.Interval)
+				now := time.Now()
+				if firstChange.IsZero() {
+					firstChange = now
+				}
+				timer.Reset(t.delay(now, firstChange, lastTrigger))
 			case <-timer.C:
 				trigger <- true
+				firstChange = time.Time{}
+				lastTrigger = time.Now()
 			case <-ctx.Done():
+				notify.Stop(c)
 				timer.Stop()
 				return
 			}
@@ -184,6 +249,34 @@ func (t *fsNotifyTrigger) Start(ctx context.Context) (<-chan bool, error) {
 	return trigger, nil
 }
 
+// delay computes how long to wait before triggering, given the time
+// of the first change not yet triggered and the time of the last trigger.
+func (t *fsNotifyTrigger) delay(now, firstChange, lastTrigger time.Time) time.Duration {
+	delay := t.QuietPeriod
+
+	if t.MaxWait > 0 {
+		if untilMaxWait := firstChange.Add(t.MaxWait).Sub(now); untilMaxWait < delay {
+			delay = untilMaxWait
+		}
+	}
+
+	if t.MinInterval > 0 && !lastTrigger.IsZero() {
+		if untilMinInterval := lastTrigger.Add(t.MinInterval).Sub(now); untilMinInterval > delay {
+			delay = untilMinInterval
+		}
+	}
+
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// changedPaths returns the paths that changed since the last call.
+func (t *fsNotifyTrigger) changedPaths() []string {
+	return t.changes.flush()
+}
+
 type apiTrigger struct {
 	Trigger chan bool
 }
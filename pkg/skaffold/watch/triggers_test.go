@@ -23,13 +23,16 @@ import (
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	runcontext "github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/context"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/testutil"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestNewTrigger(t *testing.T) {
 	var tests = []struct {
 		description string
 		opts        *config.SkaffoldOptions
+		cfg         *latest.Pipeline
 		expected    Trigger
 		shouldErr   bool
 	}{
@@ -44,9 +47,43 @@ func TestNewTrigger(t *testing.T) {
 			description: "notify trigger",
 			opts:        &config.SkaffoldOptions{Trigger: "notify", WatchPollInterval: 1},
 			expected: &fsNotifyTrigger{
-				Interval: time.Duration(1) * time.Millisecond,
+				Interval:    time.Duration(1) * time.Millisecond,
+				QuietPeriod: time.Duration(1) * time.Millisecond,
+			},
+		},
+		{
+			description: "notify trigger configured in skaffold.yaml",
+			opts:        &config.SkaffoldOptions{Trigger: "notify", WatchPollInterval: 1},
+			cfg: &latest.Pipeline{Watch: latest.WatchConfig{
+				QuietPeriod: "200ms",
+				MaxWait:     "5s",
+				MinInterval: "10s",
+			}},
+			expected: &fsNotifyTrigger{
+				Interval:    time.Duration(1) * time.Millisecond,
+				QuietPeriod: 200 * time.Millisecond,
+				MaxWait:     5 * time.Second,
+				MinInterval: 10 * time.Second,
+			},
+		},
+		{
+			description: "flags override skaffold.yaml",
+			opts:        &config.SkaffoldOptions{Trigger: "notify", WatchPollInterval: 1, WatchQuietPeriod: "1s", WatchMaxWait: "2s"},
+			cfg: &latest.Pipeline{Watch: latest.WatchConfig{
+				QuietPeriod: "200ms",
+				MaxWait:     "5s",
+			}},
+			expected: &fsNotifyTrigger{
+				Interval:    time.Duration(1) * time.Millisecond,
+				QuietPeriod: time.Second,
+				MaxWait:     2 * time.Second,
 			},
 		},
+		{
+			description: "invalid duration",
+			opts:        &config.SkaffoldOptions{Trigger: "notify", WatchMinInterval: "often"},
+			shouldErr:   true,
+		},
 		{
 			description: "manual trigger",
 			opts:        &config.SkaffoldOptions{Trigger: "manual"},
@@ -67,11 +104,12 @@ func TestNewTrigger(t *testing.T) {
 		testutil.Run(t, test.description, func(t *testutil.T) {
 			runCtx := &runcontext.RunContext{
 				Opts: test.opts,
+				Cfg:  test.cfg,
 			}
 
 			got, err := NewTrigger(runCtx)
 
-			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, got)
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, got, cmp.AllowUnexported(fsNotifyTrigger{}))
 		})
 	}
 }
@@ -108,6 +146,65 @@ func TestNotifyTrigger_WatchForChanges(t *testing.T) {
 	testutil.CheckDeepEqual(t, want, got)
 }
 
+func TestNotifyTrigger_Delay(t *testing.T) {
+	now := time.Now()
+
+	var tests = []struct {
+		description string
+		trigger     *fsNotifyTrigger
+		firstChange time.Time
+		lastTrigger time.Time
+		expected    time.Duration
+	}{
+		{
+			description: "quiet period",
+			trigger:     &fsNotifyTrigger{QuietPeriod: time.Second},
+			firstChange: now.Add(-time.Minute),
+			expected:    time.Second,
+		},
+		{
+			description: "max wait is near",
+			trigger:     &fsNotifyTrigger{QuietPeriod: time.Second, MaxWait: 5 * time.Second},
+			firstChange: now.Add(-4500 * time.Millisecond),
+			expected:    500 * time.Millisecond,
+		},
+		{
+			description: "max wait is over",
+			trigger:     &fsNotifyTrigger{QuietPeriod: time.Second, MaxWait: 5 * time.Second},
+			firstChange: now.Add(-time.Minute),
+			expected:    0,
+		},
+		{
+			description: "last trigger is too recent",
+			trigger:     &fsNotifyTrigger{QuietPeriod: time.Second, MinInterval: 10 * time.Second},
+			firstChange: now,
+			lastTrigger: now.Add(-2 * time.Second),
+			expected:    8 * time.Second,
+		},
+		{
+			description: "last trigger is old enough",
+			trigger:     &fsNotifyTrigger{QuietPeriod: time.Second, MinInterval: 10 * time.Second},
+			firstChange: now,
+			lastTrigger: now.Add(-time.Minute),
+			expected:    time.Second,
+		},
+		{
+			description: "min interval wins over max wait",
+			trigger:     &fsNotifyTrigger{QuietPeriod: time.Second, MaxWait: 2 * time.Second, MinInterval: 10 * time.Second},
+			firstChange: now.Add(-time.Minute),
+			lastTrigger: now.Add(-5 * time.Second),
+			expected:    5 * time.Second,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			delay := test.trigger.delay(now, test.firstChange, test.lastTrigger)
+
+			t.CheckDeepEqual(test.expected, delay)
+		})
+	}
+}
+
 func TestManualTrigger_Debounce(t *testing.T) {
 	trigger := &manualTrigger{}
 	got, want := trigger.Debounce(), false
@@ -20,6 +20,7 @@ import (
 	"context"
 	"io"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/color"
 	"github.com/sirupsen/logrus"
 
 	"github.com/pkg/errors"
@@ -30,7 +31,7 @@ type Factory func() Watcher
 
 // Watcher monitors files changes for multiples components.
 type Watcher interface {
-	Register(deps func() ([]string, error), onChange func(Events)) error
+	Register(deps func() ([]string, error), ignorer Ignorer, onChange func(Events)) error
 	Run(ctx context.Context, out io.Writer, onChange func() error) error
 }
 
@@ -46,25 +47,30 @@ func NewWatcher(trigger Trigger) Watcher {
 	}
 }
 
-type component struct {
-	deps     func() ([]string, error)
-	onChange func(Events)
-	state    FileMap
-	events   Events
+// pathNotifier is implemented by triggers that know which paths have changed.
+type pathNotifier interface {
+	// changedPaths returns the absolute paths that changed since the last call.
+	changedPaths() []string
 }
 
 // Register adds a new component to the watch list.
-func (w *watchList) Register(deps func() ([]string, error), onChange func(Events)) error {
+// The files matched by the ignorer are neither dependencies nor looked at when they change.
+func (w *watchList) Register(deps func() ([]string, error), ignorer Ignorer, onChange func(Events)) error {
+	deps = ignorer.Filter(deps)
+
 	state, err := Stat(deps)
 	if err != nil {
 		return errors.Wrap(err, "listing files")
 	}
 
-	w.components = append(w.components, &component{
+	c := &component{
 		deps:     deps,
+		ignorer:  ignorer,
 		onChange: onChange,
-		state:    state,
-	})
+	}
+	c.setState(state)
+
+	w.components = append(w.components, c)
 	return nil
 }
 
@@ -96,9 +102,23 @@ func (w *watchList) Run(ctx context.Context, out io.Writer, onChange func() erro
 		case <-ctx.Done():
 			return nil
 		case <-t:
+			// Triggers that know which paths have changed let us look only at those paths.
+			// Otherwise, every dependency is listed and stat'ed again.
+			notifier, knowsPaths := w.trigger.(pathNotifier)
+			var paths []string
+			if knowsPaths {
+				paths = notifier.changedPaths()
+			}
+
 			changed := 0
 			for i, component := range w.components {
-				state, err := Stat(component.deps)
+				var state FileMap
+				var err error
+				if knowsPaths {
+					state, err = component.update(paths)
+				} else {
+					state, err = Stat(component.deps)
+				}
 				if err != nil {
 					return errors.Wrap(err, "listing files")
 				}
@@ -106,7 +126,7 @@ func (w *watchList) Run(ctx context.Context, out io.Writer, onChange func() erro
 
 				if e.HasChanged() {
 					changedComponents[i] = true
-					component.state = state
+					component.setState(state)
 					component.events = e
 					changed++
 				}
@@ -119,6 +139,7 @@ func (w *watchList) Run(ctx context.Context, out io.Writer, onChange func() erro
 			// the accumulated changes.
 			debounce := w.trigger.Debounce()
 			if (!debounce && changed > 0) || (debounce && changed == 0 && len(changedComponents) > 0) {
+				w.reportChanges(out, changedComponents)
 				for i, component := range w.components {
 					if changedComponents[i] {
 						component.onChange(component.events)
@@ -135,3 +156,26 @@ func (w *watchList) Run(ctx context.Context, out io.Writer, onChange func() erro
 		}
 	}
 }
+
+// reportChanges tells when several file changes are handled by a single iteration.
+func (w *watchList) reportChanges(out io.Writer, changedComponents map[int]bool) {
+	added, modified, deleted := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for i, component := range w.components {
+		if !changedComponents[i] {
+			continue
+		}
+		for _, path := range component.events.Added {
+			added[path] = true
+		}
+		for _, path := range component.events.Modified {
+			modified[path] = true
+		}
+		for _, path := range component.events.Deleted {
+			deleted[path] = true
+		}
+	}
+
+	if total := len(added) + len(modified) + len(deleted); total > 1 {
+		color.Default.Fprintf(out, "Coalesced %d file changes into one iteration (%d added, %d modified, %d deleted)\n", total, len(added), len(modified), len(deleted))
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package watch
 
 import (
+	"bytes"
 	"context"
 	"io/ioutil"
 	"sync"
@@ -72,7 +73,7 @@ func TestWatch(t *testing.T) {
 			watcher := NewWatcher(&pollTrigger{
 				Interval: 10 * time.Millisecond,
 			})
-			err := watcher.Register(folder.List, folderChanged.call)
+			err := watcher.Register(folder.List, nil, folderChanged.call)
 			t.CheckError(false, err)
 
 			// Run the watcher
@@ -96,6 +97,24 @@ func TestWatch(t *testing.T) {
 	}
 }
 
+func TestReportChanges(t *testing.T) {
+	w := &watchList{
+		components: []*component{
+			{events: Events{Added: []string{"a"}, Modified: []string{"b"}}},
+			{events: Events{Modified: []string{"b"}, Deleted: []string{"c"}}},
+			{events: Events{Modified: []string{"d"}}},
+		},
+	}
+
+	var out bytes.Buffer
+	w.reportChanges(&out, map[int]bool{0: true, 1: true})
+	testutil.CheckDeepEqual(t, "Coalesced 3 file changes into one iteration (1 added, 1 modified, 1 deleted)\n", out.String())
+
+	out.Reset()
+	w.reportChanges(&out, map[int]bool{2: true})
+	testutil.CheckDeepEqual(t, "", out.String())
+}
+
 type callback struct {
 	wg *sync.WaitGroup
 }